@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/usecase"
+	"github.com/Babushkin05/subscription-organizer/internal/config"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/delivery/bot"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/notification"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/repository/postgres"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/scheduler"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/telegram"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+)
+
+// Telegram bot: a separate process working with the same database as the API.
+func main() {
+	// Load config
+	cfg := config.MustLoad()
+	if cfg.Telegram.Token == "" {
+		log.Fatal("telegram.token is not set")
+	}
+
+	err := logger.Init(logger.Config{
+		Level:  cfg.LoggerConfig.Level,
+		Output: cfg.LoggerConfig.Output,
+	})
+	if err != nil {
+		log.Fatalf("Failed to initialize logger: %v", err)
+	}
+
+	// Connect to DB
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
+		cfg.DataBase.Host,
+		cfg.DataBase.Port,
+		cfg.DataBase.User,
+		cfg.DataBase.Password,
+		cfg.DataBase.Name)
+	db, err := sqlx.Connect("postgres", dsn)
+	if err != nil {
+		log.Fatalf("failed to connect to DB: %v", err)
+	}
+	defer db.Close()
+	logger.Log.Info("Connected to DB")
+
+	// Init repository
+	subRepo := postgres.NewSubscriptionRepository(db)
+	auditRepo := postgres.NewAuditRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	chatLinkRepo := postgres.NewChatLinkRepository(db)
+	jobRunRepo := postgres.NewJobRunRepository(db)
+	unitOfWork := postgres.NewUnitOfWork(db)
+
+	// Init service
+	client := telegram.NewClient(cfg.Telegram.APIURL, cfg.Telegram.Token, cfg.Telegram.Timeout)
+	subService := usecase.NewSubscriptionService(subRepo, auditRepo, outboxRepo, unitOfWork)
+	chatLinkService := usecase.NewChatLinkService(chatLinkRepo, cfg.Telegram.LinkTokenTTL)
+	// Email отправляет API, бот отвечает только за напоминания в Telegram
+	notificationService := usecase.NewNotificationService(notificationRepo, subRepo,
+		notification.NewTelegramChannel(client, chatLinkService))
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	jobs := scheduler.New(jobRunRepo, postgres.NewJobLocker(db))
+	err = jobs.Register(scheduler.Job{
+		Name:     "telegram-reminders",
+		Schedule: cfg.Jobs.Reminders,
+		Run:      scheduler.Counted("%d reminders sent", notificationService.SendReminders),
+	})
+	if err != nil {
+		log.Fatalf("failed to register job: %v", err)
+	}
+	scheduled := make(chan struct{})
+	go func() {
+		defer close(scheduled)
+		jobs.Run(ctx)
+	}()
+
+	logger.Log.Info("Starting bot")
+	bot.NewBot(client, subService, chatLinkService, notificationService, cfg.Telegram.PollTimeout).Run(ctx)
+	// Рассылка напоминаний могла быть в процессе, дожидаемся её
+	<-scheduled
+	logger.Log.Info("Bot stopped")
+}
@@ -1,22 +1,35 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"os/signal"
 	"strconv"
+	"sync"
+	"syscall"
 
 	_ "github.com/Babushkin05/subscription-organizer/docs"
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
 	"github.com/Babushkin05/subscription-organizer/internal/application/usecase"
 	"github.com/Babushkin05/subscription-organizer/internal/config"
+	graphqlService "github.com/Babushkin05/subscription-organizer/internal/infrastructure/delivery/graphql"
+	grpcService "github.com/Babushkin05/subscription-organizer/internal/infrastructure/delivery/grpc"
 	httpService "github.com/Babushkin05/subscription-organizer/internal/infrastructure/delivery/http"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/events"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/notification"
 	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/repository/postgres"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/scheduler"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/webhook"
 	"github.com/Babushkin05/subscription-organizer/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
 )
 
 func main() {
@@ -49,24 +62,189 @@ func main() {
 
 	// Init repository
 	subRepo := postgres.NewSubscriptionRepository(db)
+	idempotencyRepo := postgres.NewIdempotencyRepository(db)
+	auditRepo := postgres.NewAuditRepository(db)
+	webhookRepo := postgres.NewWebhookRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	chatLinkRepo := postgres.NewChatLinkRepository(db)
+	jobRunRepo := postgres.NewJobRunRepository(db)
+	unitOfWork := postgres.NewUnitOfWork(db)
 
 	// Init service
-	subService := usecase.NewSubscriptionService(subRepo)
+	subService := usecase.NewSubscriptionService(subRepo, auditRepo, outboxRepo, unitOfWork)
+	recService := usecase.NewRecommendationService(subRepo, usecase.DefaultRecommendationRules())
+	statementService := usecase.NewStatementService(subRepo)
+	idempotencyService := usecase.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.Lease)
+	auditService := usecase.NewAuditService(auditRepo, subRepo)
+	webhookService := usecase.NewWebhookService(webhookRepo, webhook.NewSender(cfg.Webhooks.Timeout), usecase.WebhookOptions{
+		MaxAttempts: cfg.Webhooks.MaxAttempts,
+		BaseDelay:   cfg.Webhooks.BaseDelay,
+		MaxDelay:    cfg.Webhooks.MaxDelay,
+		Lease:       2 * cfg.Webhooks.Timeout,
+		BatchSize:   cfg.Webhooks.BatchSize,
+	})
+
+	var reminderChannels []port.ReminderChannel
+	if cfg.Notifications.SMTP.Host != "" {
+		mailer, err := notification.NewSMTPMailer(notification.SMTPConfig{
+			Host:     cfg.Notifications.SMTP.Host,
+			Port:     cfg.Notifications.SMTP.Port,
+			Username: cfg.Notifications.SMTP.Username,
+			Password: cfg.Notifications.SMTP.Password,
+			From:     cfg.Notifications.SMTP.From,
+			Timeout:  cfg.Notifications.SMTP.Timeout,
+		})
+		if err != nil {
+			log.Fatalf("failed to configure SMTP: %v", err)
+		}
+		reminderChannels = append(reminderChannels, notification.NewEmailChannel(mailer))
+	}
+	notificationService := usecase.NewNotificationService(notificationRepo, subRepo, reminderChannels...)
+	chatLinkService := usecase.NewChatLinkService(chatLinkRepo, cfg.Telegram.LinkTokenTTL)
+
+	// Init event publishers
+	bus := events.NewBus()
+	bus.Subscribe(webhookService)
+	if !cfg.Events.DisableLog {
+		bus.Subscribe(events.NewLogPublisher())
+	}
+	if cfg.Events.HTTPURL != "" {
+		bus.Subscribe(events.NewHTTPPublisher(cfg.Events.HTTPURL, cfg.Events.HTTPTimeout))
+	}
+	relay := usecase.NewOutboxRelay(outboxRepo, bus, unitOfWork, usecase.OutboxOptions{
+		Lease:       cfg.Events.Lease,
+		BatchSize:   cfg.Events.BatchSize,
+		MaxAttempts: cfg.Events.MaxAttempts,
+		BaseDelay:   cfg.Events.BaseDelay,
+		MaxDelay:    cfg.Events.MaxDelay,
+	})
+	renewalScanner := usecase.NewRenewalScanner(subRepo, outboxRepo, cfg.Events.RenewalNotice)
+
+	// Init scheduled jobs
+	jobs := scheduler.New(jobRunRepo, postgres.NewJobLocker(db))
+	mustRegister(jobs, scheduler.Job{
+		Name:     "renewal-scan",
+		Schedule: cfg.Jobs.RenewalScan,
+		Run:      scheduler.Counted("%d upcoming renewals", renewalScanner.ScanRenewals),
+	})
+	mustRegister(jobs, scheduler.Job{
+		Name:     "refresh-subscription-statuses",
+		Schedule: cfg.Jobs.RefreshStatuses,
+		Run:      scheduler.Counted("%d statuses changed", subService.RefreshStatuses),
+	})
+	mustRegister(jobs, scheduler.Job{
+		Name:     "purge-idempotency-keys",
+		Schedule: cfg.Jobs.PurgeIdempotency,
+		Run:      scheduler.Counted("%d expired keys removed", idempotencyService.PurgeExpired),
+	})
+	mustRegister(jobs, scheduler.Job{
+		Name:     "purge-deleted-subscriptions",
+		Schedule: cfg.Jobs.PurgeDeleted,
+		Run: scheduler.Counted("%d subscriptions removed", func(ctx context.Context) (int64, error) {
+			return subService.PurgeDeleted(ctx, cfg.Jobs.DeletedRetention)
+		}),
+	})
+	if len(reminderChannels) > 0 {
+		mustRegister(jobs, scheduler.Job{
+			Name:     "email-reminders",
+			Schedule: cfg.Jobs.Reminders,
+			Run:      scheduler.Counted("%d reminders sent", notificationService.SendReminders),
+		})
+	}
+
+	// Start background workers, they are stopped after the servers so in-flight requests can finish
+	workersCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	for _, run := range []func(ctx context.Context){
+		jobs.Run,
+		events.NewWorker(relay, cfg.Events.RelayInterval).Run,
+		webhook.NewWorker(webhookService, cfg.Webhooks.PollInterval).Run,
+	} {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			run(workersCtx)
+		}()
+	}
 
 	// Init Gin router
 	r := gin.Default()
 
-	// Init handler
-	subHandler := httpService.NewSubscriptionHandler(subService)
+	graphqlHandler, err := graphqlService.NewHandler(subService, graphqlService.Limits{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+	})
+	if err != nil {
+		log.Fatalf("failed to build GraphQL schema: %v", err)
+	}
+
+	// Init handlers
+	handlers := httpService.Handlers{
+		Subscription:   httpService.NewSubscriptionHandler(subService),
+		Recommendation: httpService.NewRecommendationHandler(recService),
+		Statement:      httpService.NewStatementHandler(statementService, subService),
+		Audit:          httpService.NewAuditHandler(auditService),
+		Webhook:        httpService.NewWebhookHandler(webhookService),
+		Notification:   httpService.NewNotificationHandler(notificationService),
+		Telegram:       httpService.NewTelegramHandler(chatLinkService, cfg.Telegram.BotUsername),
+		Job:            httpService.NewJobHandler(jobs),
+		Idempotency:    httpService.IdempotencyMiddleware(idempotencyService),
+		GraphQL:        graphqlHandler.Query,
+	}
 
 	// Register routes
-	httpService.RegisterRoutes(r, subHandler)
+	httpService.RegisterRoutes(r, handlers)
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Run gRPC server
+	var grpcServer *grpc.Server
+	if cfg.GRPC.Enabled {
+		lis, err := net.Listen("tcp", ":"+strconv.Itoa(cfg.GRPC.Port))
+		if err != nil {
+			log.Fatalf("failed to listen for gRPC: %v", err)
+		}
+		grpcServer = grpcService.NewServer(subService)
+		go func() {
+			logger.Log.Infof("Starting gRPC server on %s", lis.Addr())
+			if err := grpcServer.Serve(lis); err != nil {
+				log.Fatalf("gRPC server error: %v", err)
+			}
+		}()
+	}
+
 	// Run server
-	addr := ":" + strconv.Itoa(cfg.Server.Port)
-	logger.Log.Info("Starting server")
-	if err := r.Run(addr); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("server error: %v", err)
+	server := &http.Server{Addr: ":" + strconv.Itoa(cfg.Server.Port), Handler: r}
+	go func() {
+		logger.Log.Infof("Starting server on %s", server.Addr)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatalf("server error: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Log.Info("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		logger.Log.Errorf("server shutdown: %v", err)
+	}
+	if grpcServer != nil {
+		grpcServer.GracefulStop()
+	}
+
+	// Планировщик дожидается запущенных задач, их контекст уже отменён
+	stopWorkers()
+	workers.Wait()
+	logger.Log.Info("Server stopped")
+}
+
+func mustRegister(s *scheduler.Scheduler, job scheduler.Job) {
+	if err := s.Register(job); err != nil {
+		log.Fatalf("failed to register job: %v", err)
 	}
 }
@@ -0,0 +1,23 @@
+package port
+
+import "context"
+
+// DefaultActor is recorded in the audit log when a change has no known author.
+const DefaultActor = "anonymous"
+
+// SystemActor is recorded for changes the service makes on its own, e.g. in scheduled jobs.
+const SystemActor = "system"
+
+type actorKey struct{}
+
+// WithActor attaches the author of the changes made with ctx.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return DefaultActor
+}
@@ -0,0 +1,22 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// AuditRepository is append-only, entries are never updated or removed.
+type AuditRepository interface {
+	Append(ctx context.Context, entries ...*model.AuditEntry) error
+	ListBySubscription(ctx context.Context, subscriptionID uuid.UUID) ([]*model.AuditEntry, error)
+	Query(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+}
+
+type AuditService interface {
+	// History returns changes of the subscription, oldest first.
+	History(ctx context.Context, subscriptionID uuid.UUID) ([]*model.AuditEntry, error)
+	// Query returns matching entries, newest first.
+	Query(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+}
@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type ChatLinkRepository interface {
+	CreateLinkToken(ctx context.Context, token string, userID uuid.UUID, expiresAt time.Time) error
+	// ConsumeLinkToken deletes the token and returns its user, nil when it is unknown or expired.
+	ConsumeLinkToken(ctx context.Context, token string, now time.Time) (*uuid.UUID, error)
+	// SaveLink relinks the chat when it was linked to another user.
+	SaveLink(ctx context.Context, link *model.ChatLink) error
+	DeleteLink(ctx context.Context, chatID int64) (bool, error)
+	GetLink(ctx context.Context, chatID int64) (*model.ChatLink, error)
+	ListLinksByUser(ctx context.Context, userID uuid.UUID) ([]*model.ChatLink, error)
+}
+
+// ChatLinkService lets users prove in the chat that they own an account:
+// a token issued for the user through the API is sent to the bot.
+type ChatLinkService interface {
+	CreateLinkToken(ctx context.Context, userID uuid.UUID) (string, time.Time, error)
+	// LinkChat returns model.ErrInvalidLinkToken for unknown, used or expired tokens.
+	LinkChat(ctx context.Context, chatID int64, token string) (*model.ChatLink, error)
+	UnlinkChat(ctx context.Context, chatID int64) error
+	// GetLink returns nil for chats that aren't linked.
+	GetLink(ctx context.Context, chatID int64) (*model.ChatLink, error)
+	ListLinks(ctx context.Context, userID uuid.UUID) ([]*model.ChatLink, error)
+}
@@ -0,0 +1,14 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// EventPublisher receives events relayed from the outbox. Publish runs in a
+// transaction that marks the events as published, publishers storing events in
+// the database join it through ctx.
+type EventPublisher interface {
+	Publish(ctx context.Context, events ...model.Event) error
+}
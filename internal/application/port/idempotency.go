@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores a pending record, replacing an expired one or a pending one for the same
+	// request whose lease has run out. It reports false when the key is taken.
+	Reserve(ctx context.Context, rec *model.IdempotencyRecord) (bool, error)
+	// Get returns nil, nil when the key is unknown.
+	Get(ctx context.Context, scope, key string) (*model.IdempotencyRecord, error)
+	// Complete and Delete change the pending record only while rec holds its lease token.
+	Complete(ctx context.Context, rec *model.IdempotencyRecord) error
+	Delete(ctx context.Context, rec *model.IdempotencyRecord) error
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+type IdempotencyService interface {
+	// Begin reserves the key of the actor from ctx for a request identified by requestHash
+	// and returns the pending record holding the lease. When the key was already used for
+	// the same request, the stored record with a non-zero StatusCode is returned for replay.
+	// It fails with ErrIdempotencyKeyReused for a different request and with
+	// ErrIdempotencyKeyInProgress while the first request hasn't finished and its lease is valid.
+	Begin(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, error)
+	// Complete stores the response to be replayed on retries, unless the lease was taken over.
+	Complete(ctx context.Context, lease *model.IdempotencyRecord, statusCode int, contentType string, response []byte) error
+	// Abort releases the key so the request can be retried, e.g. after a server error.
+	Abort(ctx context.Context, lease *model.IdempotencyRecord) error
+	PurgeExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+type JobRunRepository interface {
+	Create(ctx context.Context, run *model.JobRun) error
+	// Claim creates a scheduled run unless a run for the same job and
+	// ScheduledAt already exists, false is returned in that case.
+	Claim(ctx context.Context, run *model.JobRun) (bool, error)
+	Finish(ctx context.Context, run *model.JobRun) error
+	List(ctx context.Context, job string, limit int) ([]*model.JobRun, error)
+	// Latest returns the last run of every job that has run at least once.
+	Latest(ctx context.Context) (map[string]*model.JobRun, error)
+}
+
+// JobLocker prevents a job from running concurrently, including on other replicas.
+type JobLocker interface {
+	// TryLock returns false when the job is locked, unlock must be called once the job is done.
+	TryLock(ctx context.Context, job string) (unlock func(), ok bool, err error)
+}
+
+type JobScheduler interface {
+	ListJobs(ctx context.Context) ([]model.JobInfo, error)
+	// TriggerJob starts the job in the background and returns its running entry,
+	// model.ErrJobRunning is returned when the job is already running anywhere.
+	TriggerJob(ctx context.Context, name string) (*model.JobRun, error)
+	ListJobRuns(ctx context.Context, name string, limit int) ([]*model.JobRun, error)
+}
@@ -0,0 +1,37 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, prefs *model.NotificationPreferences) error
+	ListEnabledPreferences(ctx context.Context) ([]*model.NotificationPreferences, error)
+
+	// ReserveReminder claims the reminder for sending through channel and returns
+	// false when it was claimed before, so every reminder is sent at most once.
+	ReserveReminder(ctx context.Context, channel string, userID uuid.UUID, reminder model.Reminder, now time.Time) (bool, error)
+	// ReleaseReminder drops a claim that was not sent, so the reminder can be retried.
+	ReleaseReminder(ctx context.Context, channel string, reminder model.Reminder) error
+	MarkReminderSent(ctx context.Context, channel string, reminder model.Reminder, at time.Time) error
+}
+
+// ReminderChannel delivers reminders to users, for example by email.
+type ReminderChannel interface {
+	Name() string
+	// Send returns model.ErrNoRecipient when the user can't be reached through the channel.
+	Send(ctx context.Context, prefs *model.NotificationPreferences, reminder model.Reminder) error
+}
+
+type NotificationService interface {
+	// GetPreferences returns defaults for users who haven't set any.
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, prefs *model.NotificationPreferences) error
+	// SendReminders sends due reminders through every channel and returns how many were sent.
+	SendReminders(ctx context.Context) (int, error)
+}
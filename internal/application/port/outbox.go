@@ -0,0 +1,39 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// OutboxRepository stores events in the transaction of the change that
+// caused them, the relay publishes them afterwards.
+type OutboxRepository interface {
+	// Append ignores events that are already stored.
+	Append(ctx context.Context, events ...model.Event) error
+	// Claim leases up to limit unpublished events due at now, oldest first.
+	// Leased events are hidden from other relays until the lease ends.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEvent, error)
+	MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error
+	// MarkFailed records the error and postpones the event until retryAt.
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error
+	// MarkDead records the error and stops retrying the event.
+	MarkDead(ctx context.Context, id uuid.UUID, reason string, at time.Time) error
+}
+
+// OutboxRelay moves events from the outbox to an EventPublisher.
+// Delivery is at least once: consumers should deduplicate by event id.
+type OutboxRelay interface {
+	// Relay publishes due events oldest first and returns how many were published.
+	// A failed event is retried later with backoff and doesn't hold up the rest of
+	// the batch; after the last attempt it is given up.
+	Relay(ctx context.Context) (int, error)
+}
+
+type RenewalScanner interface {
+	// ScanRenewals emits renewal_upcoming events for subscriptions billed soon.
+	// Each renewal is emitted once, however often it is found.
+	ScanRenewals(ctx context.Context) (int, error)
+}
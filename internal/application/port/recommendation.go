@@ -0,0 +1,31 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// RecommendationInput is the data available to recommendation rules.
+type RecommendationInput struct {
+	UserID        uuid.UUID
+	Now           time.Time
+	Subscriptions []*model.Subscription
+	// PriceChanges are indexed by subscription ID and ordered by effective date.
+	PriceChanges map[uuid.UUID][]*model.PriceChange
+	// YearlyPrices holds the lowest known yearly price per service and
+	// currency, keyed by lower-cased service name and currency joined with "|".
+	YearlyPrices map[string]model.Money
+}
+
+// RecommendationRule inspects user's subscriptions and suggests savings.
+type RecommendationRule interface {
+	Name() string
+	Evaluate(ctx context.Context, input RecommendationInput) []model.Recommendation
+}
+
+type RecommendationService interface {
+	Recommend(ctx context.Context, userID uuid.UUID) ([]model.Recommendation, error)
+}
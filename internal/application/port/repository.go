@@ -10,10 +10,51 @@ import (
 
 type SubscriptionRepository interface {
 	Create(ctx context.Context, sub *model.Subscription) error
+	// ExecBatch runs the operations in a single transaction and returns an error per operation.
+	// In atomic mode the first failure rolls everything back, otherwise failed operations are
+	// skipped and the rest is committed. The returned error reports failures of the transaction itself.
+	ExecBatch(ctx context.Context, ops []model.BatchOperation, atomic bool) ([]error, error)
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	// GetByIDForUpdate locks the row until the end of the transaction started by UnitOfWork.
+	GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	// GetByIDsForUpdate is GetByIDForUpdate for several rows, missing ids are skipped.
+	GetByIDsForUpdate(ctx context.Context, ids []uuid.UUID) ([]*model.Subscription, error)
 	Update(ctx context.Context, sub *model.Subscription) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// PurgeDeleted permanently removes subscriptions deleted as a mistake before the given time.
+	PurgeDeleted(ctx context.Context, before time.Time) (int64, error)
 	List(ctx context.Context) ([]*model.Subscription, error)
+	ListByStatus(ctx context.Context, statuses []model.SubscriptionStatus) ([]*model.Subscription, error)
+	// ListByUsers returns subscriptions owned by any of userIDs.
+	ListByUsers(ctx context.Context, userIDs []uuid.UUID) ([]*model.Subscription, error)
+	// ListPageByUsers returns up to limit subscriptions of each of userIDs after skipping offset of them.
+	ListPageByUsers(ctx context.Context, userIDs []uuid.UUID, limit, offset int) ([]*model.Subscription, error)
+	// ListPage returns a page of the rows Stream reads, optionally only those in status.
+	ListPage(ctx context.Context, userID *uuid.UUID, serviceName *string, status *model.SubscriptionStatus, limit, offset int) ([]*model.Subscription, error)
+	// Stream reads subscriptions owned by userID (when set) with a cursor, calling fn for each row.
+	Stream(ctx context.Context, userID *uuid.UUID, serviceName *string, fn func(*model.Subscription) error) error
 
-	GetByFilter(ctx context.Context, userID *uuid.UUID, serviceName *string, from, to time.Time) ([]*model.Subscription, error)
+	// GetByFilter matches userID against both owners and members of shared subscriptions.
+	// With includeDeleted it also returns subscriptions deleted as ended during or after the period.
+	GetByFilter(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) ([]*model.Subscription, error)
+	// GetByFilterForUsers is GetByFilter for any of userIDs, a subscription shared between them is returned once.
+	GetByFilterForUsers(ctx context.Context, userIDs []uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) ([]*model.Subscription, error)
+	// ListByFilter returns a page of the rows GetByFilter matches, ordered like StreamByFilter.
+	ListByFilter(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool, limit, offset int) ([]*model.Subscription, error)
+	// StreamByFilter reads the same rows as GetByFilter with a cursor, calling fn for each row.
+	StreamByFilter(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool, fn func(*model.Subscription) error) error
+	ListByUserAndService(ctx context.Context, userID uuid.UUID, serviceName string) ([]*model.Subscription, error)
+	// LockUser serializes changes to subscriptions of userID until the end of the transaction started by UnitOfWork.
+	LockUser(ctx context.Context, userID uuid.UUID) error
+	LowestPrices(ctx context.Context, period model.BillingPeriod) ([]model.ServicePrice, error)
+
+	CreatePriceChange(ctx context.Context, change *model.PriceChange) error
+	ListPriceChanges(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.PriceChange, error)
+
+	ReplaceMembers(ctx context.Context, subscriptionID uuid.UUID, members []*model.SubscriptionMember) error
+	ListMembers(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.SubscriptionMember, error)
+
+	CreateDiscount(ctx context.Context, discount *model.Discount) error
+	ListDiscounts(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.Discount, error)
+	DeleteDiscount(ctx context.Context, id uuid.UUID) error
 }
@@ -9,11 +9,66 @@ import (
 )
 
 type SubscriptionService interface {
-	CreateSubscription(ctx context.Context, sub *model.Subscription) error
+	// CreateSubscription and UpdateSubscription return model.ErrSubscriptionOverlap in strict mode
+	// when the user already has another subscription to the same service running in any of sub's months.
+	CreateSubscription(ctx context.Context, sub *model.Subscription, strict bool) error
+	// ImportSubscriptions creates all subscriptions atomically, nothing is stored when dryRun is set.
+	// In strict mode it returns model.ErrSubscriptionOverlap when a subscription overlaps an existing or another imported one.
+	ImportSubscriptions(ctx context.Context, subs []*model.Subscription, dryRun, strict bool) error
+	// ExecBatch applies create, update and delete operations, see SubscriptionRepository.ExecBatch.
+	ExecBatch(ctx context.Context, ops []model.BatchOperation, mode model.BatchMode) ([]error, error)
 	GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
-	UpdateSubscription(ctx context.Context, sub *model.Subscription) error
-	DeleteSubscription(ctx context.Context, id uuid.UUID) error
+	UpdateSubscription(ctx context.Context, sub *model.Subscription, strict bool) error
+	// DeleteSubscription soft-deletes the subscription. Subscriptions deleted as ended
+	// still count in costs up to the month of deletion, mistakes never do.
+	DeleteSubscription(ctx context.Context, id uuid.UUID, reason model.DeletionReason) error
+	// RestoreSubscription undoes a deletion, restoring an active subscription is a no-op.
+	RestoreSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	// CancelSubscription and ReactivateSubscription return model.ErrInvalidTransition
+	// when the subscription status doesn't allow them.
+	CancelSubscription(ctx context.Context, id uuid.UUID, mode model.CancelMode) (*model.Subscription, error)
+	ReactivateSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	// RefreshStatuses stores the statuses subscriptions reached with time and returns how many changed.
+	RefreshStatuses(ctx context.Context) (int, error)
+	// PurgeDeleted permanently removes subscriptions deleted as a mistake longer than retention ago,
+	// they can't be restored afterwards. Ended ones stay for the cost history. Their audit log is kept.
+	PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error)
 	ListSubscriptions(ctx context.Context) ([]*model.Subscription, error)
+	// ListSubscriptionsPage returns a page of the subscriptions StreamSubscriptions reads, optionally only those in status.
+	ListSubscriptionsPage(ctx context.Context, userID *uuid.UUID, serviceName *string, status *model.SubscriptionStatus, limit, offset int) ([]*model.Subscription, error)
+	// StreamSubscriptions calls fn for every matching subscription as it is read from storage.
+	StreamSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName *string, fn func(*model.Subscription) error) error
+	// FilterSubscriptions returns a page of subscriptions running during [from, to] selected the same way as for cost calculations.
+	FilterSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool, limit, offset int) ([]*model.Subscription, error)
 
-	CalculateTotalCost(ctx context.Context, userID *uuid.UUID, serviceName *string, from, to time.Time) (int, error)
+	// Batch lookups for data loaders, results are grouped by the requested ID.
+	// ListSubscriptionsByUsers pages subscriptions of every user separately.
+	ListSubscriptionsByUsers(ctx context.Context, userIDs []uuid.UUID, limit, offset int) (map[uuid.UUID][]*model.Subscription, error)
+	ListMembersBySubscriptions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]*model.SubscriptionMember, error)
+	ListPriceChangesBySubscriptions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]*model.PriceChange, error)
+	// CalculateTotalCostByUsers is CalculateTotalCost of every user, every requested user gets a total.
+	CalculateTotalCostByUsers(ctx context.Context, userIDs []uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) (map[uuid.UUID]model.CostBreakdown, error)
+
+	FindDuplicates(ctx context.Context, userID *uuid.UUID) ([]model.DuplicateGroup, error)
+
+	SchedulePriceChange(ctx context.Context, change *model.PriceChange) error
+	ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]*model.PriceChange, error)
+
+	SetMembers(ctx context.Context, subscriptionID uuid.UUID, members []*model.SubscriptionMember) error
+	ListMembers(ctx context.Context, subscriptionID uuid.UUID) ([]*model.SubscriptionMember, error)
+
+	CreateDiscount(ctx context.Context, discount *model.Discount) error
+	ListDiscounts(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Discount, error)
+	DeleteDiscount(ctx context.Context, subscriptionID, discountID uuid.UUID) error
+
+	// Cost methods attribute shared subscriptions to userID by its split share,
+	// without userID every subscription is counted once in full.
+	// Forecasts and comparisons operate on amounts paid: after discounts, including tax.
+	// includeDeleted adds subscriptions deleted as ended, comparisons always include them and forecasts never do.
+	CalculateTotalCost(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) (model.CostBreakdown, error)
+	CostReport(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) ([]model.SubscriptionCost, error)
+	// StreamCostReport calls fn for every line of the cost report as soon as it is calculated.
+	StreamCostReport(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool, fn func(model.SubscriptionCost) error) error
+	CompareCost(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, base, current model.Period) (*model.CostComparison, error)
+	ForecastCost(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from time.Time, months int) ([]model.MonthlyCost, error)
 }
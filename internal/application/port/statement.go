@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+	"io"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// StatementParser reads transactions from a bank statement file.
+type StatementParser interface {
+	Parse(r io.Reader) ([]model.Transaction, error)
+}
+
+type StatementService interface {
+	// DetectSubscriptions finds recurring charges in the transactions and
+	// links candidates to subscriptions the user already tracks.
+	DetectSubscriptions(ctx context.Context, userID uuid.UUID, transactions []model.Transaction) ([]model.SubscriptionCandidate, error)
+}
@@ -0,0 +1,11 @@
+package port
+
+import "context"
+
+// UnitOfWork runs several repository calls atomically. Repositories join the
+// transaction when they are called with the context passed to fn.
+type UnitOfWork interface {
+	// Do commits when fn returns nil and rolls back otherwise.
+	// Nested calls join the outer transaction.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
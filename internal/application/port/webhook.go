@@ -0,0 +1,52 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error
+	GetEndpoint(ctx context.Context, id uuid.UUID) (*model.WebhookEndpoint, error)
+	ListEndpoints(ctx context.Context) ([]*model.WebhookEndpoint, error)
+	// DeleteEndpoint removes the endpoint with its deliveries, it returns false when there was none.
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// Enqueue creates a pending delivery for every endpoint subscribed to the event type.
+	// An event is enqueued for an endpoint only once, repeated calls are ignored.
+	Enqueue(ctx context.Context, event model.Event, now time.Time) error
+	// ClaimDue returns pending deliveries due at now and postpones them by lease,
+	// so other workers skip them while they are being sent.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error)
+	// RecordAttempt stores the attempt together with the new state of the delivery.
+	RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookAttempt) error
+	GetDelivery(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	ListDeliveries(ctx context.Context, endpointID uuid.UUID, status *model.DeliveryStatus, limit int) ([]*model.WebhookDelivery, error)
+	ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*model.WebhookAttempt, error)
+}
+
+// WebhookSender posts a signed delivery to the endpoint and returns the response status.
+type WebhookSender interface {
+	Send(ctx context.Context, endpoint *model.WebhookEndpoint, delivery *model.WebhookDelivery) (int, error)
+}
+
+// WebhookService also implements EventPublisher by enqueueing deliveries.
+type WebhookService interface {
+	EventPublisher
+
+	RegisterEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error
+	ListEndpoints(ctx context.Context) ([]*model.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id uuid.UUID) error
+
+	ListDeliveries(ctx context.Context, endpointID uuid.UUID, status *model.DeliveryStatus, limit int) ([]*model.WebhookDelivery, error)
+	GetDelivery(ctx context.Context, endpointID, id uuid.UUID) (*model.WebhookDelivery, []*model.WebhookAttempt, error)
+	// Redeliver queues the delivery for an immediate send with a fresh retry budget.
+	Redeliver(ctx context.Context, endpointID, id uuid.UUID) (*model.WebhookDelivery, error)
+
+	// DeliverDue sends due deliveries and returns how many were attempted.
+	DeliverDue(ctx context.Context) (int, error)
+}
@@ -0,0 +1,76 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+var auditEventTypes = map[model.AuditAction]model.EventType{
+	model.AuditCreate:       model.EventSubscriptionCreated,
+	model.AuditUpdate:       model.EventSubscriptionUpdated,
+	model.AuditDelete:       model.EventSubscriptionDeleted,
+	model.AuditRestore:      model.EventSubscriptionRestored,
+	model.AuditCancel:       model.EventSubscriptionCancelled,
+	model.AuditReactivate:   model.EventSubscriptionReactivated,
+	model.AuditStatusChange: model.EventSubscriptionStatusChanged,
+	model.AuditPriceChange:  model.EventSubscriptionPriceChanged,
+}
+
+type auditService struct {
+	repo port.AuditRepository
+	subs port.SubscriptionRepository
+}
+
+func NewAuditService(repo port.AuditRepository, subs port.SubscriptionRepository) port.AuditService {
+	return &auditService{repo: repo, subs: subs}
+}
+
+func (s *auditService) History(ctx context.Context, subscriptionID uuid.UUID) ([]*model.AuditEntry, error) {
+	entries, err := s.repo.ListBySubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		return entries, nil
+	}
+
+	// Подписки, созданные до появления журнала, не имеют записей
+	sub, err := s.subs.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, model.ErrSubscriptionNotFound
+	}
+	return entries, nil
+}
+
+func (s *auditService) Query(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	return s.repo.Query(ctx, filter)
+}
+
+// newAuditEntry snapshots before and after, so later changes of the
+// subscriptions don't leak into the entry.
+func newAuditEntry(ctx context.Context, action model.AuditAction, before, after *model.Subscription) *model.AuditEntry {
+	entry := &model.AuditEntry{
+		ID:        uuid.New(),
+		Actor:     port.ActorFromContext(ctx),
+		Action:    action,
+		CreatedAt: time.Now().UTC(),
+	}
+	if before != nil {
+		snapshot := *before
+		entry.Before = &snapshot
+		entry.SubscriptionID = before.ID
+	}
+	if after != nil {
+		snapshot := *after
+		entry.After = &snapshot
+		entry.SubscriptionID = after.ID
+	}
+	return entry
+}
@@ -0,0 +1,315 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// monthStart truncates t to the first day of its month.
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// monthsDiff returns the number of whole months from a to b.
+func monthsDiff(a, b time.Time) int {
+	return (b.Year()-a.Year())*12 + int(b.Month()) - int(a.Month())
+}
+
+// endMonth returns the last month the subscription runs, nil when it has no end.
+// A subscription deleted as ended runs until the month it was deleted.
+func endMonth(sub *model.Subscription) *time.Time {
+	end := sub.EndDate
+	if sub.IsDeleted && sub.DeletedAt != nil && (end == nil || sub.DeletedAt.Before(*end)) {
+		end = sub.DeletedAt
+	}
+	if end == nil {
+		return nil
+	}
+	month := monthStart(end.UTC())
+	return &month
+}
+
+// isActiveIn reports whether the subscription runs during the given month.
+func isActiveIn(sub *model.Subscription, month time.Time) bool {
+	if month.Before(monthStart(sub.StartDate)) {
+		return false
+	}
+	if end := endMonth(sub); end != nil && month.After(*end) {
+		return false
+	}
+	return true
+}
+
+// priceAt returns the price in effect for the given month.
+// changes must be ordered by EffectiveFrom.
+func priceAt(sub *model.Subscription, changes []*model.PriceChange, month time.Time) model.Money {
+	price := sub.Price
+	for _, c := range changes {
+		if monthStart(c.EffectiveFrom).After(month) {
+			break
+		}
+		price = c.Price
+	}
+	return price
+}
+
+// chargeAt returns the amount billed for the subscription in the given month.
+func chargeAt(sub *model.Subscription, changes []*model.PriceChange, month time.Time) model.Money {
+	if !isBillingMonth(sub, month) {
+		return model.Money{Currency: sub.Price.Currency}
+	}
+	return priceAt(sub, changes, month)
+}
+
+// isBillingMonth reports whether the subscription is charged in the given month.
+// Months before the trial end are free, and the billing cycle is anchored
+// to the first paid month.
+func isBillingMonth(sub *model.Subscription, month time.Time) bool {
+	if !isActiveIn(sub, month) {
+		return false
+	}
+
+	anchor := monthStart(sub.StartDate)
+	if sub.TrialEndDate != nil {
+		trialEnd := monthStart(*sub.TrialEndDate)
+		if month.Before(trialEnd) {
+			return false
+		}
+		if trialEnd.After(anchor) {
+			anchor = trialEnd
+		}
+	}
+
+	return monthsDiff(anchor, month)%sub.BillingPeriod.Months() == 0
+}
+
+// periodEnd returns the last month paid for when the subscription is cancelled
+// in the given month: the end of the billing cycle in progress, or the last
+// free month during a trial.
+func periodEnd(sub *model.Subscription, month time.Time) time.Time {
+	anchor := monthStart(sub.StartDate)
+	if sub.TrialEndDate != nil {
+		trialEnd := monthStart(*sub.TrialEndDate)
+		if month.Before(trialEnd) {
+			return trialEnd.AddDate(0, -1, 0)
+		}
+		if trialEnd.After(anchor) {
+			anchor = trialEnd
+		}
+	}
+
+	period := sub.BillingPeriod.Months()
+	cycles := monthsDiff(anchor, month)/period + 1
+	return anchor.AddDate(0, cycles*period-1, 0)
+}
+
+// upcomingBillingMonths returns the billing months starting after from and no later than until.
+func upcomingBillingMonths(sub *model.Subscription, from, until time.Time) []time.Time {
+	var months []time.Time
+	for month := monthStart(from).AddDate(0, 1, 0); !month.After(until); month = month.AddDate(0, 1, 0) {
+		if isBillingMonth(sub, month) {
+			months = append(months, month)
+		}
+	}
+	return months
+}
+
+// discountAt returns the discount for a charge billed in the given month.
+// Percentage discounts are taken from the full charge and rounded down,
+// the total discount never exceeds the charge.
+func discountAt(charge model.Money, discounts []*model.Discount, month time.Time) (model.Money, error) {
+	discount := model.Money{Currency: charge.Currency}
+	for _, d := range discounts {
+		if month.Before(monthStart(d.StartDate)) || (d.EndDate != nil && month.After(monthStart(*d.EndDate))) {
+			continue
+		}
+
+		var amount model.Money
+		var err error
+		switch d.Kind {
+		case model.DiscountPercentage:
+			amount, err = charge.MulRatio(d.Value, 100, model.RoundDown)
+		case model.DiscountFixed:
+			amount = model.NewMoney(d.Value, charge.Currency)
+		}
+		if err != nil {
+			return model.Money{}, err
+		}
+
+		if discount, err = discount.Add(amount); err != nil {
+			return model.Money{}, err
+		}
+	}
+	return discount.Min(charge)
+}
+
+// billingData holds the child records needed to bill a set of subscriptions.
+type billingData struct {
+	priceChanges map[uuid.UUID][]*model.PriceChange
+	members      map[uuid.UUID][]*model.SubscriptionMember
+	discounts    map[uuid.UUID][]*model.Discount
+}
+
+// loadBillingData reads the child records of subs.
+func loadBillingData(ctx context.Context, repo port.SubscriptionRepository, subs []*model.Subscription) (billingData, error) {
+	if len(subs) == 0 {
+		return billingData{}, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+
+	changes, err := repo.ListPriceChanges(ctx, ids)
+	if err != nil {
+		return billingData{}, err
+	}
+
+	members, err := repo.ListMembers(ctx, ids)
+	if err != nil {
+		return billingData{}, err
+	}
+
+	discounts, err := repo.ListDiscounts(ctx, ids)
+	if err != nil {
+		return billingData{}, err
+	}
+
+	return billingData{
+		priceChanges: groupPriceChanges(changes),
+		members:      groupMembers(members),
+		discounts:    groupDiscounts(discounts),
+	}, nil
+}
+
+// breakdown returns the amounts billed for the subscription in the given month.
+// When userID is set, only the share attributed to that user is returned.
+func (b billingData) breakdown(sub *model.Subscription, month time.Time, userID *uuid.UUID) (model.CostBreakdown, error) {
+	charge := chargeAt(sub, b.priceChanges[sub.ID], month)
+	if charge.IsZero() {
+		return model.CostBreakdown{}, nil
+	}
+
+	discount, err := discountAt(charge, b.discounts[sub.ID], month)
+	if err != nil {
+		return model.CostBreakdown{}, err
+	}
+	paid, err := charge.Sub(discount)
+	if err != nil {
+		return model.CostBreakdown{}, err
+	}
+
+	before, err := withTax(sub, charge)
+	if err != nil {
+		return model.CostBreakdown{}, err
+	}
+	net, tax, err := sub.SplitTax(paid)
+	if err != nil {
+		return model.CostBreakdown{}, err
+	}
+	gross, err := net.Add(tax)
+	if err != nil {
+		return model.CostBreakdown{}, err
+	}
+
+	discount, err = before.Sub(gross)
+	if err != nil {
+		return model.CostBreakdown{}, err
+	}
+
+	total := model.CostBreakdown{
+		BeforeDiscount: before,
+		Discount:       discount,
+		Net:            net,
+		Tax:            tax,
+		Gross:          gross,
+	}
+	if userID == nil {
+		return total, nil
+	}
+	return shareOf(total, sub.UserID, b.members[sub.ID], *userID)
+}
+
+// withTax returns the tax-inclusive value of an amount in the subscription's price basis.
+func withTax(sub *model.Subscription, amount model.Money) (model.Money, error) {
+	net, tax, err := sub.SplitTax(amount)
+	if err != nil {
+		return model.Money{}, err
+	}
+	return net.Add(tax)
+}
+
+// charge returns the amount paid, including tax, for the subscription in the given month.
+func (b billingData) charge(sub *model.Subscription, month time.Time, userID *uuid.UUID) (model.Money, error) {
+	breakdown, err := b.breakdown(sub, month, userID)
+	return breakdown.Gross, err
+}
+
+// breakdownInRange sums the breakdowns of the subscription for every month in [from, to].
+func (b billingData) breakdownInRange(sub *model.Subscription, from, to time.Time, userID *uuid.UUID) (model.CostBreakdown, error) {
+	var total model.CostBreakdown
+	for month := monthStart(from); !month.After(to); month = month.AddDate(0, 1, 0) {
+		breakdown, err := b.breakdown(sub, month, userID)
+		if err != nil {
+			return model.CostBreakdown{}, err
+		}
+		if total, err = total.Add(breakdown); err != nil {
+			return model.CostBreakdown{}, err
+		}
+	}
+	return total, nil
+}
+
+// costInRange sums the amounts paid for the subscription for every month in [from, to].
+func (b billingData) costInRange(sub *model.Subscription, from, to time.Time, userID *uuid.UUID) (model.Money, error) {
+	breakdown, err := b.breakdownInRange(sub, from, to, userID)
+	return breakdown.Gross, err
+}
+
+// addMoney adds amount to total in place.
+func addMoney(total *model.Money, amount model.Money) error {
+	sum, err := total.Add(amount)
+	if err != nil {
+		return err
+	}
+	*total = sum
+	return nil
+}
+
+// overlaps reports whether the subscription runs during any month of [from, to].
+func overlaps(sub *model.Subscription, from, to time.Time) bool {
+	if monthStart(sub.StartDate).After(to) {
+		return false
+	}
+	end := endMonth(sub)
+	return end == nil || !end.Before(monthStart(from))
+}
+
+// groupPriceChanges indexes price changes by subscription ID, ordered by effective date.
+func groupPriceChanges(changes []*model.PriceChange) map[uuid.UUID][]*model.PriceChange {
+	grouped := make(map[uuid.UUID][]*model.PriceChange)
+	for _, c := range changes {
+		grouped[c.SubscriptionID] = append(grouped[c.SubscriptionID], c)
+	}
+	for _, list := range grouped {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].EffectiveFrom.Before(list[j].EffectiveFrom)
+		})
+	}
+	return grouped
+}
+
+// groupDiscounts indexes discounts by subscription ID.
+func groupDiscounts(discounts []*model.Discount) map[uuid.UUID][]*model.Discount {
+	grouped := make(map[uuid.UUID][]*model.Discount)
+	for _, d := range discounts {
+		grouped[d.SubscriptionID] = append(grouped[d.SubscriptionID], d)
+	}
+	return grouped
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+func TestDiscountAt(t *testing.T) {
+	percent := func(value int64, from time.Time, to *time.Time) *model.Discount {
+		return &model.Discount{Kind: model.DiscountPercentage, Value: value, StartDate: from, EndDate: to}
+	}
+	fixed := func(value int64, from time.Time, to *time.Time) *model.Discount {
+		return &model.Discount{Kind: model.DiscountFixed, Value: value, StartDate: from, EndDate: to}
+	}
+	march := month(2025, time.March)
+	// Скидка действует весь месяц окончания, даже если заканчивается в его середине
+	midMarch := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)
+	february := month(2025, time.February)
+
+	tests := []struct {
+		name      string
+		charge    int64
+		discounts []*model.Discount
+		want      int64
+	}{
+		{"no discounts", 99900, nil, 0},
+		{"percentage rounded down", 99999, []*model.Discount{percent(15, february, nil)}, 14999},
+		{"fixed", 99900, []*model.Discount{fixed(10000, february, nil)}, 10000},
+		{"overlapping discounts add up", 100000, []*model.Discount{percent(10, february, nil), fixed(5000, march, nil)}, 15000},
+		{"percentages taken from the full charge", 100000, []*model.Discount{percent(30, february, nil), percent(30, february, nil)}, 60000},
+		{"fixed capped at the charge", 30000, []*model.Discount{fixed(50000, february, nil)}, 30000},
+		{"overlapping discounts capped at the charge", 100000, []*model.Discount{percent(60, february, nil), fixed(50000, february, nil)}, 100000},
+		{"not started yet", 100000, []*model.Discount{percent(50, month(2025, time.April), nil)}, 0},
+		{"started mid month", 100000, []*model.Discount{percent(50, midMarch, nil)}, 50000},
+		{"ended last month", 100000, []*model.Discount{fixed(10000, month(2024, time.December), &february)}, 0},
+		{"ends this month", 100000, []*model.Discount{fixed(10000, february, &midMarch)}, 10000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := discountAt(rub(tt.charge), tt.discounts, march)
+			if err != nil {
+				t.Fatalf("discountAt() error = %v", err)
+			}
+			if got != rub(tt.want) {
+				t.Errorf("discountAt() = %+v, want %d RUB", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPeriodEndAndBillingMonths(t *testing.T) {
+	subscription := func(period model.BillingPeriod, start time.Time, trialEnd *time.Time) *model.Subscription {
+		return &model.Subscription{Price: rub(10000), StartDate: start, BillingPeriod: period, TrialEndDate: trialEnd}
+	}
+	// Пробный период до 10 мая: апрель последний бесплатный месяц, платежи с мая
+	trialEnd := time.Date(2025, time.May, 10, 0, 0, 0, 0, time.UTC)
+	// Пробный период, закончившийся до начала подписки, не сдвигает циклы
+	pastTrial := month(2025, time.January)
+
+	tests := []struct {
+		name    string
+		sub     *model.Subscription
+		month   time.Time
+		wantEnd time.Time
+		billed  bool
+	}{
+		{"monthly", subscription(model.BillingPeriodMonthly, month(2025, time.January), nil), month(2025, time.March), month(2025, time.March), true},
+		{"quarterly in the anchor month", subscription(model.BillingPeriodQuarterly, month(2025, time.February), nil), month(2025, time.May), month(2025, time.July), true},
+		{"quarterly mid cycle", subscription(model.BillingPeriodQuarterly, month(2025, time.February), nil), month(2025, time.June), month(2025, time.July), false},
+		{"quarterly at the end of the cycle", subscription(model.BillingPeriodQuarterly, month(2025, time.February), nil), month(2025, time.July), month(2025, time.July), false},
+		{"yearly in the first month", subscription(model.BillingPeriodYearly, time.Date(2024, time.November, 20, 0, 0, 0, 0, time.UTC), nil), month(2024, time.November), month(2025, time.October), true},
+		{"yearly in the last month", subscription(model.BillingPeriodYearly, month(2024, time.November), nil), month(2025, time.October), month(2025, time.October), false},
+		{"yearly on the anniversary", subscription(model.BillingPeriodYearly, month(2024, time.November), nil), month(2025, time.November), month(2026, time.October), true},
+		{"during the trial", subscription(model.BillingPeriodMonthly, month(2025, time.March), &trialEnd), month(2025, time.March), month(2025, time.April), false},
+		{"last trial month", subscription(model.BillingPeriodQuarterly, month(2025, time.March), &trialEnd), month(2025, time.April), month(2025, time.April), false},
+		{"first month after the trial", subscription(model.BillingPeriodQuarterly, month(2025, time.March), &trialEnd), month(2025, time.May), month(2025, time.July), true},
+		{"cycles anchored at the trial end", subscription(model.BillingPeriodQuarterly, month(2025, time.March), &trialEnd), month(2025, time.August), month(2025, time.October), true},
+		{"trial ending before the start", subscription(model.BillingPeriodQuarterly, month(2025, time.March), &pastTrial), month(2025, time.June), month(2025, time.August), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := periodEnd(tt.sub, tt.month); !got.Equal(tt.wantEnd) {
+				t.Errorf("periodEnd() = %s, want %s", got.Format("01-2006"), tt.wantEnd.Format("01-2006"))
+			}
+			if got := isBillingMonth(tt.sub, tt.month); got != tt.billed {
+				t.Errorf("isBillingMonth() = %v, want %v", got, tt.billed)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type chatLinkService struct {
+	repo     port.ChatLinkRepository
+	tokenTTL time.Duration
+}
+
+func NewChatLinkService(repo port.ChatLinkRepository, tokenTTL time.Duration) port.ChatLinkService {
+	return &chatLinkService{repo: repo, tokenTTL: tokenTTL}
+}
+
+// CreateLinkToken returns a token short enough for Telegram deep links (up to 64 characters).
+func (s *chatLinkService) CreateLinkToken(ctx context.Context, userID uuid.UUID) (string, time.Time, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", time.Time{}, err
+	}
+	token := hex.EncodeToString(raw)
+	expiresAt := time.Now().UTC().Add(s.tokenTTL)
+
+	if err := s.repo.CreateLinkToken(ctx, token, userID, expiresAt); err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+func (s *chatLinkService) LinkChat(ctx context.Context, chatID int64, token string) (*model.ChatLink, error) {
+	now := time.Now().UTC()
+	userID, err := s.repo.ConsumeLinkToken(ctx, token, now)
+	if err != nil {
+		return nil, err
+	}
+	if userID == nil {
+		return nil, model.ErrInvalidLinkToken
+	}
+
+	link := &model.ChatLink{ChatID: chatID, UserID: *userID, LinkedAt: now}
+	if err := s.repo.SaveLink(ctx, link); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *chatLinkService) UnlinkChat(ctx context.Context, chatID int64) error {
+	_, err := s.repo.DeleteLink(ctx, chatID)
+	return err
+}
+
+func (s *chatLinkService) GetLink(ctx context.Context, chatID int64) (*model.ChatLink, error) {
+	return s.repo.GetLink(ctx, chatID)
+}
+
+func (s *chatLinkService) ListLinks(ctx context.Context, userID uuid.UUID) ([]*model.ChatLink, error) {
+	return s.repo.ListLinksByUser(ctx, userID)
+}
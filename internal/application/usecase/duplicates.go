@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// normalizeServiceName makes service names comparable regardless of case and padding.
+func normalizeServiceName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// rangesOverlap reports whether two subscriptions run in at least one common month.
+func rangesOverlap(a, b *model.Subscription) bool {
+	return overlaps(a, b.StartDate, lastMonth(b)) && overlaps(b, a.StartDate, lastMonth(a))
+}
+
+// lastMonth returns the last month of the subscription, open-ended ones run forever.
+func lastMonth(sub *model.Subscription) time.Time {
+	if sub.EndDate == nil {
+		return time.Date(9999, time.December, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return monthStart(*sub.EndDate)
+}
+
+// findDuplicates groups overlapping subscriptions per user and service.
+func findDuplicates(subs []*model.Subscription) []model.DuplicateGroup {
+	type key struct {
+		userID  uuid.UUID
+		service string
+	}
+
+	buckets := make(map[key][]*model.Subscription)
+	for _, sub := range subs {
+		if sub.IsDeleted {
+			continue
+		}
+		k := key{userID: sub.UserID, service: normalizeServiceName(sub.ServiceName)}
+		buckets[k] = append(buckets[k], sub)
+	}
+
+	var groups []model.DuplicateGroup
+	for _, bucket := range buckets {
+		sort.Slice(bucket, func(i, j int) bool {
+			return bucket[i].StartDate.Before(bucket[j].StartDate)
+		})
+
+		// Сортировка по дате начала позволяет собрать пересекающиеся подписки за один проход
+		var cluster []*model.Subscription
+		var clusterEnd time.Time
+		flush := func() {
+			if len(cluster) > 1 {
+				groups = append(groups, model.DuplicateGroup{
+					UserID:        cluster[0].UserID,
+					ServiceName:   cluster[0].ServiceName,
+					Subscriptions: cluster,
+				})
+			}
+		}
+		for _, sub := range bucket {
+			if len(cluster) > 0 && monthStart(sub.StartDate).After(clusterEnd) {
+				flush()
+				cluster = nil
+			}
+			cluster = append(cluster, sub)
+			if end := lastMonth(sub); len(cluster) == 1 || end.After(clusterEnd) {
+				clusterEnd = end
+			}
+		}
+		flush()
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].UserID != groups[j].UserID {
+			return groups[i].UserID.String() < groups[j].UserID.String()
+		}
+		si, sj := normalizeServiceName(groups[i].ServiceName), normalizeServiceName(groups[j].ServiceName)
+		if si != sj {
+			return si < sj
+		}
+		return groups[i].Subscriptions[0].StartDate.Before(groups[j].Subscriptions[0].StartDate)
+	})
+	return groups
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type idempotencyService struct {
+	repo  port.IdempotencyRepository
+	ttl   time.Duration
+	lease time.Duration
+}
+
+func NewIdempotencyService(repo port.IdempotencyRepository, ttl, lease time.Duration) port.IdempotencyService {
+	return &idempotencyService{repo: repo, ttl: ttl, lease: lease}
+}
+
+func (s *idempotencyService) Begin(ctx context.Context, key, requestHash string) (*model.IdempotencyRecord, error) {
+	now := time.Now()
+	lease := &model.IdempotencyRecord{
+		Scope:       port.ActorFromContext(ctx),
+		Key:         key,
+		RequestHash: requestHash,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.ttl),
+		LockedUntil: now.Add(s.lease),
+		LeaseToken:  uuid.NewString(),
+	}
+	reserved, err := s.repo.Reserve(ctx, lease)
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return lease, nil
+	}
+
+	rec, err := s.repo.Get(ctx, lease.Scope, key)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case rec == nil:
+		// Запись удалили между Reserve и Get: первый запрос завершился ошибкой и освободил ключ
+		return nil, model.ErrIdempotencyKeyInProgress
+	case rec.RequestHash != requestHash:
+		return nil, model.ErrIdempotencyKeyReused
+	case rec.StatusCode == 0:
+		return nil, model.ErrIdempotencyKeyInProgress
+	}
+	return rec, nil
+}
+
+func (s *idempotencyService) Complete(ctx context.Context, lease *model.IdempotencyRecord, statusCode int, contentType string, response []byte) error {
+	rec := *lease
+	rec.StatusCode, rec.ContentType, rec.Response = statusCode, contentType, response
+	return s.repo.Complete(ctx, &rec)
+}
+
+func (s *idempotencyService) Abort(ctx context.Context, lease *model.IdempotencyRecord) error {
+	return s.repo.Delete(ctx, lease)
+}
+
+func (s *idempotencyService) PurgeExpired(ctx context.Context) (int64, error) {
+	return s.repo.DeleteExpired(ctx, time.Now())
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// memorySubscriptions keeps subscriptions and their child records in memory.
+// Only what the tested services call is implemented, GetByFilter follows the SQL filter.
+type memorySubscriptions struct {
+	port.SubscriptionRepository
+
+	mu        sync.Mutex
+	subs      map[uuid.UUID]*model.Subscription
+	members   []*model.SubscriptionMember
+	changes   []*model.PriceChange
+	discounts []*model.Discount
+}
+
+func newMemorySubscriptions(subs ...*model.Subscription) *memorySubscriptions {
+	r := &memorySubscriptions{subs: make(map[uuid.UUID]*model.Subscription)}
+	for _, sub := range subs {
+		r.subs[sub.ID] = sub
+	}
+	return r
+}
+
+func (r *memorySubscriptions) GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub, ok := r.subs[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *sub
+	return &copied, nil
+}
+
+func (r *memorySubscriptions) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	return r.GetByID(ctx, id)
+}
+
+func (r *memorySubscriptions) Update(ctx context.Context, sub *model.Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *sub
+	r.subs[sub.ID] = &stored
+	return nil
+}
+
+func (r *memorySubscriptions) GetByIDsForUpdate(ctx context.Context, ids []uuid.UUID) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+	for _, id := range ids {
+		if sub, _ := r.GetByID(ctx, id); sub != nil {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+// ExecBatch only creates subscriptions, which is all imports need.
+func (r *memorySubscriptions) ExecBatch(ctx context.Context, ops []model.BatchOperation, atomic bool) ([]error, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, op := range ops {
+		stored := *op.Subscription
+		r.subs[stored.ID] = &stored
+	}
+	return make([]error, len(ops)), nil
+}
+
+func (r *memorySubscriptions) LockUser(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+func (r *memorySubscriptions) ListByUserAndService(ctx context.Context, userID uuid.UUID, serviceName string) ([]*model.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var subs []*model.Subscription
+	for _, sub := range r.subs {
+		if !sub.IsDeleted && sub.UserID == userID && normalizeServiceName(sub.ServiceName) == normalizeServiceName(serviceName) {
+			copied := *sub
+			subs = append(subs, &copied)
+		}
+	}
+	return subs, nil
+}
+
+func (r *memorySubscriptions) GetByFilter(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) ([]*model.Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var subs []*model.Subscription
+	for _, sub := range r.subs {
+		deletedEnded := sub.DeletionReason == model.DeletionEnded && sub.DeletedAt != nil && !sub.DeletedAt.Before(from)
+		switch {
+		case sub.IsDeleted && !(includeDeleted && deletedEnded),
+			sub.StartDate.After(to),
+			sub.EndDate != nil && sub.EndDate.Before(from),
+			userID != nil && sub.UserID != *userID && !r.isMember(sub.ID, *userID),
+			serviceName != nil && sub.ServiceName != *serviceName,
+			currency != nil && sub.Price.Currency != *currency:
+			continue
+		}
+		copied := *sub
+		subs = append(subs, &copied)
+	}
+	slices.SortFunc(subs, func(a, b *model.Subscription) int {
+		return a.StartDate.Compare(b.StartDate)
+	})
+	return subs, nil
+}
+
+func (r *memorySubscriptions) isMember(subscriptionID, userID uuid.UUID) bool {
+	for _, m := range r.members {
+		if m.SubscriptionID == subscriptionID && m.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memorySubscriptions) ListPriceChanges(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.PriceChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return childrenOf(r.changes, subscriptionIDs, func(c *model.PriceChange) uuid.UUID { return c.SubscriptionID }), nil
+}
+
+func (r *memorySubscriptions) ListMembers(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.SubscriptionMember, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return childrenOf(r.members, subscriptionIDs, func(m *model.SubscriptionMember) uuid.UUID { return m.SubscriptionID }), nil
+}
+
+func (r *memorySubscriptions) ListDiscounts(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.Discount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return childrenOf(r.discounts, subscriptionIDs, func(d *model.Discount) uuid.UUID { return d.SubscriptionID }), nil
+}
+
+func childrenOf[T any](records []T, subscriptionIDs []uuid.UUID, parent func(T) uuid.UUID) []T {
+	var found []T
+	for _, rec := range records {
+		if slices.Contains(subscriptionIDs, parent(rec)) {
+			found = append(found, rec)
+		}
+	}
+	return found
+}
+
+// memoryAudit and memoryOutbox only collect what is appended.
+type memoryAudit struct {
+	port.AuditRepository
+	entries []*model.AuditEntry
+}
+
+func (a *memoryAudit) Append(ctx context.Context, entries ...*model.AuditEntry) error {
+	a.entries = append(a.entries, entries...)
+	return nil
+}
+
+type memoryOutbox struct {
+	port.OutboxRepository
+	events []model.Event
+}
+
+func (o *memoryOutbox) Append(ctx context.Context, events ...model.Event) error {
+	o.events = append(o.events, events...)
+	return nil
+}
+
+// directTx runs fn without a transaction.
+type directTx struct{}
+
+func (directTx) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func newTestService(repo *memorySubscriptions) (port.SubscriptionService, *memoryAudit) {
+	audit := &memoryAudit{}
+	return NewSubscriptionService(repo, audit, &memoryOutbox{}, directTx{}), audit
+}
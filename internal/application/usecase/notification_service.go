@@ -0,0 +1,145 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+const defaultReminderDays = 3
+
+type notificationService struct {
+	repo     port.NotificationRepository
+	subs     port.SubscriptionRepository
+	channels []port.ReminderChannel
+}
+
+func NewNotificationService(repo port.NotificationRepository, subs port.SubscriptionRepository, channels ...port.ReminderChannel) port.NotificationService {
+	return &notificationService{repo: repo, subs: subs, channels: channels}
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil || prefs != nil {
+		return prefs, err
+	}
+	return &model.NotificationPreferences{
+		UserID:            userID,
+		RenewalDaysBefore: defaultReminderDays,
+		TrialDaysBefore:   defaultReminderDays,
+	}, nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, prefs *model.NotificationPreferences) error {
+	prefs.UpdatedAt = time.Now().UTC()
+	return s.repo.SavePreferences(ctx, prefs)
+}
+
+// SendReminders keeps going after failures of single reminders and returns them joined.
+func (s *notificationService) SendReminders(ctx context.Context) (int, error) {
+	prefs, err := s.repo.ListEnabledPreferences(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now().UTC()
+	var (
+		sent int
+		errs []error
+	)
+	for _, p := range prefs {
+		reminders, err := s.upcomingReminders(ctx, p, now)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, r := range reminders {
+			for _, ch := range s.channels {
+				ok, err := s.send(ctx, ch, p, r, now)
+				if err != nil {
+					errs = append(errs, err)
+				}
+				if ok {
+					sent++
+				}
+			}
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// upcomingReminders finds charges of the user's subscriptions within the
+// notice periods. A trial end replaces the renewal reminder for the same charge.
+func (s *notificationService) upcomingReminders(ctx context.Context, p *model.NotificationPreferences, now time.Time) ([]model.Reminder, error) {
+	var subs []*model.Subscription
+	err := s.subs.Stream(ctx, &p.UserID, nil, func(sub *model.Subscription) error {
+		subs = append(subs, sub)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	billing, err := loadBillingData(ctx, s.subs, subs)
+	if err != nil {
+		return nil, err
+	}
+
+	var reminders []model.Reminder
+	add := func(kind model.ReminderKind, sub *model.Subscription, date time.Time) error {
+		amount, err := billing.charge(sub, date, &p.UserID)
+		if err != nil {
+			return err
+		}
+		reminders = append(reminders, model.Reminder{Kind: kind, Subscription: sub, Date: date, Amount: amount})
+		return nil
+	}
+
+	for _, sub := range subs {
+		var trialEnd time.Time
+		if p.TrialDaysBefore > 0 && sub.TrialEndDate != nil {
+			end := monthStart(*sub.TrialEndDate)
+			if end.After(now) && !end.After(now.AddDate(0, 0, p.TrialDaysBefore)) && isActiveIn(sub, end) {
+				trialEnd = end
+				if err := add(model.ReminderTrialEnd, sub, end); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if p.RenewalDaysBefore > 0 {
+			for _, month := range upcomingBillingMonths(sub, now, now.AddDate(0, 0, p.RenewalDaysBefore)) {
+				if month.Equal(trialEnd) {
+					continue
+				}
+				if err := add(model.ReminderRenewal, sub, month); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return reminders, nil
+}
+
+func (s *notificationService) send(ctx context.Context, ch port.ReminderChannel, p *model.NotificationPreferences, r model.Reminder, now time.Time) (bool, error) {
+	reserved, err := s.repo.ReserveReminder(ctx, ch.Name(), p.UserID, r, now)
+	if err != nil || !reserved {
+		return false, err
+	}
+
+	if err := ch.Send(ctx, p, r); err != nil {
+		// Не отправлено: снимаем отметку, чтобы повторить при следующем запуске
+		if releaseErr := s.repo.ReleaseReminder(ctx, ch.Name(), r); releaseErr != nil {
+			return false, releaseErr
+		}
+		if errors.Is(err, model.ErrNoRecipient) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, s.repo.MarkReminderSent(ctx, ch.Name(), r, time.Now().UTC())
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+)
+
+type OutboxOptions struct {
+	// Lease must be longer than publishing a whole batch.
+	Lease     time.Duration
+	BatchSize int
+	// A failed event is retried after BaseDelay, then twice as long after every failure, up to MaxDelay.
+	// After MaxAttempts failures it is given up.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+type outboxRelay struct {
+	outbox    port.OutboxRepository
+	publisher port.EventPublisher
+	tx        port.UnitOfWork
+	opts      OutboxOptions
+}
+
+// NewOutboxRelay publishes up to opts.BatchSize events per Relay call.
+func NewOutboxRelay(outbox port.OutboxRepository, publisher port.EventPublisher, tx port.UnitOfWork, opts OutboxOptions) port.OutboxRelay {
+	return &outboxRelay{outbox: outbox, publisher: publisher, tx: tx, opts: opts}
+}
+
+func (r *outboxRelay) Relay(ctx context.Context) (int, error) {
+	events, err := r.outbox.Claim(ctx, time.Now(), r.opts.Lease, r.opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var errs []error
+	for _, event := range events {
+		err := r.tx.Do(ctx, func(ctx context.Context) error {
+			if err := r.publisher.Publish(ctx, event.Event); err != nil {
+				return err
+			}
+			return r.outbox.MarkPublished(ctx, event.ID, time.Now())
+		})
+		if err == nil {
+			published++
+			continue
+		}
+
+		errs = append(errs, err)
+		now := time.Now()
+		attempts := event.Attempts + 1
+		var markErr error
+		if attempts >= r.opts.MaxAttempts {
+			markErr = r.outbox.MarkDead(ctx, event.ID, err.Error(), now)
+		} else {
+			markErr = r.outbox.MarkFailed(ctx, event.ID, err.Error(), now.Add(backoff(r.opts.BaseDelay, r.opts.MaxDelay, attempts)))
+		}
+		if markErr != nil {
+			// Событие останется под арендой и будет повторено после её окончания
+			return published, errors.Join(append(errs, markErr)...)
+		}
+	}
+	return published, errors.Join(errs...)
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// DefaultRecommendationRules returns the built-in savings rules.
+func DefaultRecommendationRules() []port.RecommendationRule {
+	return []port.RecommendationRule{
+		DuplicateServiceRule{},
+		YearlyPlanRule{},
+		PriceIncreaseRule{LookbackMonths: 6},
+		LongRunningRule{MinMonths: 12},
+	}
+}
+
+func yearlyPriceKey(serviceName, currency string) string {
+	return normalizeServiceName(serviceName) + "|" + currency
+}
+
+// monthlyPrice returns the current price of the subscription spread per month.
+func monthlyPrice(sub *model.Subscription, input port.RecommendationInput) (model.Money, error) {
+	price := priceAt(sub, input.PriceChanges[sub.ID], input.Now)
+	return price.MulRatio(1, int64(sub.BillingPeriod.Months()), model.RoundHalfUp)
+}
+
+// scoreByShare maps part/whole to a score in [min, 100].
+func scoreByShare(part, whole int64, min int) int {
+	if whole <= 0 {
+		return min
+	}
+	score := int64(min) + int64(100-min)*part/whole
+	if score > 100 {
+		return 100
+	}
+	return int(score)
+}
+
+// DuplicateServiceRule suggests cancelling overlapping subscriptions to the same service.
+type DuplicateServiceRule struct{}
+
+func (DuplicateServiceRule) Name() string { return "duplicate_service" }
+
+func (r DuplicateServiceRule) Evaluate(_ context.Context, input port.RecommendationInput) []model.Recommendation {
+	var result []model.Recommendation
+	for _, group := range findDuplicates(input.Subscriptions) {
+		// Оставляем самую дорогую подписку, остальные считаем лишними
+		ids := make([]uuid.UUID, 0, len(group.Subscriptions))
+		var savings, highest model.Money
+		valid := true
+		for _, sub := range group.Subscriptions {
+			ids = append(ids, sub.ID)
+			price, err := monthlyPrice(sub, input)
+			if err == nil {
+				err = addMoney(&savings, price)
+			}
+			if err != nil {
+				valid = false
+				break
+			}
+			if price.Amount > highest.Amount {
+				highest = price
+			}
+		}
+		if !valid {
+			continue
+		}
+		savings, err := savings.Sub(highest)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, model.Recommendation{
+			Rule:            r.Name(),
+			ServiceName:     group.ServiceName,
+			SubscriptionIDs: ids,
+			Score:           90,
+			MonthlySavings:  savings,
+			Explanation: fmt.Sprintf("You pay for %s %d times at once; keeping a single subscription saves %s %s per month.",
+				group.ServiceName, len(group.Subscriptions), savings, savings.Currency),
+		})
+	}
+	return result
+}
+
+// YearlyPlanRule suggests switching monthly subscriptions to a yearly plan
+// when a yearly plan of the same service is known to be cheaper.
+type YearlyPlanRule struct{}
+
+func (YearlyPlanRule) Name() string { return "yearly_plan" }
+
+func (r YearlyPlanRule) Evaluate(_ context.Context, input port.RecommendationInput) []model.Recommendation {
+	var result []model.Recommendation
+	for _, sub := range input.Subscriptions {
+		if sub.BillingPeriod == model.BillingPeriodYearly {
+			continue
+		}
+		yearly, ok := input.YearlyPrices[yearlyPriceKey(sub.ServiceName, sub.Price.Currency)]
+		if !ok {
+			continue
+		}
+
+		price := priceAt(sub, input.PriceChanges[sub.ID], input.Now)
+		paidPerYear, err := price.Mul(int64(12 / sub.BillingPeriod.Months()))
+		if err != nil {
+			continue
+		}
+		overpay, err := paidPerYear.Sub(yearly)
+		if err != nil || overpay.Amount <= 0 {
+			continue
+		}
+		savings, err := overpay.MulRatio(1, 12, model.RoundDown)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, model.Recommendation{
+			Rule:            r.Name(),
+			ServiceName:     sub.ServiceName,
+			SubscriptionIDs: []uuid.UUID{sub.ID},
+			Score:           scoreByShare(overpay.Amount, paidPerYear.Amount, 40),
+			MonthlySavings:  savings,
+			Explanation: fmt.Sprintf("A yearly %s plan costs %s %s, while you pay %s %s per year on the %s plan.",
+				sub.ServiceName, yearly, yearly.Currency, paidPerYear, paidPerYear.Currency, sub.BillingPeriod),
+		})
+	}
+	return result
+}
+
+// PriceIncreaseRule flags subscriptions whose price rose within the last LookbackMonths.
+type PriceIncreaseRule struct {
+	LookbackMonths int
+}
+
+func (PriceIncreaseRule) Name() string { return "price_increase" }
+
+func (r PriceIncreaseRule) Evaluate(_ context.Context, input port.RecommendationInput) []model.Recommendation {
+	var result []model.Recommendation
+	since := input.Now.AddDate(0, -r.LookbackMonths, 0)
+	for _, sub := range input.Subscriptions {
+		if sub.StartDate.After(since) {
+			continue
+		}
+		before := priceAt(sub, input.PriceChanges[sub.ID], since)
+		now := priceAt(sub, input.PriceChanges[sub.ID], input.Now)
+		increase, err := now.Sub(before)
+		if err != nil || increase.Amount <= 0 {
+			continue
+		}
+		savings, err := increase.MulRatio(1, int64(sub.BillingPeriod.Months()), model.RoundHalfUp)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, model.Recommendation{
+			Rule:            r.Name(),
+			ServiceName:     sub.ServiceName,
+			SubscriptionIDs: []uuid.UUID{sub.ID},
+			Score:           scoreByShare(increase.Amount, before.Amount, 30),
+			MonthlySavings:  savings,
+			Explanation: fmt.Sprintf("%s price rose from %s to %s %s within the last %d months; consider a cheaper plan or an alternative.",
+				sub.ServiceName, before, now, now.Currency, r.LookbackMonths),
+		})
+	}
+	return result
+}
+
+// LongRunningRule flags open-ended subscriptions running for at least MinMonths.
+type LongRunningRule struct {
+	MinMonths int
+}
+
+func (LongRunningRule) Name() string { return "long_running" }
+
+func (r LongRunningRule) Evaluate(_ context.Context, input port.RecommendationInput) []model.Recommendation {
+	var result []model.Recommendation
+	for _, sub := range input.Subscriptions {
+		if sub.EndDate != nil {
+			continue
+		}
+		age := monthsDiff(monthStart(sub.StartDate), input.Now)
+		if age < r.MinMonths {
+			continue
+		}
+		price, err := monthlyPrice(sub, input)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, model.Recommendation{
+			Rule:            r.Name(),
+			ServiceName:     sub.ServiceName,
+			SubscriptionIDs: []uuid.UUID{sub.ID},
+			Score:           scoreByShare(int64(age), int64(age+r.MinMonths*4), 10),
+			MonthlySavings:  price,
+			Explanation: fmt.Sprintf("%s has been running for %d months without an end date; check whether you still use it.",
+				sub.ServiceName, age),
+		})
+	}
+	return result
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type recommendationService struct {
+	repo  port.SubscriptionRepository
+	rules []port.RecommendationRule
+}
+
+func NewRecommendationService(repo port.SubscriptionRepository, rules []port.RecommendationRule) port.RecommendationService {
+	return &recommendationService{repo: repo, rules: rules}
+}
+
+func (s *recommendationService) Recommend(ctx context.Context, userID uuid.UUID) ([]model.Recommendation, error) {
+	now := monthStart(time.Now())
+
+	shared, err := s.repo.GetByFilter(ctx, &userID, nil, nil, now, now, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// Советуем только по подпискам, которые пользователь оплачивает сам
+	subs := make([]*model.Subscription, 0, len(shared))
+	for _, sub := range shared {
+		if sub.UserID == userID {
+			subs = append(subs, sub)
+		}
+	}
+
+	input := port.RecommendationInput{
+		UserID:        userID,
+		Now:           now,
+		Subscriptions: subs,
+		YearlyPrices:  make(map[string]model.Money),
+	}
+
+	if len(subs) > 0 {
+		ids := make([]uuid.UUID, 0, len(subs))
+		for _, sub := range subs {
+			ids = append(ids, sub.ID)
+		}
+		changes, err := s.repo.ListPriceChanges(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		input.PriceChanges = groupPriceChanges(changes)
+
+		prices, err := s.repo.LowestPrices(ctx, model.BillingPeriodYearly)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range prices {
+			input.YearlyPrices[yearlyPriceKey(p.ServiceName, p.Price.Currency)] = p.Price
+		}
+	}
+
+	var recommendations []model.Recommendation
+	for _, rule := range s.rules {
+		recommendations = append(recommendations, rule.Evaluate(ctx, input)...)
+	}
+
+	sort.SliceStable(recommendations, func(i, j int) bool {
+		if recommendations[i].Score != recommendations[j].Score {
+			return recommendations[i].Score > recommendations[j].Score
+		}
+		return recommendations[i].MonthlySavings.Amount > recommendations[j].MonthlySavings.Amount
+	})
+
+	return recommendations, nil
+}
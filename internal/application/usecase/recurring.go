@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// cadence is the expected spacing in days between charges of a billing period.
+type cadence struct {
+	period     model.BillingPeriod
+	days       int
+	minDays    int
+	maxDays    int
+	minCharges int
+}
+
+var cadences = []cadence{
+	{period: model.BillingPeriodMonthly, days: 30, minDays: 25, maxDays: 35, minCharges: 3},
+	{period: model.BillingPeriodQuarterly, days: 91, minDays: 83, maxDays: 99, minCharges: 2},
+	{period: model.BillingPeriodYearly, days: 365, minDays: 350, maxDays: 380, minCharges: 2},
+}
+
+// amountTolerancePercent is how much charges of one subscription may differ,
+// so that small price changes don't split the series.
+const amountTolerancePercent = 20
+
+// merchantKey makes bank descriptions of the same merchant comparable by
+// dropping case, punctuation and tokens with digits (card numbers, references, dates).
+func merchantKey(description string) string {
+	var tokens []string
+	for _, token := range merchantTokens(description) {
+		tokens = append(tokens, strings.ToLower(token))
+	}
+	return strings.Join(tokens, " ")
+}
+
+func merchantTokens(description string) []string {
+	fields := strings.FieldsFunc(description, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.' && r != '&'
+	})
+	tokens := fields[:0]
+	for _, field := range fields {
+		field = strings.Trim(field, ".")
+		if field == "" || strings.IndexFunc(field, unicode.IsDigit) >= 0 {
+			continue
+		}
+		tokens = append(tokens, field)
+	}
+	return tokens
+}
+
+// merchantServiceName turns a bank description into a readable service name,
+// e.g. "NETFLIX.COM 4829 AMSTERDAM" becomes "Netflix.com Amsterdam".
+func merchantServiceName(description string) string {
+	tokens := merchantTokens(description)
+	for i, token := range tokens {
+		if strings.ToUpper(token) != token {
+			continue
+		}
+		runes := []rune(strings.ToLower(token))
+		runes[0] = unicode.ToUpper(runes[0])
+		tokens[i] = string(runes)
+	}
+	return strings.Join(tokens, " ")
+}
+
+// detectRecurring finds series of charges from one merchant with a similar
+// amount and a regular monthly, quarterly or yearly cadence.
+func detectRecurring(transactions []model.Transaction) []model.SubscriptionCandidate {
+	var statementEnd time.Time
+	groups := make(map[string][]model.Transaction)
+	for _, tx := range transactions {
+		if tx.Date.After(statementEnd) {
+			statementEnd = tx.Date
+		}
+		if !tx.Amount.IsNegative() {
+			continue
+		}
+		key := merchantKey(tx.Description)
+		if key == "" {
+			continue
+		}
+		tx.Amount = tx.Amount.Neg()
+		groups[key+"|"+tx.Amount.Currency] = append(groups[key+"|"+tx.Amount.Currency], tx)
+	}
+
+	var candidates []model.SubscriptionCandidate
+	for _, group := range groups {
+		for _, series := range splitByAmount(group) {
+			if candidate, ok := recurringCandidate(series, statementEnd); ok {
+				candidates = append(candidates, candidate)
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Confidence != candidates[j].Confidence {
+			return candidates[i].Confidence > candidates[j].Confidence
+		}
+		return candidates[i].ServiceName < candidates[j].ServiceName
+	})
+	return candidates
+}
+
+// splitByAmount separates charges of one merchant into series of similar
+// amounts, e.g. two different plans billed by the same store.
+func splitByAmount(charges []model.Transaction) [][]model.Transaction {
+	sort.Slice(charges, func(i, j int) bool { return charges[i].Amount.Amount < charges[j].Amount.Amount })
+
+	var series [][]model.Transaction
+	start := 0
+	for i := 1; i <= len(charges); i++ {
+		if i == len(charges) || charges[i].Amount.Amount*100 > charges[i-1].Amount.Amount*(100+amountTolerancePercent) {
+			series = append(series, charges[start:i])
+			start = i
+		}
+	}
+	return series
+}
+
+func recurringCandidate(charges []model.Transaction, statementEnd time.Time) (model.SubscriptionCandidate, bool) {
+	if len(charges) < 2 {
+		return model.SubscriptionCandidate{}, false
+	}
+	sort.Slice(charges, func(i, j int) bool { return charges[i].Date.Before(charges[j].Date) })
+
+	intervals := make([]int, 0, len(charges)-1)
+	for i := 1; i < len(charges); i++ {
+		intervals = append(intervals, int(charges[i].Date.Sub(charges[i-1].Date).Hours()/24))
+	}
+	sorted := append([]int(nil), intervals...)
+	sort.Ints(sorted)
+	median := sorted[len(sorted)/2]
+
+	for _, cad := range cadences {
+		if median < cad.minDays || median > cad.maxDays {
+			continue
+		}
+		if len(charges) < cad.minCharges {
+			return model.SubscriptionCandidate{}, false
+		}
+
+		regular := 0
+		for _, days := range intervals {
+			if days >= cad.minDays && days <= cad.maxDays {
+				regular++
+			}
+		}
+		// Допускаем единичные сбои графика, например пропущенный месяц
+		if regular*4 < len(intervals)*3 {
+			return model.SubscriptionCandidate{}, false
+		}
+
+		first, last := charges[0], charges[len(charges)-1]
+		confidence := 50 + 10*(len(charges)-cad.minCharges)
+		if first.Amount == last.Amount {
+			confidence += 10
+		}
+		confidence = min(confidence, 100) * regular / len(intervals)
+
+		candidate := model.SubscriptionCandidate{
+			ServiceName:   merchantServiceName(last.Description),
+			Merchant:      last.Description,
+			Price:         last.Amount,
+			BillingPeriod: cad.period,
+			StartDate:     monthStart(first.Date),
+			LastCharge:    last.Date,
+			Occurrences:   len(charges),
+			Confidence:    confidence,
+		}
+		// Списания прекратились задолго до конца выписки — похоже, подписка отменена
+		if statementEnd.Sub(last.Date) > time.Duration(cad.days*3/2)*24*time.Hour {
+			end := monthStart(last.Date)
+			candidate.EndDate = &end
+		}
+		return candidate, true
+	}
+	return model.SubscriptionCandidate{}, false
+}
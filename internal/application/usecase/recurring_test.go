@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// charges returns charges of amount made after the given gaps in days, starting on 10 January 2025.
+func charges(description string, amount int64, gaps ...int) []model.Transaction {
+	day := time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC)
+	txs := []model.Transaction{{Date: day, Description: description, Amount: rub(amount)}}
+	for _, gap := range gaps {
+		day = day.AddDate(0, 0, gap)
+		txs = append(txs, model.Transaction{Date: day, Description: description, Amount: rub(amount)})
+	}
+	return txs
+}
+
+func TestSplitByAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		amounts []int64
+		want    [][]int64
+	}{
+		{"one amount", []int64{29900, 29900, 29900}, [][]int64{{29900, 29900, 29900}}},
+		{"price change within the tolerance", []int64{29900, 34900, 29900}, [][]int64{{29900, 29900, 34900}}},
+		{"two plans", []int64{99900, 29900, 99900, 29900}, [][]int64{{29900, 29900}, {99900, 99900}}},
+		{"just over the tolerance", []int64{10000, 12001}, [][]int64{{10000}, {12001}}},
+		{"at the tolerance", []int64{10000, 12000}, [][]int64{{10000, 12000}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var txs []model.Transaction
+			for _, amount := range tt.amounts {
+				txs = append(txs, model.Transaction{Amount: rub(amount)})
+			}
+			got := splitByAmount(txs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d series, want %d", len(got), len(tt.want))
+			}
+			for i, series := range tt.want {
+				if len(got[i]) != len(series) {
+					t.Fatalf("series %d has %d charges, want %d", i, len(got[i]), len(series))
+				}
+				for j, amount := range series {
+					if got[i][j].Amount.Amount != amount {
+						t.Errorf("series %d charge %d = %d, want %d", i, j, got[i][j].Amount.Amount, amount)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRecurringCandidate(t *testing.T) {
+	drifting := charges("SPOTIFY", 16900, 31, 28)
+	drifting[2].Amount = rub(19900)
+
+	tests := []struct {
+		name       string
+		charges    []model.Transaction
+		ok         bool
+		period     model.BillingPeriod
+		confidence int
+	}{
+		{"monthly minimum", charges("NETFLIX", 99900, 31, 28), true, model.BillingPeriodMonthly, 60},
+		{"more occurrences raise confidence", charges("NETFLIX", 99900, 31, 28, 31, 30), true, model.BillingPeriodMonthly, 80},
+		{"confidence is capped", charges("NETFLIX", 99900, 31, 28, 31, 30, 31, 30, 31, 31, 30), true, model.BillingPeriodMonthly, 100},
+		{"amount drift lowers confidence", drifting, true, model.BillingPeriodMonthly, 50},
+		{"too few monthly charges", charges("NETFLIX", 99900, 31), false, "", 0},
+		{"quarterly", charges("YANDEX", 59900, 90), true, model.BillingPeriodQuarterly, 60},
+		{"yearly", charges("ICLOUD", 299000, 365), true, model.BillingPeriodYearly, 60},
+		{"single charge", charges("ICLOUD", 299000), false, "", 0},
+		// Пропущенный месяц не меняет медиану, но снижает уверенность
+		{"skipped month", charges("NETFLIX", 99900, 31, 28, 62, 30, 31), true, model.BillingPeriodMonthly, 72},
+		{"median outside any cadence", charges("TAXI", 50000, 10, 50, 45, 60), false, "", 0},
+		{"too many irregular intervals", charges("TAXI", 50000, 30, 30, 45, 45, 30), false, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statementEnd := tt.charges[len(tt.charges)-1].Date
+			got, ok := recurringCandidate(tt.charges, statementEnd)
+			if ok != tt.ok {
+				t.Fatalf("recurringCandidate() ok = %v, want %v", ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if got.BillingPeriod != tt.period || got.Confidence != tt.confidence || got.Occurrences != len(tt.charges) {
+				t.Errorf("recurringCandidate() = %s confidence %d with %d occurrences, want %s confidence %d with %d",
+					got.BillingPeriod, got.Confidence, got.Occurrences, tt.period, tt.confidence, len(tt.charges))
+			}
+			if got.Price != tt.charges[len(tt.charges)-1].Amount || !got.StartDate.Equal(month(2025, time.January)) || got.EndDate != nil {
+				t.Errorf("recurringCandidate() = price %+v from %s until %v, want the last price from January", got.Price, got.StartDate, got.EndDate)
+			}
+		})
+	}
+}
+
+func TestRecurringCandidateEnded(t *testing.T) {
+	series := charges("NETFLIX", 99900, 31, 28)
+	last := series[len(series)-1].Date
+
+	// Полтора интервала без списаний ещё не означают отмену
+	if got, _ := recurringCandidate(series, last.AddDate(0, 0, 45)); got.EndDate != nil {
+		t.Errorf("EndDate = %s, want nil while the next charge may still come", got.EndDate)
+	}
+	got, _ := recurringCandidate(series, last.AddDate(0, 0, 46))
+	if got.EndDate == nil || !got.EndDate.Equal(month(2025, time.March)) {
+		t.Errorf("EndDate = %v, want March 2025", got.EndDate)
+	}
+}
+
+func TestDetectRecurring(t *testing.T) {
+	var txs []model.Transaction
+	// Номер карты и регистр в описании не мешают узнать продавца
+	for i, tx := range charges("NETFLIX.COM 4829", -99900, 31, 28, 31) {
+		if i%2 == 1 {
+			tx.Description = "Netflix.com *7731"
+		}
+		txs = append(txs, tx)
+	}
+	txs = append(txs, charges("APPLE.COM/BILL", -29900, 31, 28)...)
+	txs = append(txs, charges("APPLE.COM/BILL", -99000, 31, 28, 31, 30)...)
+	// Поступления и разовые покупки не считаются
+	txs = append(txs, charges("SALARY", 10000000, 31, 28, 31)...)
+	txs = append(txs, model.Transaction{Date: time.Date(2025, time.February, 2, 0, 0, 0, 0, time.UTC), Description: "OZON", Amount: rub(-150000)})
+
+	got := detectRecurring(txs)
+	want := []struct {
+		service     string
+		price       int64
+		occurrences int
+	}{
+		{"Apple.com Bill", 99000, 5},
+		{"Netflix.com", 99900, 4},
+		{"Apple.com Bill", 29900, 3},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d candidates, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if got[i].ServiceName != w.service || got[i].Price != rub(w.price) || got[i].Occurrences != w.occurrences {
+			t.Errorf("candidate %d = %q %+v x%d, want %q %d x%d", i,
+				got[i].ServiceName, got[i].Price, got[i].Occurrences, w.service, w.price, w.occurrences)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// renewalNamespace derives stable ids of renewal events, so a renewal
+// found by several scans is stored in the outbox once.
+var renewalNamespace = uuid.MustParse("5b0d7f5e-2f0c-4b7e-9d43-7c1e4a6a2f10")
+
+type renewalScanner struct {
+	subs   port.SubscriptionRepository
+	outbox port.OutboxRepository
+	notice time.Duration
+}
+
+// NewRenewalScanner reports charges happening within notice from now.
+func NewRenewalScanner(subs port.SubscriptionRepository, outbox port.OutboxRepository, notice time.Duration) port.RenewalScanner {
+	return &renewalScanner{subs: subs, outbox: outbox, notice: notice}
+}
+
+// ScanRenewals relies on charges happening on the first day of a billing month.
+func (s *renewalScanner) ScanRenewals(ctx context.Context) (int, error) {
+	now := time.Now().UTC()
+	until := now.Add(s.notice)
+
+	var events []model.Event
+	err := s.subs.Stream(ctx, nil, nil, func(sub *model.Subscription) error {
+		for _, month := range upcomingBillingMonths(sub, now, until) {
+			renewsAt := month
+			events = append(events, model.Event{
+				ID:           uuid.NewSHA1(renewalNamespace, []byte(sub.ID.String()+"/"+month.Format("2006-01"))),
+				Type:         model.EventSubscriptionRenewalUpcoming,
+				OccurredAt:   now,
+				Subscription: sub,
+				RenewsAt:     &renewsAt,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return len(events), s.outbox.Append(ctx, events...)
+}
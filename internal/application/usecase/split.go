@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// splitShares divides charge between the owner and members.
+// Fixed members are served first, then percentage members; the owner and
+// equal members split the rest, with the rounding remainder going to the owner.
+func splitShares(charge model.Money, owner uuid.UUID, members []*model.SubscriptionMember) (map[uuid.UUID]model.Money, error) {
+	if len(members) == 0 {
+		return map[uuid.UUID]model.Money{owner: charge}, nil
+	}
+
+	remaining := charge
+	shares := make(map[uuid.UUID]model.Money, len(members)+1)
+	var equal []uuid.UUID
+
+	take := func(userID uuid.UUID, amount model.Money) error {
+		amount, err := amount.Min(remaining)
+		if err != nil {
+			return err
+		}
+		shares[userID] = amount
+		remaining, err = remaining.Sub(amount)
+		return err
+	}
+
+	for _, m := range members {
+		if m.SplitType == model.SplitFixed {
+			if err := take(m.UserID, model.NewMoney(m.Value, charge.Currency)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, m := range members {
+		if m.SplitType == model.SplitPercentage {
+			amount, err := charge.MulRatio(m.Value, 100, model.RoundDown)
+			if err != nil {
+				return nil, err
+			}
+			if err := take(m.UserID, amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, m := range members {
+		if m.SplitType == model.SplitEqual {
+			equal = append(equal, m.UserID)
+		}
+	}
+
+	part, err := remaining.MulRatio(1, int64(len(equal)+1), model.RoundDown)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range equal {
+		if err := take(id, part); err != nil {
+			return nil, err
+		}
+	}
+	shares[owner] = remaining
+	return shares, nil
+}
+
+// shareOf returns the part of a month's breakdown attributed to userID. The gross
+// amount is split once and every member takes the same part of the other amounts,
+// the owner gets what's left, so the shares always add up to the total.
+func shareOf(total model.CostBreakdown, owner uuid.UUID, members []*model.SubscriptionMember, userID uuid.UUID) (model.CostBreakdown, error) {
+	// Полностью оплаченный скидкой месяц делим по сумме до скидки
+	base := total.Gross
+	if base.IsZero() {
+		base = total.BeforeDiscount
+	}
+	if base.IsZero() {
+		if userID == owner {
+			return total, nil
+		}
+		return model.CostBreakdown{}, nil
+	}
+	shares, err := splitShares(base, owner, members)
+	if err != nil {
+		return model.CostBreakdown{}, err
+	}
+	if userID != owner {
+		share, ok := shares[userID]
+		if !ok {
+			share = model.Money{Currency: base.Currency}
+		}
+		return proportion(total, share, base)
+	}
+
+	rest := total
+	for id, share := range shares {
+		if id == owner {
+			continue
+		}
+		part, err := proportion(total, share, base)
+		if err != nil {
+			return model.CostBreakdown{}, err
+		}
+		if rest, err = rest.Sub(part); err != nil {
+			return model.CostBreakdown{}, err
+		}
+	}
+	return rest, nil
+}
+
+// proportion returns the part share/base of total, rounded half up.
+func proportion(total model.CostBreakdown, share, base model.Money) (model.CostBreakdown, error) {
+	var part model.CostBreakdown
+	var err error
+	for _, p := range []struct {
+		dst    *model.Money
+		amount model.Money
+	}{
+		{&part.BeforeDiscount, total.BeforeDiscount},
+		{&part.Net, total.Net},
+		{&part.Gross, total.Gross},
+	} {
+		if *p.dst, err = p.amount.MulRatio(share.Amount, base.Amount, model.RoundHalfUp); err != nil {
+			return model.CostBreakdown{}, err
+		}
+	}
+	if part.Tax, err = part.Gross.Sub(part.Net); err != nil {
+		return model.CostBreakdown{}, err
+	}
+	if part.Discount, err = part.BeforeDiscount.Sub(part.Gross); err != nil {
+		return model.CostBreakdown{}, err
+	}
+	return part, nil
+}
+
+// groupMembers indexes members by subscription ID.
+func groupMembers(members []*model.SubscriptionMember) map[uuid.UUID][]*model.SubscriptionMember {
+	grouped := make(map[uuid.UUID][]*model.SubscriptionMember)
+	for _, m := range members {
+		grouped[m.SubscriptionID] = append(grouped[m.SubscriptionID], m)
+	}
+	return grouped
+}
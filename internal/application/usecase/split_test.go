@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+func rub(amount int64) model.Money {
+	return model.NewMoney(amount, "RUB")
+}
+
+func month(year int, m time.Month) time.Time {
+	return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func breakdownOf(before, discount, net, tax, gross int64) model.CostBreakdown {
+	return model.CostBreakdown{BeforeDiscount: rub(before), Discount: rub(discount), Net: rub(net), Tax: rub(tax), Gross: rub(gross)}
+}
+
+func TestBreakdownSharesTaxAndDiscount(t *testing.T) {
+	owner, fixed, equal, stranger := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	sub := &model.Subscription{
+		ID:               uuid.New(),
+		UserID:           owner,
+		Price:            rub(120000),
+		StartDate:        month(2025, time.January),
+		BillingPeriod:    model.BillingPeriodMonthly,
+		TaxRate:          2000,
+		PriceIncludesTax: true,
+	}
+	billing := billingData{
+		members: map[uuid.UUID][]*model.SubscriptionMember{sub.ID: {
+			{SubscriptionID: sub.ID, UserID: fixed, SplitType: model.SplitFixed, Value: 30000},
+			{SubscriptionID: sub.ID, UserID: equal, SplitType: model.SplitEqual},
+		}},
+		discounts: map[uuid.UUID][]*model.Discount{sub.ID: {
+			{SubscriptionID: sub.ID, Kind: model.DiscountPercentage, Value: 10, StartDate: month(2025, time.January)},
+		}},
+	}
+
+	// 1200.00 с НДС 20% и скидкой 10%: 1080.00 к оплате, из них 180.00 налог.
+	// Участник с фиксированной долей 300.00 платит свою часть налога и получает свою часть скидки
+	tests := []struct {
+		name   string
+		userID *uuid.UUID
+		want   model.CostBreakdown
+	}{
+		{"whole subscription", nil, breakdownOf(120000, 12000, 90000, 18000, 108000)},
+		{"fixed member", &fixed, breakdownOf(33333, 3333, 25000, 5000, 30000)},
+		{"equal member", &equal, breakdownOf(43333, 4333, 32500, 6500, 39000)},
+		{"owner takes the remainder", &owner, breakdownOf(43334, 4334, 32500, 6500, 39000)},
+		{"not a member", &stranger, breakdownOf(0, 0, 0, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := billing.breakdown(sub, month(2025, time.March), tt.userID)
+			if err != nil {
+				t.Fatalf("breakdown() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("breakdown() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShareOfAddsUpToTotal(t *testing.T) {
+	owner, a, b := uuid.New(), uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		total   model.CostBreakdown
+		members []*model.SubscriptionMember
+	}{
+		{
+			name:  "equal split with rounding",
+			total: breakdownOf(99999, 3333, 80000, 16666, 96666),
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitEqual},
+				{UserID: b, SplitType: model.SplitEqual},
+			},
+		},
+		{
+			name:  "percentages",
+			total: breakdownOf(77777, 0, 64814, 12963, 77777),
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitPercentage, Value: 33},
+				{UserID: b, SplitType: model.SplitPercentage, Value: 17},
+			},
+		},
+		{
+			name:  "fully discounted month is split by the price before discount",
+			total: breakdownOf(50000, 50000, 0, 0, 0),
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitPercentage, Value: 40},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sum model.CostBreakdown
+			for _, id := range []uuid.UUID{owner, a, b} {
+				share, err := shareOf(tt.total, owner, tt.members, id)
+				if err != nil {
+					t.Fatalf("shareOf() error = %v", err)
+				}
+				if share.Tax.IsNegative() || share.Discount.IsNegative() {
+					t.Errorf("share of %s = %+v, want non-negative tax and discount", id, share)
+				}
+				if sum, err = sum.Add(share); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+			if sum != tt.total {
+				t.Errorf("shares add up to %+v, want %+v", sum, tt.total)
+			}
+		})
+	}
+}
+
+func TestSplitShares(t *testing.T) {
+	owner, a, b, c := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	tests := []struct {
+		name    string
+		charge  int64
+		members []*model.SubscriptionMember
+		want    map[uuid.UUID]int64
+	}{
+		{
+			name:   "no members",
+			charge: 99900,
+			want:   map[uuid.UUID]int64{owner: 99900},
+		},
+		{
+			name:   "equal members and the owner",
+			charge: 100000,
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitEqual},
+				{UserID: b, SplitType: model.SplitEqual},
+			},
+			want: map[uuid.UUID]int64{a: 33333, b: 33333, owner: 33334},
+		},
+		{
+			name:   "fixed before percentage whatever the order",
+			charge: 100000,
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitPercentage, Value: 50},
+				{UserID: b, SplitType: model.SplitFixed, Value: 70000},
+			},
+			want: map[uuid.UUID]int64{b: 70000, a: 30000, owner: 0},
+		},
+		{
+			name:   "percentage of the full charge rounded down",
+			charge: 99999,
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitPercentage, Value: 33},
+			},
+			want: map[uuid.UUID]int64{a: 32999, owner: 67000},
+		},
+		{
+			name:   "percentages over 100 are capped by what is left",
+			charge: 100000,
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitPercentage, Value: 70},
+				{UserID: b, SplitType: model.SplitPercentage, Value: 50},
+				{UserID: c, SplitType: model.SplitEqual},
+			},
+			want: map[uuid.UUID]int64{a: 70000, b: 30000, c: 0, owner: 0},
+		},
+		{
+			name:   "fixed amount larger than the charge",
+			charge: 50000,
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitFixed, Value: 80000},
+				{UserID: b, SplitType: model.SplitFixed, Value: 10000},
+			},
+			want: map[uuid.UUID]int64{a: 50000, b: 0, owner: 0},
+		},
+		{
+			name:   "equal members share what fixed and percentage leave",
+			charge: 100000,
+			members: []*model.SubscriptionMember{
+				{UserID: a, SplitType: model.SplitFixed, Value: 10000},
+				{UserID: b, SplitType: model.SplitPercentage, Value: 20},
+				{UserID: c, SplitType: model.SplitEqual},
+			},
+			want: map[uuid.UUID]int64{a: 10000, b: 20000, c: 35000, owner: 35000},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := splitShares(rub(tt.charge), owner, tt.members)
+			if err != nil {
+				t.Fatalf("splitShares() error = %v", err)
+			}
+			if len(shares) != len(tt.want) {
+				t.Errorf("got %d shares, want %d", len(shares), len(tt.want))
+			}
+			var sum model.Money
+			for id, want := range tt.want {
+				if got := shares[id]; got != rub(want) {
+					t.Errorf("share of %s = %+v, want %d RUB", id, got, want)
+				}
+				if sum, err = sum.Add(shares[id]); err != nil {
+					t.Fatalf("Add() error = %v", err)
+				}
+			}
+			if sum != rub(tt.charge) {
+				t.Errorf("shares add up to %+v, want %d", sum, tt.charge)
+			}
+		})
+	}
+}
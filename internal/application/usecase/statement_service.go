@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type statementService struct {
+	repo port.SubscriptionRepository
+}
+
+func NewStatementService(repo port.SubscriptionRepository) port.StatementService {
+	return &statementService{repo: repo}
+}
+
+func (s *statementService) DetectSubscriptions(ctx context.Context, userID uuid.UUID, transactions []model.Transaction) ([]model.SubscriptionCandidate, error) {
+	candidates := detectRecurring(transactions)
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	var existing []*model.Subscription
+	err := s.repo.Stream(ctx, &userID, nil, func(sub *model.Subscription) error {
+		existing = append(existing, sub)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range candidates {
+		merchant := compactName(merchantKey(candidates[i].Merchant))
+		for _, sub := range existing {
+			name := compactName(normalizeServiceName(sub.ServiceName))
+			// "Netflix" в учёте и "NETFLIX.COM AMSTERDAM" в выписке — одна подписка
+			if len(name) >= 3 && (strings.Contains(merchant, name) || strings.Contains(name, merchant)) {
+				id := sub.ID
+				candidates[i].ExistingSubscriptionID = &id
+				break
+			}
+		}
+	}
+	return candidates, nil
+}
+
+// compactName drops separators so "you tube" and "youtube" compare equal.
+func compactName(name string) string {
+	return strings.Join(strings.Fields(name), "")
+}
@@ -3,6 +3,9 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/Babushkin05/subscription-organizer/internal/application/port"
@@ -10,86 +13,1010 @@ import (
 	"github.com/google/uuid"
 )
 
+// costReportChunk is the number of subscriptions whose billing data is loaded at once while streaming a cost report.
+const costReportChunk = 500
+
 type subscriptionService struct {
-	repo port.SubscriptionRepository
+	repo   port.SubscriptionRepository
+	audit  port.AuditRepository
+	outbox port.OutboxRepository
+	tx     port.UnitOfWork
+}
+
+func NewSubscriptionService(repo port.SubscriptionRepository, audit port.AuditRepository, outbox port.OutboxRepository, tx port.UnitOfWork) port.SubscriptionService {
+	return &subscriptionService{repo: repo, audit: audit, outbox: outbox, tx: tx}
 }
 
-func NewSubscriptionService(repo port.SubscriptionRepository) port.SubscriptionService {
-	return &subscriptionService{repo: repo}
+// record writes the changes to the audit log and matching events to the outbox.
+// It must run in the transaction of the changes.
+func (s *subscriptionService) record(ctx context.Context, entries ...*model.AuditEntry) error {
+	if err := s.audit.Append(ctx, entries...); err != nil {
+		return err
+	}
+
+	events := make([]model.Event, 0, len(entries))
+	for _, entry := range entries {
+		eventType, ok := auditEventTypes[entry.Action]
+		if !ok {
+			// Участники и скидки не публикуются
+			continue
+		}
+		events = append(events, model.Event{
+			ID:           uuid.New(),
+			Type:         eventType,
+			OccurredAt:   entry.CreatedAt,
+			Subscription: entry.After,
+			PriceChange:  entry.PriceChange,
+		})
+	}
+	return s.outbox.Append(ctx, events...)
+}
+
+func (s *subscriptionService) CreateSubscription(ctx context.Context, sub *model.Subscription, strict bool) error {
+	sub.CancelledAt, sub.CancelledEndDate = nil, nil
+	sub.Status = sub.StatusAt(time.Now())
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		if strict {
+			if err := s.checkOverlaps(ctx, sub); err != nil {
+				return err
+			}
+		}
+		if err := s.repo.Create(ctx, sub); err != nil {
+			return err
+		}
+		return s.record(ctx, newAuditEntry(ctx, model.AuditCreate, nil, sub))
+	})
+}
+
+func (s *subscriptionService) ImportSubscriptions(ctx context.Context, subs []*model.Subscription, dryRun, strict bool) error {
+	if dryRun || len(subs) == 0 {
+		return nil
+	}
+
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		if strict {
+			for i, sub := range subs {
+				if err := s.checkOverlaps(ctx, sub); err != nil {
+					return err
+				}
+				// Импортируемые подписки ещё не в базе, сверяем их между собой
+				for _, other := range subs[:i] {
+					if other.UserID == sub.UserID && normalizeServiceName(other.ServiceName) == normalizeServiceName(sub.ServiceName) && rangesOverlap(sub, other) {
+						return fmt.Errorf("%w: %s", model.ErrSubscriptionOverlap, other.ID)
+					}
+				}
+			}
+		}
+
+		ops := make([]model.BatchOperation, 0, len(subs))
+		for _, sub := range subs {
+			ops = append(ops, model.BatchOperation{Action: model.BatchCreate, Subscription: sub})
+		}
+		errs, err := s.ExecBatch(ctx, ops, model.BatchAtomic)
+		if err != nil {
+			return err
+		}
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-func (s *subscriptionService) CreateSubscription(ctx context.Context, sub *model.Subscription) error {
-	return s.repo.Create(ctx, sub)
+func (s *subscriptionService) ExecBatch(ctx context.Context, ops []model.BatchOperation, mode model.BatchMode) ([]error, error) {
+	if len(ops) == 0 {
+		return nil, nil
+	}
+
+	var errs []error
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var ids []uuid.UUID
+		for _, op := range ops {
+			switch op.Action {
+			case model.BatchUpdate:
+				ids = append(ids, op.Subscription.ID)
+			case model.BatchDelete:
+				ids = append(ids, op.ID)
+			}
+		}
+		existing, err := s.repo.GetByIDsForUpdate(ctx, ids)
+		if err != nil {
+			return err
+		}
+		before := make(map[uuid.UUID]*model.Subscription, len(existing))
+		for _, sub := range existing {
+			before[sub.ID] = sub
+		}
+		now := time.Now()
+		errs = make([]error, len(ops))
+		valid := make([]model.BatchOperation, 0, len(ops))
+		positions := make([]int, 0, len(ops))
+		for i, op := range ops {
+			switch op.Action {
+			case model.BatchCreate:
+				op.Subscription.CancelledAt, op.Subscription.CancelledEndDate = nil, nil
+				op.Subscription.Status = op.Subscription.StatusAt(now)
+			case model.BatchUpdate:
+				if errs[i] = s.checkCurrencyChange(ctx, before[op.Subscription.ID], op.Subscription); errs[i] != nil {
+					if mode == model.BatchAtomic {
+						return nil
+					}
+					continue
+				}
+				keepCancellation(op.Subscription, before[op.Subscription.ID])
+				op.Subscription.Status = op.Subscription.StatusAt(now)
+			}
+			valid = append(valid, op)
+			positions = append(positions, i)
+		}
+
+		execErrs, err := s.repo.ExecBatch(ctx, valid, mode == model.BatchAtomic)
+		if err != nil {
+			return err
+		}
+		failed := false
+		for i, err := range execErrs {
+			errs[positions[i]] = err
+			failed = failed || err != nil
+		}
+		if failed && mode == model.BatchAtomic {
+			// Пакет откатан целиком, записывать в журнал нечего
+			return nil
+		}
+
+		entries := make([]*model.AuditEntry, 0, len(ops))
+		for i, op := range ops {
+			if errs[i] != nil {
+				continue
+			}
+			switch op.Action {
+			case model.BatchCreate:
+				entries = append(entries, newAuditEntry(ctx, model.AuditCreate, nil, op.Subscription))
+			case model.BatchUpdate:
+				entries = append(entries, newAuditEntry(ctx, model.AuditUpdate, before[op.Subscription.ID], op.Subscription))
+			case model.BatchDelete:
+				prev, ok := before[op.ID]
+				if !ok {
+					continue
+				}
+				deleted := *prev
+				deleted.IsDeleted = true
+				deleted.DeletionReason = op.Reason
+				entries = append(entries, newAuditEntry(ctx, model.AuditDelete, prev, &deleted))
+			}
+		}
+		return s.record(ctx, entries...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
 }
 
 func (s *subscriptionService) GetSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *subscriptionService) UpdateSubscription(ctx context.Context, sub *model.Subscription) error {
-	return s.repo.Update(ctx, sub)
+// UpdateSubscription keeps the deletion flag, deleted subscriptions are brought back by RestoreSubscription.
+// The status follows the new dates.
+func (s *subscriptionService) UpdateSubscription(ctx context.Context, sub *model.Subscription, strict bool) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByIDForUpdate(ctx, sub.ID)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return model.ErrSubscriptionNotFound
+		}
+		if strict {
+			if err := s.checkOverlaps(ctx, sub); err != nil {
+				return err
+			}
+		}
+		if err := s.checkCurrencyChange(ctx, before, sub); err != nil {
+			return err
+		}
+		sub.IsDeleted = before.IsDeleted
+		sub.DeletionReason = before.DeletionReason
+		keepCancellation(sub, before)
+		sub.Status = sub.StatusAt(time.Now())
+		if err := s.repo.Update(ctx, sub); err != nil {
+			return err
+		}
+		return s.record(ctx, newAuditEntry(ctx, model.AuditUpdate, before, sub))
+	})
+}
+
+// DeleteSubscription deletes the subscription for the given reason. Deleting it again
+// only changes the reason, the deletion time stays the same.
+func (s *subscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID, reason model.DeletionReason) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return model.ErrSubscriptionNotFound
+		}
+		if before.IsDeleted && before.DeletionReason == reason {
+			return nil
+		}
+		sub := *before
+		sub.IsDeleted = true
+		sub.DeletionReason = reason
+		if err := s.repo.Update(ctx, &sub); err != nil {
+			return err
+		}
+		return s.record(ctx, newAuditEntry(ctx, model.AuditDelete, before, &sub))
+	})
 }
 
-func (s *subscriptionService) DeleteSubscription(ctx context.Context, id uuid.UUID) error {
-	sub, err := s.repo.GetByID(ctx, id)
+func (s *subscriptionService) RestoreSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	var sub model.Subscription
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil {
+			return model.ErrSubscriptionNotFound
+		}
+		sub = *before
+		if !before.IsDeleted {
+			return nil
+		}
+		sub.IsDeleted = false
+		sub.DeletionReason = ""
+		sub.Status = sub.StatusAt(time.Now())
+		if err := s.repo.Update(ctx, &sub); err != nil {
+			return err
+		}
+		return s.record(ctx, newAuditEntry(ctx, model.AuditRestore, before, &sub))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// CancelSubscription stops the subscription.
+// At period end it runs until the end of the billing period in progress, during a trial
+// it ends before the first charge, and it keeps its status until then.
+// Immediately it ends now: the current month stays in the costs as already paid,
+// later months are dropped. A pending cancellation can still be made immediate.
+// Cancelling again is a no-op, subscriptions that haven't started or have ended can't be cancelled.
+func (s *subscriptionService) CancelSubscription(ctx context.Context, id uuid.UUID, mode model.CancelMode) (*model.Subscription, error) {
+	return s.transition(ctx, id, model.AuditCancel, func(sub *model.Subscription, now time.Time) (bool, error) {
+		switch {
+		case sub.Status == model.StatusScheduled:
+			return false, fmt.Errorf("%w: the subscription hasn't started yet, delete it instead", model.ErrInvalidTransition)
+		case sub.Status.HasEnded():
+			return false, fmt.Errorf("%w: the subscription has already ended", model.ErrInvalidTransition)
+		case sub.CancelledAt != nil && mode != model.CancelImmediately:
+			return false, nil
+		}
+
+		if sub.CancelledAt == nil {
+			sub.CancelledEndDate = sub.EndDate
+		}
+		end := periodEnd(sub, monthStart(now))
+		if mode == model.CancelImmediately {
+			end = monthStart(now)
+			endedAt := now
+			sub.EndedAt = &endedAt
+		}
+		if sub.EndDate == nil || sub.EndDate.After(end) {
+			sub.EndDate = &end
+		}
+		if sub.CancelledAt == nil {
+			cancelledAt := now
+			sub.CancelledAt = &cancelledAt
+		}
+		return true, nil
+	})
+}
+
+// ReactivateSubscription undoes a cancellation before the paid period ends,
+// the subscription gets back the end date it had before, if any.
+func (s *subscriptionService) ReactivateSubscription(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	return s.transition(ctx, id, model.AuditReactivate, func(sub *model.Subscription, now time.Time) (bool, error) {
+		switch {
+		case sub.Status.HasEnded():
+			return false, fmt.Errorf("%w: the subscription has already ended, create a new one", model.ErrInvalidTransition)
+		case sub.CancelledAt == nil:
+			return false, fmt.Errorf("%w: the subscription isn't cancelled", model.ErrInvalidTransition)
+		}
+
+		sub.EndDate = sub.CancelledEndDate
+		sub.CancelledAt, sub.CancelledEndDate = nil, nil
+		return true, nil
+	})
+}
+
+// transition applies change to the locked subscription and records it when change reports a modification.
+func (s *subscriptionService) transition(ctx context.Context, id uuid.UUID, action model.AuditAction, change func(sub *model.Subscription, now time.Time) (bool, error)) (*model.Subscription, error) {
+	var sub model.Subscription
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if before == nil || before.IsDeleted {
+			return model.ErrSubscriptionNotFound
+		}
+
+		now := time.Now().UTC()
+		sub = *before
+		// Статус мог устареть до ближайшего обновления статусов
+		sub.Status = sub.StatusAt(now)
+		changed, err := change(&sub, now)
+		if err != nil || !changed {
+			return err
+		}
+		sub.Status = sub.StatusAt(now)
+
+		if err := s.repo.Update(ctx, &sub); err != nil {
+			return err
+		}
+		return s.record(ctx, newAuditEntry(ctx, action, before, &sub))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// RefreshStatuses stores status changes caused by the passage of time: trials
+// and subscriptions starting, subscriptions ending. Failed subscriptions don't stop the rest.
+func (s *subscriptionService) RefreshStatuses(ctx context.Context) (int, error) {
+	subs, err := s.repo.ListByStatus(ctx, []model.SubscriptionStatus{model.StatusScheduled, model.StatusTrial, model.StatusActive})
+	if err != nil {
+		return 0, err
+	}
+
+	ctx = port.WithActor(ctx, port.SystemActor)
+	now := time.Now().UTC()
+	var (
+		changed int
+		errs    []error
+	)
+	for _, sub := range subs {
+		if sub.StatusAt(now) == sub.Status {
+			continue
+		}
+		updated := false
+		err := s.tx.Do(ctx, func(ctx context.Context) error {
+			before, err := s.repo.GetByIDForUpdate(ctx, sub.ID)
+			if err != nil || before == nil || before.IsDeleted {
+				return err
+			}
+			status := before.StatusAt(now)
+			if status == before.Status {
+				return nil
+			}
+			after := *before
+			after.Status = status
+			if err := s.repo.Update(ctx, &after); err != nil {
+				return err
+			}
+			updated = true
+			return s.record(ctx, newAuditEntry(ctx, model.AuditStatusChange, before, &after))
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", sub.ID, err))
+			continue
+		}
+		if updated {
+			changed++
+		}
+	}
+	return changed, errors.Join(errs...)
+}
+
+// checkCurrencyChange rejects a new currency while fixed member shares or discounts are set,
+// their amounts are stored in minor units of the current currency.
+func (s *subscriptionService) checkCurrencyChange(ctx context.Context, before, sub *model.Subscription) error {
+	if before == nil || before.Price.Currency == sub.Price.Currency {
+		return nil
+	}
+	members, err := s.repo.ListMembers(ctx, []uuid.UUID{sub.ID})
 	if err != nil {
 		return err
 	}
-	if sub == nil {
-		return errors.New("subscription not found")
+	for _, m := range members {
+		if m.SplitType == model.SplitFixed {
+			return fmt.Errorf("%w: fixed member shares are set in %s, change them before the currency", model.ErrCurrencyMismatch, before.Price.Currency)
+		}
 	}
-	sub.IsDeleted = true
-	return s.repo.Update(ctx, sub)
+	discounts, err := s.repo.ListDiscounts(ctx, []uuid.UUID{sub.ID})
+	if err != nil {
+		return err
+	}
+	for _, d := range discounts {
+		if d.Kind == model.DiscountFixed {
+			return fmt.Errorf("%w: fixed discounts are set in %s, delete them before changing the currency", model.ErrCurrencyMismatch, before.Price.Currency)
+		}
+	}
+	return nil
+}
+
+// keepCancellation carries the cancellation over to an updated subscription
+// unless the update removes the end date.
+func keepCancellation(sub, before *model.Subscription) {
+	sub.CancelledAt = nil
+	sub.CancelledEndDate = nil
+	sub.EndedAt = nil
+	if before != nil && sub.EndDate != nil {
+		sub.CancelledAt = before.CancelledAt
+		sub.CancelledEndDate = before.CancelledEndDate
+		sub.EndedAt = before.EndedAt
+	}
+}
+
+func (s *subscriptionService) PurgeDeleted(ctx context.Context, retention time.Duration) (int64, error) {
+	return s.repo.PurgeDeleted(ctx, time.Now().UTC().Add(-retention))
 }
 
 func (s *subscriptionService) ListSubscriptions(ctx context.Context) ([]*model.Subscription, error) {
 	return s.repo.List(ctx)
 }
 
+func (s *subscriptionService) ListSubscriptionsPage(ctx context.Context, userID *uuid.UUID, serviceName *string, status *model.SubscriptionStatus, limit, offset int) ([]*model.Subscription, error) {
+	return s.repo.ListPage(ctx, userID, serviceName, status, limit, offset)
+}
+
+func (s *subscriptionService) StreamSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName *string, fn func(*model.Subscription) error) error {
+	return s.repo.Stream(ctx, userID, serviceName, fn)
+}
+
+func (s *subscriptionService) FilterSubscriptions(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool, limit, offset int) ([]*model.Subscription, error) {
+	return s.repo.ListByFilter(ctx, userID, serviceName, currency, from, to, includeDeleted, limit, offset)
+}
+
+func (s *subscriptionService) ListSubscriptionsByUsers(ctx context.Context, userIDs []uuid.UUID, limit, offset int) (map[uuid.UUID][]*model.Subscription, error) {
+	subs, err := s.repo.ListPageByUsers(ctx, userIDs, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	grouped := make(map[uuid.UUID][]*model.Subscription, len(userIDs))
+	for _, sub := range subs {
+		grouped[sub.UserID] = append(grouped[sub.UserID], sub)
+	}
+	return grouped, nil
+}
+
+func (s *subscriptionService) ListMembersBySubscriptions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]*model.SubscriptionMember, error) {
+	members, err := s.repo.ListMembers(ctx, subscriptionIDs)
+	if err != nil {
+		return nil, err
+	}
+	return groupMembers(members), nil
+}
+
+func (s *subscriptionService) ListPriceChangesBySubscriptions(ctx context.Context, subscriptionIDs []uuid.UUID) (map[uuid.UUID][]*model.PriceChange, error) {
+	changes, err := s.repo.ListPriceChanges(ctx, subscriptionIDs)
+	if err != nil {
+		return nil, err
+	}
+	return groupPriceChanges(changes), nil
+}
+
+func (s *subscriptionService) FindDuplicates(ctx context.Context, userID *uuid.UUID) ([]model.DuplicateGroup, error) {
+	var (
+		subs []*model.Subscription
+		err  error
+	)
+	if userID != nil {
+		subs, err = s.repo.ListByUsers(ctx, []uuid.UUID{*userID})
+	} else {
+		subs, err = s.repo.List(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return findDuplicates(subs), nil
+}
+
+// checkOverlaps returns ErrSubscriptionOverlap when the user already has
+// another subscription to the same service running in any of sub's months.
+// It locks the user's subscriptions, so concurrent strict changes can't both pass the check.
+func (s *subscriptionService) checkOverlaps(ctx context.Context, sub *model.Subscription) error {
+	if err := s.repo.LockUser(ctx, sub.UserID); err != nil {
+		return err
+	}
+	existing, err := s.repo.ListByUserAndService(ctx, sub.UserID, sub.ServiceName)
+	if err != nil {
+		return err
+	}
+
+	var conflicts []string
+	for _, other := range existing {
+		if other.ID == sub.ID || other.IsDeleted {
+			continue
+		}
+		if rangesOverlap(sub, other) {
+			conflicts = append(conflicts, other.ID.String())
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: %s", model.ErrSubscriptionOverlap, strings.Join(conflicts, ", "))
+	}
+	return nil
+}
+
+func (s *subscriptionService) SchedulePriceChange(ctx context.Context, change *model.PriceChange) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetByIDForUpdate(ctx, change.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		if sub == nil || sub.IsDeleted {
+			return model.ErrSubscriptionNotFound
+		}
+		if change.Price.Currency == "" {
+			change.Price.Currency = sub.Price.Currency
+		}
+		if change.Price.Currency != sub.Price.Currency {
+			return fmt.Errorf("%w: subscription is billed in %s", model.ErrCurrencyMismatch, sub.Price.Currency)
+		}
+		if err := s.repo.CreatePriceChange(ctx, change); err != nil {
+			return err
+		}
+		entry := newAuditEntry(ctx, model.AuditPriceChange, sub, sub)
+		entry.PriceChange = change
+		return s.record(ctx, entry)
+	})
+}
+
+func (s *subscriptionService) ListPriceChanges(ctx context.Context, subscriptionID uuid.UUID) ([]*model.PriceChange, error) {
+	sub, err := s.repo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, model.ErrSubscriptionNotFound
+	}
+	return s.repo.ListPriceChanges(ctx, []uuid.UUID{subscriptionID})
+}
+
+func (s *subscriptionService) SetMembers(ctx context.Context, subscriptionID uuid.UUID, members []*model.SubscriptionMember) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetByIDForUpdate(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
+		if sub == nil || sub.IsDeleted {
+			return model.ErrSubscriptionNotFound
+		}
+
+		seen := make(map[uuid.UUID]bool, len(members))
+		var percent int64
+		for _, m := range members {
+			m.SubscriptionID = subscriptionID
+			switch {
+			case m.UserID == sub.UserID:
+				return fmt.Errorf("%w: owner %s cannot be a member", model.ErrInvalidMembers, m.UserID)
+			case seen[m.UserID]:
+				return fmt.Errorf("%w: user %s listed twice", model.ErrInvalidMembers, m.UserID)
+			case m.Value < 0:
+				return fmt.Errorf("%w: negative value for user %s", model.ErrInvalidMembers, m.UserID)
+			}
+			seen[m.UserID] = true
+
+			switch m.SplitType {
+			case model.SplitEqual:
+				m.Value = 0
+			case model.SplitPercentage:
+				percent += m.Value
+			case model.SplitFixed:
+			default:
+				return fmt.Errorf("%w: unknown split type %q", model.ErrInvalidMembers, m.SplitType)
+			}
+		}
+		if percent > 100 {
+			return fmt.Errorf("%w: percentages add up to %d", model.ErrInvalidMembers, percent)
+		}
+
+		if err := s.repo.ReplaceMembers(ctx, subscriptionID, members); err != nil {
+			return err
+		}
+		entry := newAuditEntry(ctx, model.AuditMembersChange, sub, sub)
+		entry.Members = members
+		return s.record(ctx, entry)
+	})
+}
+
+func (s *subscriptionService) ListMembers(ctx context.Context, subscriptionID uuid.UUID) ([]*model.SubscriptionMember, error) {
+	sub, err := s.repo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, model.ErrSubscriptionNotFound
+	}
+	return s.repo.ListMembers(ctx, []uuid.UUID{subscriptionID})
+}
+
+func (s *subscriptionService) CreateDiscount(ctx context.Context, discount *model.Discount) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetByIDForUpdate(ctx, discount.SubscriptionID)
+		if err != nil {
+			return err
+		}
+		if sub == nil || sub.IsDeleted {
+			return model.ErrSubscriptionNotFound
+		}
+
+		switch {
+		case discount.Kind == model.DiscountPercentage && (discount.Value <= 0 || discount.Value > 100):
+			return fmt.Errorf("%w: percentage must be between 1 and 100", model.ErrInvalidDiscount)
+		case discount.Kind == model.DiscountFixed && discount.Value <= 0:
+			return fmt.Errorf("%w: amount must be positive", model.ErrInvalidDiscount)
+		case discount.Kind != model.DiscountPercentage && discount.Kind != model.DiscountFixed:
+			return fmt.Errorf("%w: unknown kind %q", model.ErrInvalidDiscount, discount.Kind)
+		case discount.EndDate != nil && discount.EndDate.Before(discount.StartDate):
+			return fmt.Errorf("%w: end date is before start date", model.ErrInvalidDiscount)
+		}
+
+		if err := s.repo.CreateDiscount(ctx, discount); err != nil {
+			return err
+		}
+		entry := newAuditEntry(ctx, model.AuditDiscountCreate, sub, sub)
+		entry.Discount = discount
+		return s.record(ctx, entry)
+	})
+}
+
+func (s *subscriptionService) ListDiscounts(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Discount, error) {
+	sub, err := s.repo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, model.ErrSubscriptionNotFound
+	}
+	return s.repo.ListDiscounts(ctx, []uuid.UUID{subscriptionID})
+}
+
+func (s *subscriptionService) DeleteDiscount(ctx context.Context, subscriptionID, discountID uuid.UUID) error {
+	return s.tx.Do(ctx, func(ctx context.Context) error {
+		sub, err := s.repo.GetByIDForUpdate(ctx, subscriptionID)
+		if err != nil {
+			return err
+		}
+		if sub == nil {
+			return model.ErrSubscriptionNotFound
+		}
+		discounts, err := s.repo.ListDiscounts(ctx, []uuid.UUID{subscriptionID})
+		if err != nil {
+			return err
+		}
+		for _, d := range discounts {
+			if d.ID != discountID {
+				continue
+			}
+			if err := s.repo.DeleteDiscount(ctx, discountID); err != nil {
+				return err
+			}
+			entry := newAuditEntry(ctx, model.AuditDiscountDelete, sub, sub)
+			entry.Discount = d
+			return s.record(ctx, entry)
+		}
+		return model.ErrDiscountNotFound
+	})
+}
+
 func (s *subscriptionService) CalculateTotalCost(
 	ctx context.Context,
 	userID *uuid.UUID,
 	serviceName *string,
+	currency *string,
 	from time.Time,
 	to time.Time,
-) (int, error) {
-	subs, err := s.repo.GetByFilter(ctx, userID, serviceName, from, to)
+	includeDeleted bool,
+) (model.CostBreakdown, error) {
+	report, err := s.CostReport(ctx, userID, serviceName, currency, from, to, includeDeleted)
 	if err != nil {
-		return 0, err
+		return model.CostBreakdown{}, err
 	}
 
-	total := 0
-	for _, sub := range subs {
-		if sub.IsDeleted {
-			continue
+	var total model.CostBreakdown
+	for _, line := range report {
+		if total, err = total.Add(line.Cost); err != nil {
+			return model.CostBreakdown{}, err
 		}
-		months := monthsBetween(sub.StartDate, sub.EndDate, from, to)
-		total += sub.Price * months
 	}
 
 	return total, nil
 }
 
-func monthsBetween(start time.Time, end *time.Time, rangeFrom, rangeTo time.Time) int {
-	subStart := maxTime(start, rangeFrom)
-	subEnd := rangeTo
-	if end != nil && end.Before(rangeTo) {
-		subEnd = *end
+func (s *subscriptionService) CalculateTotalCostByUsers(
+	ctx context.Context,
+	userIDs []uuid.UUID,
+	serviceName *string,
+	currency *string,
+	from time.Time,
+	to time.Time,
+	includeDeleted bool,
+) (map[uuid.UUID]model.CostBreakdown, error) {
+	totals := make(map[uuid.UUID]model.CostBreakdown, len(userIDs))
+	for _, id := range userIDs {
+		totals[id] = model.CostBreakdown{}
+	}
+	if len(userIDs) == 0 {
+		return totals, nil
+	}
+
+	subs, err := s.repo.GetByFilterForUsers(ctx, userIDs, serviceName, currency, from, to, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	billing, err := loadBillingData(ctx, s.repo, subs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Общая подписка достаётся каждому запрошенному участнику его долей
+	add := func(sub *model.Subscription, userID uuid.UUID) error {
+		total, ok := totals[userID]
+		if !ok {
+			return nil
+		}
+		share, err := billing.breakdownInRange(sub, from, to, &userID)
+		if err != nil {
+			return err
+		}
+		totals[userID], err = total.Add(share)
+		return err
+	}
+	for _, sub := range subs {
+		if err := add(sub, sub.UserID); err != nil {
+			return nil, err
+		}
+		for _, member := range billing.members[sub.ID] {
+			if member.UserID == sub.UserID {
+				continue
+			}
+			if err := add(sub, member.UserID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return totals, nil
+}
+
+func (s *subscriptionService) CostReport(
+	ctx context.Context,
+	userID *uuid.UUID,
+	serviceName *string,
+	currency *string,
+	from time.Time,
+	to time.Time,
+	includeDeleted bool,
+) ([]model.SubscriptionCost, error) {
+	subs, err := s.repo.GetByFilter(ctx, userID, serviceName, currency, from, to, includeDeleted)
+	if err != nil {
+		return nil, err
+	}
+
+	billing, err := loadBillingData(ctx, s.repo, subs)
+	if err != nil {
+		return nil, err
+	}
+
+	report := make([]model.SubscriptionCost, 0, len(subs))
+	for _, sub := range subs {
+		breakdown, err := billing.breakdownInRange(sub, from, to, userID)
+		if err != nil {
+			return nil, err
+		}
+		report = append(report, model.SubscriptionCost{Subscription: sub, Cost: breakdown})
+	}
+
+	return report, nil
+}
+
+// StreamCostReport must not run inside a transaction: the cursor and the billing
+// queries of each chunk need separate connections.
+func (s *subscriptionService) StreamCostReport(
+	ctx context.Context,
+	userID *uuid.UUID,
+	serviceName *string,
+	currency *string,
+	from time.Time,
+	to time.Time,
+	includeDeleted bool,
+	fn func(model.SubscriptionCost) error,
+) error {
+	chunk := make([]*model.Subscription, 0, costReportChunk)
+	flush := func() error {
+		billing, err := loadBillingData(ctx, s.repo, chunk)
+		if err != nil {
+			return err
+		}
+		for _, sub := range chunk {
+			breakdown, err := billing.breakdownInRange(sub, from, to, userID)
+			if err != nil {
+				return err
+			}
+			if err := fn(model.SubscriptionCost{Subscription: sub, Cost: breakdown}); err != nil {
+				return err
+			}
+		}
+		chunk = chunk[:0]
+		return nil
+	}
+
+	err := s.repo.StreamByFilter(ctx, userID, serviceName, currency, from, to, includeDeleted, func(sub *model.Subscription) error {
+		chunk = append(chunk, sub)
+		if len(chunk) < costReportChunk {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+func (s *subscriptionService) ForecastCost(
+	ctx context.Context,
+	userID *uuid.UUID,
+	serviceName *string,
+	currency *string,
+	from time.Time,
+	months int,
+) ([]model.MonthlyCost, error) {
+	if months <= 0 {
+		return nil, nil
+	}
+
+	from = monthStart(from)
+	to := from.AddDate(0, months-1, 0)
+
+	subs, err := s.repo.GetByFilter(ctx, userID, serviceName, currency, from, to, false)
+	if err != nil {
+		return nil, err
+	}
+
+	billing, err := loadBillingData(ctx, s.repo, subs)
+	if err != nil {
+		return nil, err
+	}
+
+	forecast := make([]model.MonthlyCost, months)
+	for i := range forecast {
+		forecast[i].Month = from.AddDate(0, i, 0)
+	}
+
+	for _, sub := range subs {
+		for i := range forecast {
+			charge, err := billing.charge(sub, forecast[i].Month, userID)
+			if err != nil {
+				return nil, err
+			}
+			// Подписки без даты окончания считаем продолжающимися — это прогноз, а не факт
+			bucket := &forecast[i].Committed
+			if sub.EndDate == nil {
+				bucket = &forecast[i].Projected
+			}
+			if err := addMoney(bucket, charge); err != nil {
+				return nil, err
+			}
+			if err := addMoney(&forecast[i].Total, charge); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return forecast, nil
+}
+
+func (s *subscriptionService) CompareCost(
+	ctx context.Context,
+	userID *uuid.UUID,
+	serviceName *string,
+	currency *string,
+	base model.Period,
+	current model.Period,
+) (*model.CostComparison, error) {
+	from, to := base.From, base.To
+	if current.From.Before(from) {
+		from = current.From
+	}
+	if current.To.After(to) {
+		to = current.To
 	}
 
-	if subEnd.Before(subStart) {
-		return 0
+	// Сравнение — о прошлых расходах, закончившиеся подписки в нём остаются
+	subs, err := s.repo.GetByFilter(ctx, userID, serviceName, currency, from, to, true)
+	if err != nil {
+		return nil, err
+	}
+
+	billing, err := loadBillingData(ctx, s.repo, subs)
+	if err != nil {
+		return nil, err
 	}
 
-	// Округляем вниз, чтобы не учитывать неполные месяцы
-	years := subEnd.Year() - subStart.Year()
-	months := int(subEnd.Month()) - int(subStart.Month()) + years*12 + 1
-	return months
+	comparison := &model.CostComparison{Base: base, Current: current}
+	services := make(map[string]*model.ServiceCostChange)
+
+	for _, sub := range subs {
+		baseCost, err := billing.costInRange(sub, base.From, base.To, userID)
+		if err != nil {
+			return nil, err
+		}
+		currentCost, err := billing.costInRange(sub, current.From, current.To, userID)
+		if err != nil {
+			return nil, err
+		}
+		if baseCost.IsZero() && currentCost.IsZero() {
+			continue
+		}
+		delta, err := currentCost.Sub(baseCost)
+		if err != nil {
+			return nil, err
+		}
+
+		svc, ok := services[sub.ServiceName]
+		if !ok {
+			svc = &model.ServiceCostChange{ServiceName: sub.ServiceName}
+			services[sub.ServiceName] = svc
+		}
+
+		contribution := &svc.PriceChanges
+		inBase := overlaps(sub, base.From, base.To)
+		inCurrent := overlaps(sub, current.From, current.To)
+		switch {
+		case inCurrent && !inBase:
+			contribution = &svc.NewSubscriptions
+		case inBase && !inCurrent:
+			contribution = &svc.CancelledSubscriptions
+		}
+
+		for _, step := range []struct {
+			total  *model.Money
+			amount model.Money
+		}{
+			{&svc.BaseTotal, baseCost},
+			{&svc.CurrentTotal, currentCost},
+			{&svc.Delta, delta},
+			{contribution, delta},
+			{&comparison.BaseTotal, baseCost},
+			{&comparison.CurrentTotal, currentCost},
+			{&comparison.Delta, delta},
+		} {
+			if err := addMoney(step.total, step.amount); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	comparison.Services = make([]model.ServiceCostChange, 0, len(services))
+	for _, svc := range services {
+		comparison.Services = append(comparison.Services, *svc)
+	}
+	sort.Slice(comparison.Services, func(i, j int) bool {
+		di, dj := abs(comparison.Services[i].Delta.Amount), abs(comparison.Services[j].Delta.Amount)
+		if di != dj {
+			return di > dj
+		}
+		return comparison.Services[i].ServiceName < comparison.Services[j].ServiceName
+	})
+
+	return comparison, nil
 }
 
-func maxTime(a, b time.Time) time.Time {
-	if a.After(b) {
-		return a
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
 	}
-	return b
+	return n
 }
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+func monthly(userID uuid.UUID, service string, price int64, start time.Time) *model.Subscription {
+	return &model.Subscription{
+		ID:            uuid.New(),
+		ServiceName:   service,
+		Price:         rub(price),
+		UserID:        userID,
+		StartDate:     start,
+		BillingPeriod: model.BillingPeriodMonthly,
+	}
+}
+
+func deleted(sub *model.Subscription, reason model.DeletionReason, at time.Time) *model.Subscription {
+	sub.IsDeleted = true
+	sub.DeletionReason = reason
+	sub.DeletedAt = &at
+	return sub
+}
+
+func until(sub *model.Subscription, end time.Time) *model.Subscription {
+	sub.EndDate = &end
+	return sub
+}
+
+func TestForecastCost(t *testing.T) {
+	user := uuid.New()
+	quarterly := monthly(user, "Yandex Plus", 30000, month(2025, time.April))
+	quarterly.BillingPeriod = model.BillingPeriodQuarterly
+	repo := newMemorySubscriptions(
+		monthly(user, "Netflix", 10000, month(2024, time.June)),
+		until(monthly(user, "Spotify", 5000, month(2024, time.June)), month(2025, time.March)),
+		// Закончилась до начала прогноза
+		until(monthly(user, "Okko", 4000, month(2024, time.June)), month(2025, time.January)),
+		// Удалённые подписки в прогноз не попадают, даже закончившиеся в его первом месяце
+		deleted(monthly(user, "Ivi", 7000, month(2024, time.June)), model.DeletionEnded, time.Date(2025, time.February, 15, 0, 0, 0, 0, time.UTC)),
+		deleted(monthly(user, "Wink", 9000, month(2024, time.June)), model.DeletionMistake, time.Date(2025, time.January, 5, 0, 0, 0, 0, time.UTC)),
+		quarterly,
+		// Начинается сразу после окна прогноза
+		monthly(user, "Start", 2000, month(2025, time.May)),
+	)
+	service, _ := newTestService(repo)
+
+	forecast, err := service.ForecastCost(context.Background(), &user, nil, nil, time.Date(2025, time.February, 10, 12, 0, 0, 0, time.UTC), 3)
+	if err != nil {
+		t.Fatalf("ForecastCost() error = %v", err)
+	}
+
+	want := []struct {
+		month                       time.Time
+		committed, projected, total int64
+	}{
+		{month(2025, time.February), 5000, 10000, 15000},
+		{month(2025, time.March), 5000, 10000, 15000},
+		{month(2025, time.April), 0, 40000, 40000},
+	}
+	if len(forecast) != len(want) {
+		t.Fatalf("got %d months, want %d", len(forecast), len(want))
+	}
+	for i, w := range want {
+		got := forecast[i]
+		if !got.Month.Equal(w.month) || got.Committed.Amount != w.committed || got.Projected.Amount != w.projected || got.Total.Amount != w.total {
+			t.Errorf("month %d = %s committed %d projected %d total %d, want %s %d %d %d", i,
+				got.Month.Format("01-2006"), got.Committed.Amount, got.Projected.Amount, got.Total.Amount,
+				w.month.Format("01-2006"), w.committed, w.projected, w.total)
+		}
+	}
+
+	if forecast, err := service.ForecastCost(context.Background(), &user, nil, nil, month(2025, time.February), 0); err != nil || forecast != nil {
+		t.Errorf("ForecastCost(0 months) = %v, %v, want nil", forecast, err)
+	}
+}
+
+func TestCompareCost(t *testing.T) {
+	user := uuid.New()
+	netflix := monthly(user, "Netflix", 10000, month(2024, time.June))
+	repo := newMemorySubscriptions(
+		netflix,
+		// Удалена как закончившаяся в последнем месяце базового периода: он остаётся в расходах
+		deleted(monthly(user, "Ivi", 7000, month(2024, time.June)), model.DeletionEnded, time.Date(2025, time.February, 10, 0, 0, 0, 0, time.UTC)),
+		deleted(monthly(user, "Wink", 9000, month(2024, time.June)), model.DeletionMistake, time.Date(2025, time.March, 5, 0, 0, 0, 0, time.UTC)),
+		monthly(user, "Spotify", 3000, month(2025, time.March)),
+		// Заканчивается в первом месяце текущего периода
+		until(monthly(user, "Okko", 4000, month(2024, time.June)), month(2025, time.March)),
+	)
+	repo.changes = []*model.PriceChange{{ID: uuid.New(), SubscriptionID: netflix.ID, Price: rub(15000), EffectiveFrom: month(2025, time.April)}}
+	service, _ := newTestService(repo)
+
+	base := model.Period{From: month(2025, time.January), To: month(2025, time.February)}
+	current := model.Period{From: month(2025, time.March), To: month(2025, time.April)}
+	comparison, err := service.CompareCost(context.Background(), &user, nil, nil, base, current)
+	if err != nil {
+		t.Fatalf("CompareCost() error = %v", err)
+	}
+
+	if comparison.BaseTotal.Amount != 42000 || comparison.CurrentTotal.Amount != 35000 || comparison.Delta.Amount != -7000 {
+		t.Errorf("totals = %d -> %d (%d), want 42000 -> 35000 (-7000)",
+			comparison.BaseTotal.Amount, comparison.CurrentTotal.Amount, comparison.Delta.Amount)
+	}
+
+	// Услуги идут по убыванию изменения
+	want := []struct {
+		service                          string
+		base, current, delta             int64
+		newSubs, cancelled, priceChanges int64
+	}{
+		{"Ivi", 14000, 0, -14000, 0, -14000, 0},
+		{"Spotify", 0, 6000, 6000, 6000, 0, 0},
+		{"Netflix", 20000, 25000, 5000, 0, 0, 5000},
+		{"Okko", 8000, 4000, -4000, 0, 0, -4000},
+	}
+	if len(comparison.Services) != len(want) {
+		t.Fatalf("got %d services, want %d: %+v", len(comparison.Services), len(want), comparison.Services)
+	}
+	for i, w := range want {
+		got := comparison.Services[i]
+		if got.ServiceName != w.service || got.BaseTotal.Amount != w.base || got.CurrentTotal.Amount != w.current || got.Delta.Amount != w.delta ||
+			got.NewSubscriptions.Amount != w.newSubs || got.CancelledSubscriptions.Amount != w.cancelled || got.PriceChanges.Amount != w.priceChanges {
+			t.Errorf("service %d = %s %d -> %d (%d; new %d, cancelled %d, prices %d), want %+v", i, got.ServiceName,
+				got.BaseTotal.Amount, got.CurrentTotal.Amount, got.Delta.Amount,
+				got.NewSubscriptions.Amount, got.CancelledSubscriptions.Amount, got.PriceChanges.Amount, w)
+		}
+	}
+}
+
+func TestCancelSubscription(t *testing.T) {
+	user := uuid.New()
+	thisMonth := monthStart(time.Now().UTC())
+	nextMonth := thisMonth.AddDate(0, 1, 0)
+	yearly := func() *model.Subscription {
+		sub := monthly(user, "Kinopoisk", 300000, thisMonth.AddDate(0, -2, 0))
+		sub.BillingPeriod = model.BillingPeriodYearly
+		return sub
+	}
+
+	tests := []struct {
+		name             string
+		sub              *model.Subscription
+		mode             model.CancelMode
+		wantEnd          time.Time
+		wantEnded        bool
+		wantCancelledEnd *time.Time
+	}{
+		{"at period end keeps the paid year", yearly(), model.CancelAtPeriodEnd, thisMonth.AddDate(0, 9, 0), false, nil},
+		{"immediately ends this month", yearly(), model.CancelImmediately, thisMonth, true, nil},
+		{"earlier end date is kept", until(yearly(), nextMonth), model.CancelAtPeriodEnd, nextMonth, false, &nextMonth},
+		{"immediately shortens the end date", until(yearly(), nextMonth), model.CancelImmediately, thisMonth, true, &nextMonth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, audit := newTestService(newMemorySubscriptions(tt.sub))
+
+			got, err := service.CancelSubscription(context.Background(), tt.sub.ID, tt.mode)
+			if err != nil {
+				t.Fatalf("CancelSubscription() error = %v", err)
+			}
+			if got.EndDate == nil || !got.EndDate.Equal(tt.wantEnd) {
+				t.Errorf("EndDate = %v, want %s", got.EndDate, tt.wantEnd.Format("01-2006"))
+			}
+			if (got.EndedAt != nil) != tt.wantEnded || got.CancelledAt == nil {
+				t.Errorf("EndedAt = %v, CancelledAt = %v, want ended %v and cancelled", got.EndedAt, got.CancelledAt, tt.wantEnded)
+			}
+			if (got.CancelledEndDate == nil) != (tt.wantCancelledEnd == nil) ||
+				(got.CancelledEndDate != nil && !got.CancelledEndDate.Equal(*tt.wantCancelledEnd)) {
+				t.Errorf("CancelledEndDate = %v, want %v", got.CancelledEndDate, tt.wantCancelledEnd)
+			}
+			if len(audit.entries) != 1 {
+				t.Errorf("got %d audit entries, want 1", len(audit.entries))
+			}
+		})
+	}
+}
+
+func TestCancelSubscriptionTwice(t *testing.T) {
+	user := uuid.New()
+	thisMonth := monthStart(time.Now().UTC())
+	sub := monthly(user, "Netflix", 10000, thisMonth.AddDate(0, -2, 0))
+	sub.BillingPeriod = model.BillingPeriodQuarterly
+	service, audit := newTestService(newMemorySubscriptions(sub))
+	ctx := context.Background()
+
+	first, err := service.CancelSubscription(ctx, sub.ID, model.CancelAtPeriodEnd)
+	if err != nil || !first.EndDate.Equal(thisMonth) {
+		t.Fatalf("CancelSubscription() = %v, %v, want the end of the quarter", first.EndDate, err)
+	}
+	// Повторная отмена в конце периода ничего не меняет
+	again, err := service.CancelSubscription(ctx, sub.ID, model.CancelAtPeriodEnd)
+	if err != nil || !again.CancelledAt.Equal(*first.CancelledAt) || len(audit.entries) != 1 {
+		t.Errorf("repeated cancel = %+v, %v with %d audit entries, want no change", again, err, len(audit.entries))
+	}
+
+	immediate, err := service.CancelSubscription(ctx, sub.ID, model.CancelImmediately)
+	if err != nil {
+		t.Fatalf("CancelSubscription(immediately) error = %v", err)
+	}
+	if immediate.EndedAt == nil || !immediate.EndDate.Equal(thisMonth) || immediate.CancelledEndDate != nil {
+		t.Errorf("immediate cancel = end %v, ended at %v, cancelled end %v, want this month and no previous end",
+			immediate.EndDate, immediate.EndedAt, immediate.CancelledEndDate)
+	}
+}
+
+func TestImportSubscriptionsStrict(t *testing.T) {
+	user := uuid.New()
+	existing := until(monthly(user, "Netflix", 99900, month(2024, time.June)), month(2025, time.January))
+
+	tests := []struct {
+		name    string
+		subs    []*model.Subscription
+		strict  bool
+		wantErr bool
+	}{
+		{"after the existing one", []*model.Subscription{monthly(user, "Netflix", 99900, month(2025, time.February))}, true, false},
+		{"overlaps the existing one", []*model.Subscription{monthly(user, " netflix", 99900, month(2025, time.January))}, true, true},
+		{"overlapping imports", []*model.Subscription{
+			monthly(user, "Spotify", 16900, month(2025, time.February)),
+			monthly(user, "SPOTIFY", 16900, month(2025, time.March)),
+		}, true, true},
+		{"other users don't conflict", []*model.Subscription{monthly(uuid.New(), "Netflix", 99900, month(2024, time.June))}, true, false},
+		{"overlaps allowed when not strict", []*model.Subscription{monthly(user, "Netflix", 99900, month(2025, time.January))}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemorySubscriptions(existing)
+			service, _ := newTestService(repo)
+
+			err := service.ImportSubscriptions(context.Background(), tt.subs, false, tt.strict)
+			if tt.wantErr != errors.Is(err, model.ErrSubscriptionOverlap) || (!tt.wantErr && err != nil) {
+				t.Fatalf("ImportSubscriptions() error = %v, want overlap %v", err, tt.wantErr)
+			}
+			if stored := len(repo.subs) - 1; tt.wantErr && stored != 0 || !tt.wantErr && stored != len(tt.subs) {
+				t.Errorf("stored %d subscriptions", stored)
+			}
+		})
+	}
+}
@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type WebhookOptions struct {
+	MaxAttempts int
+	// Retries wait BaseDelay, then twice as long after every failure, up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Lease must be longer than a single send, deliveries being sent are hidden from other workers for it.
+	Lease     time.Duration
+	BatchSize int
+}
+
+type webhookService struct {
+	repo   port.WebhookRepository
+	sender port.WebhookSender
+	opts   WebhookOptions
+}
+
+func NewWebhookService(repo port.WebhookRepository, sender port.WebhookSender, opts WebhookOptions) port.WebhookService {
+	return &webhookService{repo: repo, sender: sender, opts: opts}
+}
+
+// Publish enqueues deliveries of relayed events.
+func (s *webhookService) Publish(ctx context.Context, events ...model.Event) error {
+	now := time.Now()
+	for _, event := range events {
+		if err := s.repo.Enqueue(ctx, event, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterEndpoint generates a secret when none is given.
+func (s *webhookService) RegisterEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error {
+	if endpoint.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return err
+		}
+		endpoint.Secret = hex.EncodeToString(secret)
+	}
+	endpoint.CreatedAt = time.Now().UTC()
+	return s.repo.CreateEndpoint(ctx, endpoint)
+}
+
+func (s *webhookService) ListEndpoints(ctx context.Context) ([]*model.WebhookEndpoint, error) {
+	return s.repo.ListEndpoints(ctx)
+}
+
+func (s *webhookService) DeleteEndpoint(ctx context.Context, id uuid.UUID) error {
+	deleted, err := s.repo.DeleteEndpoint(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return model.ErrWebhookNotFound
+	}
+	return nil
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, endpointID uuid.UUID, status *model.DeliveryStatus, limit int) ([]*model.WebhookDelivery, error) {
+	endpoint, err := s.repo.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, model.ErrWebhookNotFound
+	}
+	return s.repo.ListDeliveries(ctx, endpointID, status, limit)
+}
+
+func (s *webhookService) GetDelivery(ctx context.Context, endpointID, id uuid.UUID) (*model.WebhookDelivery, []*model.WebhookAttempt, error) {
+	delivery, err := s.getDelivery(ctx, endpointID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	attempts, err := s.repo.ListAttempts(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return delivery, attempts, nil
+}
+
+func (s *webhookService) Redeliver(ctx context.Context, endpointID, id uuid.UUID) (*model.WebhookDelivery, error) {
+	delivery, err := s.getDelivery(ctx, endpointID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery.Status = model.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = now
+	delivery.UpdatedAt = now
+	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (s *webhookService) getDelivery(ctx context.Context, endpointID, id uuid.UUID) (*model.WebhookDelivery, error) {
+	delivery, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil || delivery.EndpointID != endpointID {
+		return nil, model.ErrDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+func (s *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDue(ctx, time.Now(), s.opts.Lease, s.opts.BatchSize)
+	if err != nil || len(deliveries) == 0 {
+		return 0, err
+	}
+
+	endpoints := make(map[uuid.UUID]*model.WebhookEndpoint)
+	for _, d := range deliveries {
+		if _, ok := endpoints[d.EndpointID]; ok {
+			continue
+		}
+		endpoint, err := s.repo.GetEndpoint(ctx, d.EndpointID)
+		if err != nil {
+			return 0, err
+		}
+		endpoints[d.EndpointID] = endpoint
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+	for _, d := range deliveries {
+		endpoint := endpoints[d.EndpointID]
+		if endpoint == nil {
+			// Эндпоинт удалён вместе с доставками после выборки
+			continue
+		}
+		wg.Add(1)
+		go func(d *model.WebhookDelivery) {
+			defer wg.Done()
+			if err := s.deliver(ctx, endpoint, d); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(d)
+	}
+	wg.Wait()
+
+	return len(deliveries), firstErr
+}
+
+func (s *webhookService) deliver(ctx context.Context, endpoint *model.WebhookEndpoint, d *model.WebhookDelivery) error {
+	start := time.Now()
+	status, err := s.sender.Send(ctx, endpoint, d)
+	attempt := &model.WebhookAttempt{
+		ID:          uuid.New(),
+		DeliveryID:  d.ID,
+		AttemptedAt: start,
+		StatusCode:  status,
+		Duration:    time.Since(start),
+	}
+
+	d.Attempts++
+	switch {
+	case err == nil:
+		d.Status = model.DeliverySucceeded
+	case d.Attempts >= s.opts.MaxAttempts:
+		attempt.Error = err.Error()
+		d.Status = model.DeliveryFailed
+	default:
+		attempt.Error = err.Error()
+		d.NextAttemptAt = start.Add(backoff(s.opts.BaseDelay, s.opts.MaxDelay, d.Attempts))
+	}
+	d.UpdatedAt = time.Now()
+
+	return s.repo.RecordAttempt(ctx, d, attempt)
+}
+
+// backoff returns the delay before the next try after the given number of failed attempts:
+// base, then twice as long after every failure, up to max.
+func backoff(base, max time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	if delay > max {
+		delay = max
+	}
+	return delay
+}
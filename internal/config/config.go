@@ -3,15 +3,27 @@ package config
 import (
 	"flag"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
 	Server struct {
-		Port int `yaml:"port"`
+		Port            int           `yaml:"port"`
+		ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"15s"` // сколько ждать незавершённые запросы при остановке
 	} `yaml:"server"`
 
+	GRPC struct {
+		Enabled bool `yaml:"enabled"`
+		Port    int  `yaml:"port" env-default:"9090"`
+	} `yaml:"grpc"`
+
+	GraphQL struct {
+		MaxDepth      int `yaml:"max_depth" env-default:"10"`
+		MaxComplexity int `yaml:"max_complexity" env-default:"5000"` // списки стоят как limit (или 10) элементов
+	} `yaml:"graphql"`
+
 	DataBase struct {
 		Host     string `yaml:"host"`
 		Port     int    `yaml:"port"`
@@ -24,6 +36,63 @@ type Config struct {
 		Level  string `yaml:"level"`
 		Output string `yaml:"output"`
 	} `yaml:"logger"`
+
+	Idempotency struct {
+		TTL   time.Duration `yaml:"ttl" env-default:"24h"`  // сколько хранить ответы по Idempotency-Key
+		Lease time.Duration `yaml:"lease" env-default:"1m"` // после этого повтор перехватывает ключ незавершённого запроса
+	} `yaml:"idempotency"`
+
+	Webhooks struct {
+		Timeout      time.Duration `yaml:"timeout" env-default:"10s"`
+		MaxAttempts  int           `yaml:"max_attempts" env-default:"8"`
+		BaseDelay    time.Duration `yaml:"base_delay" env-default:"30s"` // удваивается после каждой неудачи
+		MaxDelay     time.Duration `yaml:"max_delay" env-default:"6h"`
+		PollInterval time.Duration `yaml:"poll_interval" env-default:"5s"`
+		BatchSize    int           `yaml:"batch_size" env-default:"20"`
+	} `yaml:"webhooks"`
+
+	Events struct {
+		RelayInterval time.Duration `yaml:"relay_interval" env-default:"1s"`
+		BatchSize     int           `yaml:"batch_size" env-default:"100"`
+		Lease         time.Duration `yaml:"lease" env-default:"1m"` // больше времени публикации пакета
+		MaxAttempts   int           `yaml:"max_attempts" env-default:"10"`
+		BaseDelay     time.Duration `yaml:"base_delay" env-default:"5s"` // удваивается после каждой неудачи
+		MaxDelay      time.Duration `yaml:"max_delay" env-default:"1h"`
+		DisableLog    bool          `yaml:"disable_log"` // не писать события в лог
+		HTTPURL       string        `yaml:"http_url"`    // пусто — не отправлять
+		HTTPTimeout   time.Duration `yaml:"http_timeout" env-default:"10s"`
+		RenewalNotice time.Duration `yaml:"renewal_notice" env-default:"72h"` // за сколько предупреждать о списании
+	} `yaml:"events"`
+
+	Notifications struct {
+		SMTP struct {
+			Host     string        `yaml:"host"` // пусто — email отключён
+			Port     int           `yaml:"port" env-default:"587"`
+			Username string        `yaml:"username"`
+			Password string        `yaml:"password" env:"SMTP_PASSWORD"`
+			From     string        `yaml:"from"`
+			Timeout  time.Duration `yaml:"timeout" env-default:"30s"`
+		} `yaml:"smtp"`
+	} `yaml:"notifications"`
+
+	Telegram struct {
+		Token        string        `yaml:"token" env:"TELEGRAM_TOKEN"` // нужен только процессу бота
+		APIURL       string        `yaml:"api_url" env-default:"https://api.telegram.org"`
+		BotUsername  string        `yaml:"bot_username"` // для ссылок вида t.me/<bot>?start=<token>
+		Timeout      time.Duration `yaml:"timeout" env-default:"10s"`
+		PollTimeout  time.Duration `yaml:"poll_timeout" env-default:"30s"`
+		LinkTokenTTL time.Duration `yaml:"link_token_ttl" env-default:"15m"`
+	} `yaml:"telegram"`
+
+	// Расписания фоновых задач: cron-выражения или @every <duration>
+	Jobs struct {
+		RenewalScan      string        `yaml:"renewal_scan" env-default:"@hourly"`
+		RefreshStatuses  string        `yaml:"refresh_statuses" env-default:"5 0 * * *"` // статусы меняются с началом месяца
+		Reminders        string        `yaml:"reminders" env-default:"*/15 * * * *"`
+		PurgeIdempotency string        `yaml:"purge_idempotency" env-default:"@hourly"`
+		PurgeDeleted     string        `yaml:"purge_deleted" env-default:"0 3 * * *"`
+		DeletedRetention time.Duration `yaml:"deleted_retention" env-default:"2160h"` // 90 дней для удалённых по ошибке, потом восстановить нельзя
+	} `yaml:"jobs"`
 }
 
 func MustLoad() *Config {
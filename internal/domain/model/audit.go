@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate     AuditAction = "create"
+	AuditUpdate     AuditAction = "update"
+	AuditDelete     AuditAction = "delete"
+	AuditRestore    AuditAction = "restore"
+	AuditCancel     AuditAction = "cancel"
+	AuditReactivate AuditAction = "reactivate"
+	// AuditStatusChange is a status change caused by the passage of time, e.g. the end of a trial.
+	AuditStatusChange AuditAction = "status_change"
+
+	// Changes of related records leave Before and After equal and carry the record itself.
+	AuditPriceChange    AuditAction = "price_change"
+	AuditMembersChange  AuditAction = "members_change"
+	AuditDiscountCreate AuditAction = "discount_create"
+	AuditDiscountDelete AuditAction = "discount_delete"
+)
+
+// AuditEntry records a single change of a subscription.
+// Before is nil for created subscriptions. PriceChange, Members (the new list)
+// and Discount are set for the actions changing them.
+type AuditEntry struct {
+	ID             uuid.UUID
+	SubscriptionID uuid.UUID
+	Actor          string
+	Action         AuditAction
+	Before         *Subscription
+	After          *Subscription
+	PriceChange    *PriceChange
+	Members        []*SubscriptionMember
+	Discount       *Discount
+	CreatedAt      time.Time
+}
+
+type AuditFilter struct {
+	SubscriptionID *uuid.UUID
+	Actor          *string
+	From           *time.Time
+	To             *time.Time
+	Limit          int
+	Offset         int
+}
@@ -0,0 +1,29 @@
+package model
+
+import "github.com/google/uuid"
+
+type BatchAction string
+
+const (
+	BatchCreate BatchAction = "create"
+	BatchUpdate BatchAction = "update"
+	BatchDelete BatchAction = "delete"
+)
+
+type BatchMode string
+
+const (
+	// BatchAtomic applies all operations or none of them.
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort applies every operation that succeeds and skips failed ones.
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// BatchOperation is a single change of a batch. Subscription is set for
+// create and update, ID and Reason for delete.
+type BatchOperation struct {
+	Action       BatchAction
+	Subscription *Subscription
+	ID           uuid.UUID
+	Reason       DeletionReason
+}
@@ -0,0 +1,14 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatLink connects a Telegram chat to the user it manages subscriptions for.
+type ChatLink struct {
+	ChatID   int64     `db:"chat_id"`
+	UserID   uuid.UUID `db:"user_id"`
+	LinkedAt time.Time `db:"linked_at"`
+}
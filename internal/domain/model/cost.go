@@ -0,0 +1,111 @@
+package model
+
+import "time"
+
+// CostBreakdown details an amount paid for subscriptions.
+// BeforeDiscount and Discount are tax-inclusive, and
+// Gross = BeforeDiscount - Discount = Net + Tax.
+type CostBreakdown struct {
+	BeforeDiscount Money
+	Discount       Money
+	Net            Money
+	Tax            Money
+	Gross          Money
+}
+
+func (b CostBreakdown) Add(other CostBreakdown) (CostBreakdown, error) {
+	var sum CostBreakdown
+	var err error
+	pairs := []struct {
+		dst  *Money
+		a, b Money
+	}{
+		{&sum.BeforeDiscount, b.BeforeDiscount, other.BeforeDiscount},
+		{&sum.Discount, b.Discount, other.Discount},
+		{&sum.Net, b.Net, other.Net},
+		{&sum.Tax, b.Tax, other.Tax},
+		{&sum.Gross, b.Gross, other.Gross},
+	}
+	for _, p := range pairs {
+		if *p.dst, err = p.a.Add(p.b); err != nil {
+			return CostBreakdown{}, err
+		}
+	}
+	return sum, nil
+}
+
+func (b CostBreakdown) Sub(other CostBreakdown) (CostBreakdown, error) {
+	var diff CostBreakdown
+	var err error
+	pairs := []struct {
+		dst  *Money
+		a, b Money
+	}{
+		{&diff.BeforeDiscount, b.BeforeDiscount, other.BeforeDiscount},
+		{&diff.Discount, b.Discount, other.Discount},
+		{&diff.Net, b.Net, other.Net},
+		{&diff.Tax, b.Tax, other.Tax},
+		{&diff.Gross, b.Gross, other.Gross},
+	}
+	for _, p := range pairs {
+		if *p.dst, err = p.a.Sub(p.b); err != nil {
+			return CostBreakdown{}, err
+		}
+	}
+	return diff, nil
+}
+
+// SubscriptionCost is the cost of a single subscription over a period.
+type SubscriptionCost struct {
+	Subscription *Subscription
+	Cost         CostBreakdown
+}
+
+// MonthlyCost is a single point of a spending forecast.
+// Committed covers subscriptions with a known end date, Projected covers
+// open-ended ones which are assumed to keep running at their scheduled price.
+type MonthlyCost struct {
+	Month     time.Time
+	Committed Money
+	Projected Money
+	Total     Money
+}
+
+// Period is an inclusive range of months.
+type Period struct {
+	From time.Time
+	To   time.Time
+}
+
+// CostComparison compares spending of two periods.
+type CostComparison struct {
+	Base         Period
+	Current      Period
+	BaseTotal    Money
+	CurrentTotal Money
+	Delta        Money
+	Services     []ServiceCostChange
+}
+
+// DeltaPercent returns the relative change against the base period.
+// ok is false when the base total is zero and the percentage is undefined.
+func (c CostComparison) DeltaPercent() (percent float64, ok bool) {
+	if c.BaseTotal.IsZero() {
+		return 0, false
+	}
+	return float64(c.Delta.Amount) * 100 / float64(c.BaseTotal.Amount), true
+}
+
+// ServiceCostChange breaks down the spending change of a single service.
+// NewSubscriptions and CancelledSubscriptions hold the contribution of
+// subscriptions running in only one of the periods, PriceChanges holds the
+// difference for subscriptions running in both.
+type ServiceCostChange struct {
+	ServiceName            string
+	BaseTotal              Money
+	CurrentTotal           Money
+	Delta                  Money
+	NewSubscriptions       Money
+	CancelledSubscriptions Money
+	PriceChanges           Money
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type DiscountKind string
+
+const (
+	DiscountPercentage DiscountKind = "percentage"
+	DiscountFixed      DiscountKind = "fixed"
+)
+
+// Discount reduces the charge of every billed month between StartDate and
+// EndDate. Value is a percentage for DiscountPercentage and an amount for
+// DiscountFixed, in minor units of the subscription currency.
+// Open-ended discounts have no EndDate.
+type Discount struct {
+	ID             uuid.UUID    `db:"id"`
+	SubscriptionID uuid.UUID    `db:"subscription_id"`
+	Kind           DiscountKind `db:"kind"`
+	Value          int64        `db:"value"`
+	Code           string       `db:"code"`
+	StartDate      time.Time    `db:"start_date"`
+	EndDate        *time.Time   `db:"end_date"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "github.com/google/uuid"
+
+// DuplicateGroup is a set of subscriptions of one user to the same service
+// (compared case-insensitively) whose date ranges overlap.
+type DuplicateGroup struct {
+	UserID        uuid.UUID
+	ServiceName   string
+	Subscriptions []*Subscription
+}
@@ -0,0 +1,21 @@
+package model
+
+import "errors"
+
+var (
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionOverlap  = errors.New("subscription overlaps with an existing one")
+	ErrInvalidTransition    = errors.New("subscription status doesn't allow this change")
+	ErrInvalidMembers       = errors.New("invalid subscription members")
+	ErrInvalidDiscount      = errors.New("invalid discount")
+	ErrDiscountNotFound     = errors.New("discount not found")
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrDeliveryNotFound     = errors.New("webhook delivery not found")
+	ErrNoRecipient          = errors.New("user has no recipient for the channel")
+	ErrInvalidLinkToken     = errors.New("link token is invalid or expired")
+	ErrJobNotFound          = errors.New("job not found")
+	ErrJobRunning           = errors.New("job is already running")
+
+	ErrIdempotencyKeyReused     = errors.New("idempotency key was used with a different request")
+	ErrIdempotencyKeyInProgress = errors.New("request with this idempotency key is still in progress")
+)
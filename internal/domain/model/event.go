@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventSubscriptionCreated         EventType = "subscription.created"
+	EventSubscriptionUpdated         EventType = "subscription.updated"
+	EventSubscriptionDeleted         EventType = "subscription.deleted"
+	EventSubscriptionRestored        EventType = "subscription.restored"
+	EventSubscriptionPriceChanged    EventType = "subscription.price_changed"
+	EventSubscriptionRenewalUpcoming EventType = "subscription.renewal_upcoming"
+	EventSubscriptionCancelled       EventType = "subscription.cancelled"
+	EventSubscriptionReactivated     EventType = "subscription.reactivated"
+	EventSubscriptionStatusChanged   EventType = "subscription.status_changed"
+)
+
+// EventTypes lists every event that can be subscribed to.
+var EventTypes = []EventType{
+	EventSubscriptionCreated,
+	EventSubscriptionUpdated,
+	EventSubscriptionDeleted,
+	EventSubscriptionRestored,
+	EventSubscriptionPriceChanged,
+	EventSubscriptionRenewalUpcoming,
+	EventSubscriptionCancelled,
+	EventSubscriptionReactivated,
+	EventSubscriptionStatusChanged,
+}
+
+// Event describes something that happened to a subscription.
+// Subscription holds its state after the event, PriceChange is set for
+// scheduled price changes and RenewsAt for upcoming renewals.
+type Event struct {
+	ID           uuid.UUID
+	Type         EventType
+	OccurredAt   time.Time
+	Subscription *Subscription
+	PriceChange  *PriceChange
+	RenewsAt     *time.Time
+}
+
+// OutboxEvent is an event waiting to be relayed, Attempts counts failed publications.
+type OutboxEvent struct {
+	Event
+	Attempts int
+}
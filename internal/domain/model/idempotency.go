@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// IdempotencyRecord stores the response to a request made with an Idempotency-Key.
+// Keys are scoped by the author of the request, so clients can't collide on them.
+// StatusCode is zero while the original request is still being processed; if it
+// hasn't finished by LockedUntil, a retry takes the key over with a new LeaseToken
+// and the original request can no longer complete or release it.
+type IdempotencyRecord struct {
+	Scope       string    `db:"scope"`
+	Key         string    `db:"key"`
+	RequestHash string    `db:"request_hash"`
+	StatusCode  int       `db:"status_code"`
+	ContentType string    `db:"content_type"`
+	Response    []byte    `db:"response"`
+	CreatedAt   time.Time `db:"created_at"`
+	ExpiresAt   time.Time `db:"expires_at"`
+	LockedUntil time.Time `db:"locked_until"`
+	LeaseToken  string    `db:"lease_token"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type JobTrigger string
+
+const (
+	JobTriggerSchedule JobTrigger = "schedule"
+	JobTriggerManual   JobTrigger = "manual"
+)
+
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+// JobRun is an entry of the job run history, Result summarizes what a successful run did.
+type JobRun struct {
+	ID          uuid.UUID    `db:"id"`
+	Job         string       `db:"job"`
+	Trigger     JobTrigger   `db:"trigger"`
+	Status      JobRunStatus `db:"status"`
+	ScheduledAt *time.Time   `db:"scheduled_at"` // слот расписания, nil для ручного запуска
+	StartedAt   time.Time    `db:"started_at"`
+	FinishedAt  *time.Time   `db:"finished_at"`
+	Result      string       `db:"result"`
+	Error       string       `db:"error"`
+}
+
+// JobInfo describes a registered job, NextRunAt is zero until the scheduler is started.
+type JobInfo struct {
+	Name      string
+	Schedule  string
+	NextRunAt time.Time
+	LastRun   *JobRun
+}
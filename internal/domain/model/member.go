@@ -0,0 +1,23 @@
+package model
+
+import "github.com/google/uuid"
+
+type SplitType string
+
+const (
+	SplitEqual      SplitType = "equal"
+	SplitPercentage SplitType = "percentage"
+	SplitFixed      SplitType = "fixed"
+)
+
+// SubscriptionMember is a user sharing a subscription paid by its owner.
+// Value is a percentage of the charge for SplitPercentage, an amount for
+// SplitFixed (in minor units of the subscription currency) and is ignored
+// for SplitEqual. The owner always takes an equal
+// share of whatever is left after fixed and percentage members.
+type SubscriptionMember struct {
+	SubscriptionID uuid.UUID `db:"subscription_id"`
+	UserID         uuid.UUID `db:"user_id"`
+	SplitType      SplitType `db:"split_type"`
+	Value          int64     `db:"value"`
+}
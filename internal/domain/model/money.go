@@ -0,0 +1,269 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+const DefaultCurrency = "RUB"
+
+var (
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+	ErrMoneyOverflow    = errors.New("money amount overflow")
+	ErrInvalidMoney     = errors.New("invalid money amount")
+)
+
+type RoundingMode int
+
+const (
+	RoundHalfUp   RoundingMode = iota // 0.5 away from zero
+	RoundHalfEven                     // 0.5 to the nearest even, banker's rounding
+	RoundDown                         // towards zero
+	RoundUp                           // away from zero
+)
+
+// Money is an amount in minor units of Currency (kopecks for RUB, cents for USD).
+// The zero value has no currency and acts as a neutral element for Add and Sub,
+// so totals can be accumulated starting from Money{}.
+//
+// In JSON Money is a decimal string such as "999.00"; the currency is carried
+// separately by the enclosing object.
+type Money struct {
+	Amount   int64  `db:"amount"`
+	Currency string `db:"currency"`
+}
+
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// minorDigits returns the number of fraction digits of the currency.
+func minorDigits(currency string) int {
+	switch currency {
+	case "JPY", "KRW", "VND", "CLP", "ISK", "UGX":
+		return 0
+	case "BHD", "KWD", "OMR", "JOD", "TND":
+		return 3
+	default:
+		return 2
+	}
+}
+
+func pow10(n int) int64 {
+	p := int64(1)
+	for i := 0; i < n; i++ {
+		p *= 10
+	}
+	return p
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// sameCurrency resolves the currency of a binary operation, treating an
+// empty zero value as compatible with any currency.
+func (m Money) sameCurrency(other Money) (string, error) {
+	switch {
+	case m.Currency == other.Currency:
+		return m.Currency, nil
+	case m.Currency == "" && m.Amount == 0:
+		return other.Currency, nil
+	case other.Currency == "" && other.Amount == 0:
+		return m.Currency, nil
+	}
+	return "", fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	currency, err := m.sameCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Amount: sum, Currency: currency}, nil
+}
+
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, ErrMoneyOverflow
+	}
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+func (m Money) Neg() Money {
+	return Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+func (m Money) Mul(n int64) (Money, error) {
+	return m.MulRatio(n, 1, RoundDown)
+}
+
+// MulRatio returns m * num / den rounded with the given mode.
+func (m Money) MulRatio(num, den int64, mode RoundingMode) (Money, error) {
+	if den == 0 {
+		return Money{}, fmt.Errorf("%w: division by zero", ErrInvalidMoney)
+	}
+
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(num))
+	divisor := big.NewInt(den)
+	if divisor.Sign() < 0 {
+		product.Neg(product)
+		divisor.Neg(divisor)
+	}
+
+	quo, rem := new(big.Int).QuoRem(product, divisor, new(big.Int))
+	if rem.Sign() != 0 {
+		// Сравниваем удвоенный остаток с делителем, чтобы понять, где середина
+		twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+		cmp := twice.Cmp(divisor)
+		away := false
+		switch mode {
+		case RoundUp:
+			away = true
+		case RoundHalfUp:
+			away = cmp >= 0
+		case RoundHalfEven:
+			away = cmp > 0 || (cmp == 0 && quo.Bit(0) == 1)
+		}
+		if away {
+			quo.Add(quo, big.NewInt(int64(product.Sign())))
+		}
+	}
+
+	if !quo.IsInt64() {
+		return Money{}, ErrMoneyOverflow
+	}
+	return Money{Amount: quo.Int64(), Currency: m.Currency}, nil
+}
+
+// Min returns the smaller of two amounts of the same currency.
+func (m Money) Min(other Money) (Money, error) {
+	currency, err := m.sameCurrency(other)
+	if err != nil {
+		return Money{}, err
+	}
+	amount := m.Amount
+	if other.Amount < amount {
+		amount = other.Amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// String formats the amount as a decimal string, e.g. "-1234.50".
+func (m Money) String() string {
+	digits := minorDigits(m.Currency)
+	if digits == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	unit := pow10(digits)
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+	}
+	major := amount / unit
+	minor := amount % unit
+	if major < 0 {
+		major = -major
+	}
+	if minor < 0 {
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, major, digits, minor)
+}
+
+// ParseMoney parses a decimal string such as "999", "999.9" or "-0.50".
+// More fraction digits than the currency allows are rejected.
+func ParseMoney(value, currency string) (Money, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Money{}, fmt.Errorf("%w: empty value", ErrInvalidMoney)
+	}
+
+	negative := false
+	switch value[0] {
+	case '-':
+		negative = true
+		value = value[1:]
+	case '+':
+		value = value[1:]
+	}
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	digits := minorDigits(currency)
+	if whole == "" || len(fraction) > digits || !isDigits(whole) || !isDigits(fraction) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+
+	major, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return Money{}, ErrMoneyOverflow
+	}
+	var minor int64
+	if fraction != "" {
+		minor, _ = strconv.ParseInt(fraction, 10, 64)
+		minor *= pow10(digits - len(fraction))
+	}
+
+	unit := pow10(digits)
+	if major > (math.MaxInt64-minor)/unit {
+		return Money{}, ErrMoneyOverflow
+	}
+	amount := major*unit + minor
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts both decimal strings and JSON numbers. The currency
+// is not part of the JSON value, so amounts are parsed with the precision of
+// the currency already set on m, or in DefaultCurrency when it is empty.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidMoney, data)
+		}
+		value = number.String()
+	}
+
+	currency := m.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	parsed, err := ParseMoney(value, currency)
+	if err != nil {
+		return err
+	}
+	m.Amount, m.Currency = parsed.Amount, currency
+	return nil
+}
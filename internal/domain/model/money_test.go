@@ -0,0 +1,189 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestMoneyMulRatioRounding(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		num, den int64
+		mode     RoundingMode
+		want     int64
+	}{
+		{"exact", 1000, 3, 1, RoundHalfUp, 3000},
+		{"half up below half", 100, 1, 3, RoundHalfUp, 33},
+		{"half up at half", 5, 1, 2, RoundHalfUp, 3},
+		{"half up negative at half", -5, 1, 2, RoundHalfUp, -3},
+		{"half even rounds to even down", 5, 1, 2, RoundHalfEven, 2},
+		{"half even rounds to even up", 7, 1, 2, RoundHalfEven, 4},
+		{"half even above half", 200, 1, 3, RoundHalfEven, 67},
+		{"half even negative", -7, 1, 2, RoundHalfEven, -4},
+		{"down", 200, 1, 3, RoundDown, 66},
+		{"down negative", -200, 1, 3, RoundDown, -66},
+		{"up", 100, 1, 3, RoundUp, 34},
+		{"up negative", -100, 1, 3, RoundUp, -34},
+		{"negative denominator", 100, 1, -3, RoundHalfUp, -33},
+		{"large intermediate product", math.MaxInt64, 2, 4, RoundDown, math.MaxInt64 / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewMoney(tt.amount, "RUB").MulRatio(tt.num, tt.den, tt.mode)
+			if err != nil {
+				t.Fatalf("MulRatio() error = %v", err)
+			}
+			if got.Amount != tt.want || got.Currency != "RUB" {
+				t.Errorf("MulRatio() = %d %s, want %d RUB", got.Amount, got.Currency, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyMulRatioErrors(t *testing.T) {
+	if _, err := NewMoney(100, "RUB").MulRatio(1, 0, RoundHalfUp); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("division by zero: error = %v, want ErrInvalidMoney", err)
+	}
+	if _, err := NewMoney(math.MaxInt64, "RUB").Mul(2); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("overflow: error = %v, want ErrMoneyOverflow", err)
+	}
+}
+
+func TestMoneyAddCurrency(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{"same currency", NewMoney(100, "RUB"), NewMoney(50, "RUB"), NewMoney(150, "RUB"), nil},
+		{"zero value on the left", Money{}, NewMoney(50, "USD"), NewMoney(50, "USD"), nil},
+		{"zero value on the right", NewMoney(50, "USD"), Money{}, NewMoney(50, "USD"), nil},
+		{"different currencies", NewMoney(100, "RUB"), NewMoney(50, "USD"), Money{}, ErrCurrencyMismatch},
+		{"zero amount keeps its currency", NewMoney(0, "RUB"), NewMoney(50, "USD"), Money{}, ErrCurrencyMismatch},
+		{"overflow", NewMoney(math.MaxInt64, "RUB"), NewMoney(1, "RUB"), Money{}, ErrMoneyOverflow},
+		{"negative overflow", NewMoney(math.MinInt64, "RUB"), NewMoney(-1, "RUB"), Money{}, ErrMoneyOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Add() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Add() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneySub(t *testing.T) {
+	got, err := Money{}.Sub(NewMoney(150, "EUR"))
+	if err != nil || got != NewMoney(-150, "EUR") {
+		t.Errorf("Sub() = %+v, %v, want -150 EUR", got, err)
+	}
+	if _, err := NewMoney(100, "RUB").Sub(NewMoney(1, "EUR")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Sub() error = %v, want ErrCurrencyMismatch", err)
+	}
+	if _, err := NewMoney(0, "RUB").Sub(NewMoney(math.MinInt64, "RUB")); !errors.Is(err, ErrMoneyOverflow) {
+		t.Errorf("Sub() error = %v, want ErrMoneyOverflow", err)
+	}
+}
+
+func TestMoneyMin(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{"smaller on the left", NewMoney(100, "RUB"), NewMoney(150, "RUB"), NewMoney(100, "RUB"), nil},
+		{"smaller on the right", NewMoney(150, "RUB"), NewMoney(100, "RUB"), NewMoney(100, "RUB"), nil},
+		{"zero value", Money{}, NewMoney(100, "USD"), NewMoney(0, "USD"), nil},
+		{"different currencies", NewMoney(100, "RUB"), NewMoney(50, "USD"), Money{}, ErrCurrencyMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Min(tt.b)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Min() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Min() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{NewMoney(123450, "RUB"), "1234.50"},
+		{NewMoney(-5, "USD"), "-0.05"},
+		{NewMoney(0, "RUB"), "0.00"},
+		{NewMoney(1500, "JPY"), "1500"},
+		{NewMoney(1234, "KWD"), "1.234"},
+	}
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.money, got, tt.want)
+		}
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value    string
+		currency string
+		want     int64
+		wantErr  error
+	}{
+		{"999", "RUB", 99900, nil},
+		{"999.9", "RUB", 99990, nil},
+		{" -0.50 ", "RUB", -50, nil},
+		{"+1.05", "USD", 105, nil},
+		{"1500", "JPY", 1500, nil},
+		{"1.5", "JPY", 0, ErrInvalidMoney},
+		{"1.005", "RUB", 0, ErrInvalidMoney},
+		{"", "RUB", 0, ErrInvalidMoney},
+		{".50", "RUB", 0, ErrInvalidMoney},
+		{"1,5", "RUB", 0, ErrInvalidMoney},
+		{"92233720368547758.08", "RUB", 0, ErrMoneyOverflow},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.value, tt.currency)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseMoney(%q) error = %v, want %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if err == nil && (got.Amount != tt.want || got.Currency != tt.currency) {
+			t.Errorf("ParseMoney(%q) = %+v, want %d %s", tt.value, got, tt.want, tt.currency)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(NewMoney(99900, "RUB"))
+	if err != nil || string(data) != `"999.00"` {
+		t.Fatalf("Marshal() = %s, %v, want \"999.00\"", data, err)
+	}
+
+	m := Money{Currency: "JPY"}
+	if err := json.Unmarshal([]byte(`1500`), &m); err != nil || m.Amount != 1500 {
+		t.Errorf("Unmarshal(1500) into JPY = %+v, %v", m, err)
+	}
+
+	// Без валюты сумма читается в валюте по умолчанию и получает её
+	var def Money
+	if err := json.Unmarshal([]byte(`"12.5"`), &def); err != nil || def != NewMoney(1250, DefaultCurrency) {
+		t.Errorf("Unmarshal(\"12.5\") = %+v, %v, want 1250 %s", def, err, DefaultCurrency)
+	}
+	if _, err := def.Add(NewMoney(100, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() to USD error = %v, want ErrCurrencyMismatch", err)
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationPreferences.*DaysBefore set how early reminders are sent, 0 disables them.
+type NotificationPreferences struct {
+	UserID            uuid.UUID `db:"user_id"`
+	Email             string    `db:"email"`
+	Enabled           bool      `db:"enabled"`
+	RenewalDaysBefore int       `db:"renewal_days_before"`
+	TrialDaysBefore   int       `db:"trial_days_before"`
+	UpdatedAt         time.Time `db:"updated_at"`
+}
+
+type ReminderKind string
+
+const (
+	ReminderRenewal  ReminderKind = "renewal"
+	ReminderTrialEnd ReminderKind = "trial_end"
+)
+
+// Reminder announces a charge of Amount on Date. For trial ends it is the first paid charge.
+type Reminder struct {
+	Kind         ReminderKind
+	Subscription *Subscription
+	Date         time.Time
+	Amount       Money
+}
@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChange sets a new subscription price starting from EffectiveFrom month.
+// Price is always in the currency of the subscription.
+type PriceChange struct {
+	ID             uuid.UUID `db:"id"`
+	SubscriptionID uuid.UUID `db:"subscription_id"`
+	Price          Money     `db:"price"`
+	EffectiveFrom  time.Time `db:"effective_from"`
+}
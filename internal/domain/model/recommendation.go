@@ -0,0 +1,23 @@
+package model
+
+import "github.com/google/uuid"
+
+// Recommendation is a savings suggestion produced by a recommendation rule.
+// Score ranges from 0 to 100, higher means more relevant. MonthlySavings is
+// the estimated amount saved per month when the suggestion is applied, zero
+// when it cannot be estimated.
+type Recommendation struct {
+	Rule            string
+	ServiceName     string
+	SubscriptionIDs []uuid.UUID
+	Score           int
+	MonthlySavings  Money
+	Explanation     string
+}
+
+// ServicePrice is a known price of a service for a billing period.
+type ServicePrice struct {
+	ServiceName   string        `db:"service_name"`
+	BillingPeriod BillingPeriod `db:"billing_period"`
+	Price         Money         `db:"price"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transaction is a single bank statement entry. Charges have negative amounts.
+type Transaction struct {
+	Date        time.Time
+	Description string
+	Amount      Money
+}
+
+// SubscriptionCandidate is a recurring charge found in a bank statement
+// which looks like a subscription.
+type SubscriptionCandidate struct {
+	ServiceName   string
+	Merchant      string // описание списания в выписке, как есть
+	Price         Money  // последняя сумма списания
+	BillingPeriod BillingPeriod
+	StartDate     time.Time
+	// EndDate is set when the charges stopped before the end of the statement.
+	EndDate     *time.Time
+	LastCharge  time.Time
+	Occurrences int
+	// Confidence from 0 to 100 grows with the number of charges and their regularity.
+	Confidence int
+	// ExistingSubscriptionID points to a subscription the user already tracks for this service.
+	ExistingSubscriptionID *uuid.UUID
+}
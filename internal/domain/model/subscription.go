@@ -6,12 +6,121 @@ import (
 	"github.com/google/uuid"
 )
 
+type BillingPeriod string
+
+const (
+	BillingPeriodMonthly   BillingPeriod = "monthly"
+	BillingPeriodQuarterly BillingPeriod = "quarterly"
+	BillingPeriodYearly    BillingPeriod = "yearly"
+)
+
+// Months returns the length of the billing period in months.
+// Unknown values are treated as monthly billing.
+func (p BillingPeriod) Months() int {
+	switch p {
+	case BillingPeriodQuarterly:
+		return 3
+	case BillingPeriodYearly:
+		return 12
+	default:
+		return 1
+	}
+}
+
+type SubscriptionStatus string
+
+const (
+	StatusScheduled SubscriptionStatus = "scheduled" // ещё не началась
+	StatusTrial     SubscriptionStatus = "trial"
+	StatusActive    SubscriptionStatus = "active"
+	StatusExpired   SubscriptionStatus = "expired"   // закончилась по end_date
+	StatusCancelled SubscriptionStatus = "cancelled" // закончилась после отмены
+)
+
+var SubscriptionStatuses = []SubscriptionStatus{StatusScheduled, StatusTrial, StatusActive, StatusExpired, StatusCancelled}
+
+// DeletionReason tells whether a deleted subscription still counts in past costs.
+type DeletionReason string
+
+const (
+	DeletionMistake DeletionReason = "mistake" // запись была ошибкой, в расходах не учитывается
+	DeletionEnded   DeletionReason = "ended"   // подписка закончилась, месяцы до удаления остаются в расходах
+)
+
+// Subscription.TaxRate is expressed in basis points (2000 = 20%).
+// PriceIncludesTax tells whether Price is a gross or a net amount.
+// Status is stored to filter by it, StatusAt tells what it should be.
+// CancelledAt is set while the subscription runs until the end of a cancelled period and after it ends,
+// CancelledEndDate keeps the end date it had before the cancellation to restore it on reactivation.
+// EndedAt is set when it was cancelled immediately: the month in progress stays paid, but it's over.
+// DeletionReason is empty unless the subscription is deleted, DeletedAt is maintained by the database.
 type Subscription struct {
-	ID          uuid.UUID  `db:"id"`
-	ServiceName string     `db:"service_name"`
-	Price       int        `db:"price"`
-	UserID      uuid.UUID  `db:"user_id"`
-	StartDate   time.Time  `db:"start_date"`
-	EndDate     *time.Time `db:"end_date"`
-	IsDeleted   bool       `db:"is_deleted"`
+	ID               uuid.UUID          `db:"id"`
+	ServiceName      string             `db:"service_name"`
+	Price            Money              `db:"price"`
+	UserID           uuid.UUID          `db:"user_id"`
+	StartDate        time.Time          `db:"start_date"`
+	EndDate          *time.Time         `db:"end_date"`
+	BillingPeriod    BillingPeriod      `db:"billing_period"`
+	TrialEndDate     *time.Time         `db:"trial_end_date"`
+	TaxRate          int                `db:"tax_rate"`
+	PriceIncludesTax bool               `db:"price_includes_tax"`
+	IsDeleted        bool               `db:"is_deleted"`
+	Status           SubscriptionStatus `db:"status"`
+	CancelledAt      *time.Time         `db:"cancelled_at"`
+	CancelledEndDate *time.Time         `db:"cancelled_end_date"`
+	EndedAt          *time.Time         `db:"ended_at"`
+	DeletionReason   DeletionReason     `db:"deletion_reason"`
+	DeletedAt        *time.Time         `db:"deleted_at"`
+}
+
+type CancelMode string
+
+const (
+	CancelImmediately CancelMode = "immediately"
+	CancelAtPeriodEnd CancelMode = "at_period_end"
+)
+
+// StatusAt derives the status for the month containing t in UTC.
+// Months before the trial end month are the trial.
+func (s Subscription) StatusAt(t time.Time) SubscriptionStatus {
+	t = t.UTC()
+	month := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	switch {
+	case s.EndedAt != nil && !s.EndedAt.After(t):
+		return StatusCancelled
+	case s.EndDate != nil && s.EndDate.Before(month):
+		if s.CancelledAt != nil {
+			return StatusCancelled
+		}
+		return StatusExpired
+	case s.StartDate.After(month):
+		return StatusScheduled
+	case s.TrialEndDate != nil && s.TrialEndDate.After(month):
+		return StatusTrial
+	}
+	return StatusActive
+}
+
+// HasEnded reports whether the status is final: only changing the dates brings the subscription back.
+func (st SubscriptionStatus) HasEnded() bool {
+	return st == StatusExpired || st == StatusCancelled
+}
+
+// SplitTax splits an amount entered in the subscription's price basis into
+// net and tax parts, rounding tax half up.
+func (s Subscription) SplitTax(amount Money) (net, tax Money, err error) {
+	if s.TaxRate == 0 {
+		return amount, Money{Currency: amount.Currency}, nil
+	}
+	if s.PriceIncludesTax {
+		net, err = amount.MulRatio(10000, int64(10000+s.TaxRate), RoundHalfUp)
+		if err != nil {
+			return Money{}, Money{}, err
+		}
+		tax, err = amount.Sub(net)
+		return net, tax, err
+	}
+	tax, err = amount.MulRatio(int64(s.TaxRate), 10000, RoundHalfUp)
+	return amount, tax, err
 }
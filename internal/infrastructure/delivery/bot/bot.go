@@ -0,0 +1,119 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/telegram"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+)
+
+const retryDelay = 5 * time.Second
+
+// handler answers a command of a linked chat, args are the words after the command.
+type handler func(ctx context.Context, link *model.ChatLink, args []string) string
+
+// Bot manages subscriptions of linked users through Telegram commands.
+type Bot struct {
+	client        telegram.Client
+	subs          port.SubscriptionService
+	links         port.ChatLinkService
+	notifications port.NotificationService
+	pollTimeout   time.Duration
+	handlers      map[string]handler
+}
+
+func NewBot(client telegram.Client, subs port.SubscriptionService, links port.ChatLinkService, notifications port.NotificationService, pollTimeout time.Duration) *Bot {
+	b := &Bot{
+		client:        client,
+		subs:          subs,
+		links:         links,
+		notifications: notifications,
+		pollTimeout:   pollTimeout,
+	}
+	b.handlers = map[string]handler{
+		"/list":       b.list,
+		"/add":        b.add,
+		"/cost":       b.cost,
+		"/cancel":     b.cancel,
+		"/reactivate": b.reactivate,
+		"/reminders":  b.reminders,
+	}
+	return b
+}
+
+// Run polls for messages until ctx is cancelled.
+func (b *Bot) Run(ctx context.Context) {
+	var offset int64
+	for {
+		updates, err := b.client.GetUpdates(ctx, offset, b.pollTimeout)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			logger.Log.Errorf("bot: get updates: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryDelay):
+			}
+			continue
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil || u.Message.Text == "" {
+				continue
+			}
+			chatID := u.Message.Chat.ID
+			reply := b.Handle(ctx, chatID, u.Message.Text)
+			if err := b.client.SendMessage(ctx, chatID, reply); err != nil {
+				logger.Log.Errorf("bot: reply to chat %d: %v", chatID, err)
+			}
+		}
+	}
+}
+
+// Handle executes the command in text and returns the reply.
+func (b *Bot) Handle(ctx context.Context, chatID int64, text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return helpText
+	}
+	// В группах команда приходит как /list@bot_name
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	args := fields[1:]
+
+	ctx = port.WithActor(ctx, "telegram:"+strconv.FormatInt(chatID, 10))
+
+	switch command {
+	case "/start", "/link":
+		if len(args) == 0 {
+			return helpText
+		}
+		return b.link(ctx, chatID, args[0])
+	case "/unlink":
+		return b.unlink(ctx, chatID)
+	case "/help":
+		return helpText
+	}
+
+	h, ok := b.handlers[command]
+	if !ok {
+		return "Unknown command.\n\n" + helpText
+	}
+
+	link, err := b.links.GetLink(ctx, chatID)
+	if err != nil {
+		logger.Log.Errorf("bot: get link of chat %d: %v", chatID, err)
+		return internalErrorText
+	}
+	if link == nil {
+		return notLinkedText
+	}
+	return h(ctx, link, args)
+}
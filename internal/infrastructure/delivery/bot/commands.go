@@ -0,0 +1,302 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/google/uuid"
+)
+
+const monthLayout = "01-2006"
+
+const helpText = `Commands:
+/link <token> — link this chat to your account, get the token from POST /users/{id}/telegram/link
+/list — your subscriptions
+/add <service> <price> [currency] [MM-YYYY] [monthly|quarterly|yearly] — add a subscription
+/cost <MM-YYYY> [MM-YYYY] [currency] — total cost for the period
+/cancel <number|id> [now] — stop a subscription at the end of the paid period, or right away
+/reactivate <number|id> — undo a cancellation
+/reminders on|off — renewal and trial end reminders
+/unlink — unlink this chat`
+
+const (
+	notLinkedText     = "This chat isn't linked to an account yet. Send /link <token>."
+	internalErrorText = "Something went wrong, please try again later."
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+func (b *Bot) link(ctx context.Context, chatID int64, token string) string {
+	link, err := b.links.LinkChat(ctx, chatID, token)
+	if errors.Is(err, model.ErrInvalidLinkToken) {
+		return "The token is invalid or expired, request a new one."
+	}
+	if err != nil {
+		logger.Log.Errorf("bot: link chat %d: %v", chatID, err)
+		return internalErrorText
+	}
+
+	logger.Log.Infof("bot: chat %d linked to user %s", chatID, link.UserID)
+	return "The chat is linked. Send /reminders on to get renewal reminders here.\n\n" + helpText
+}
+
+func (b *Bot) unlink(ctx context.Context, chatID int64) string {
+	if err := b.links.UnlinkChat(ctx, chatID); err != nil {
+		logger.Log.Errorf("bot: unlink chat %d: %v", chatID, err)
+		return internalErrorText
+	}
+	return "The chat is unlinked."
+}
+
+func (b *Bot) list(ctx context.Context, link *model.ChatLink, _ []string) string {
+	subs, err := b.userSubscriptions(ctx, link)
+	if err != nil {
+		logger.Log.Errorf("bot: list subscriptions of user %s: %v", link.UserID, err)
+		return internalErrorText
+	}
+	if len(subs) == 0 {
+		return "You have no subscriptions. Add one with /add."
+	}
+
+	var sb strings.Builder
+	for i, sub := range subs {
+		fmt.Fprintf(&sb, "%d. %s — %s %s %s, %s since %s", i+1, sub.ServiceName, sub.Price, sub.Price.Currency, billingPeriod(sub), sub.Status, sub.StartDate.Format(monthLayout))
+		if sub.EndDate != nil {
+			fmt.Fprintf(&sb, " until %s", sub.EndDate.Format(monthLayout))
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// add parses "<service> <price> [currency] [MM-YYYY] [period]" from the end,
+// so that service names may contain spaces.
+func (b *Bot) add(ctx context.Context, link *model.ChatLink, args []string) string {
+	const usage = "Usage: /add <service> <price> [currency] [MM-YYYY] [monthly|quarterly|yearly]"
+
+	req := dto.CreateSubscriptionRequest{
+		UserID:    link.UserID.String(),
+		StartDate: time.Now().UTC().Format(monthLayout),
+	}
+	if n := len(args); n > 0 {
+		switch p := strings.ToLower(args[n-1]); model.BillingPeriod(p) {
+		case model.BillingPeriodMonthly, model.BillingPeriodQuarterly, model.BillingPeriodYearly:
+			req.BillingPeriod = p
+			args = args[:n-1]
+		}
+	}
+	if n := len(args); n > 0 {
+		if _, err := time.Parse(monthLayout, args[n-1]); err == nil {
+			req.StartDate = args[n-1]
+			args = args[:n-1]
+		}
+	}
+	if n := len(args); n > 0 && currencyPattern.MatchString(strings.ToUpper(args[n-1])) {
+		req.Currency = strings.ToUpper(args[n-1])
+		args = args[:n-1]
+	}
+	if len(args) < 2 {
+		return usage
+	}
+	req.Price = json.Number(strings.Replace(args[len(args)-1], ",", ".", 1))
+	req.ServiceName = strings.Join(args[:len(args)-1], " ")
+
+	sub, err := mapper.ToSubscriptionModel(req)
+	if err != nil {
+		return err.Error() + "\n" + usage
+	}
+	if err := b.subs.CreateSubscription(ctx, sub, false); err != nil {
+		logger.Log.Errorf("bot: create subscription for user %s: %v", link.UserID, err)
+		return internalErrorText
+	}
+
+	logger.Log.Infof("bot: created subscription %s for user %s", sub.ID, link.UserID)
+	return fmt.Sprintf("Added %s — %s %s %s, since %s.", sub.ServiceName, sub.Price, sub.Price.Currency, billingPeriod(sub), sub.StartDate.Format(monthLayout))
+}
+
+func (b *Bot) cost(ctx context.Context, link *model.ChatLink, args []string) string {
+	const usage = "Usage: /cost <MM-YYYY> [MM-YYYY] [currency]"
+
+	var currency *string
+	if n := len(args); n > 1 && currencyPattern.MatchString(strings.ToUpper(args[n-1])) {
+		code := strings.ToUpper(args[n-1])
+		currency = &code
+		args = args[:n-1]
+	}
+	if len(args) == 0 || len(args) > 2 {
+		return usage
+	}
+	from, err := time.Parse(monthLayout, args[0])
+	if err != nil {
+		return usage
+	}
+	to := from
+	if len(args) == 2 {
+		if to, err = time.Parse(monthLayout, args[1]); err != nil {
+			return usage
+		}
+	}
+	if to.Before(from) {
+		return "The end of the period is before its start."
+	}
+
+	total, err := b.subs.CalculateTotalCost(ctx, &link.UserID, nil, currency, from, to, true)
+	switch {
+	case errors.Is(err, model.ErrCurrencyMismatch):
+		return "Your subscriptions are billed in different currencies, add one to the command, e.g. /cost " + args[0] + " RUB."
+	case err != nil:
+		logger.Log.Errorf("bot: calculate cost for user %s: %v", link.UserID, err)
+		return internalErrorText
+	}
+
+	period := from.Format(monthLayout)
+	if !to.Equal(from) {
+		period += " – " + to.Format(monthLayout)
+	}
+	text := fmt.Sprintf("Total for %s: %s %s", period, total.Gross, total.Gross.Currency)
+	if total.Discount.Amount != 0 {
+		text += fmt.Sprintf("\nDiscounts: %s %s", total.Discount, total.Discount.Currency)
+	}
+	if total.Tax.Amount != 0 {
+		text += fmt.Sprintf("\nIncluding tax: %s %s", total.Tax, total.Tax.Currency)
+	}
+	return text
+}
+
+// cancel stops the subscription at the end of the billing period in progress,
+// or right away with "now".
+func (b *Bot) cancel(ctx context.Context, link *model.ChatLink, args []string) string {
+	mode := model.CancelAtPeriodEnd
+	if len(args) == 2 && strings.EqualFold(args[1], "now") {
+		mode = model.CancelImmediately
+		args = args[:1]
+	}
+
+	fn := func(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+		return b.subs.CancelSubscription(ctx, id, mode)
+	}
+	return b.transition(ctx, link, args, "/cancel", fn, func(sub *model.Subscription) string {
+		if sub.EndedAt != nil {
+			return fmt.Sprintf("%s is cancelled and has ended.", sub.ServiceName)
+		}
+		return fmt.Sprintf("%s is cancelled, the last paid month is %s. Changed your mind? Send /reactivate.", sub.ServiceName, sub.EndDate.Format(monthLayout))
+	})
+}
+
+func (b *Bot) reactivate(ctx context.Context, link *model.ChatLink, args []string) string {
+	return b.transition(ctx, link, args, "/reactivate", b.subs.ReactivateSubscription, func(sub *model.Subscription) string {
+		if sub.EndDate != nil {
+			return fmt.Sprintf("%s continues until %s.", sub.ServiceName, sub.EndDate.Format(monthLayout))
+		}
+		return fmt.Sprintf("%s continues without an end date.", sub.ServiceName)
+	})
+}
+
+func (b *Bot) transition(ctx context.Context, link *model.ChatLink, args []string, command string, fn func(context.Context, uuid.UUID) (*model.Subscription, error), done func(*model.Subscription) string) string {
+	if len(args) != 1 {
+		return "Usage: " + command + " <number|id>, numbers are shown by /list"
+	}
+	sub, err := b.findSubscription(ctx, link, args[0])
+	if err != nil {
+		logger.Log.Errorf("bot: find subscription for user %s: %v", link.UserID, err)
+		return internalErrorText
+	}
+	if sub == nil {
+		return "Subscription not found, check /list."
+	}
+
+	sub, err = fn(ctx, sub.ID)
+	switch {
+	case errors.Is(err, model.ErrInvalidTransition):
+		return "Can't do that: " + strings.TrimPrefix(err.Error(), model.ErrInvalidTransition.Error()+": ") + "."
+	case errors.Is(err, model.ErrSubscriptionNotFound):
+		return "Subscription not found, check /list."
+	case err != nil:
+		logger.Log.Errorf("bot: %s subscription for user %s: %v", command, link.UserID, err)
+		return internalErrorText
+	}
+
+	logger.Log.Infof("bot: %s subscription %s, now %s", command, sub.ID, sub.Status)
+	return done(sub)
+}
+
+func (b *Bot) reminders(ctx context.Context, link *model.ChatLink, args []string) string {
+	prefs, err := b.notifications.GetPreferences(ctx, link.UserID)
+	if err != nil {
+		logger.Log.Errorf("bot: get preferences of user %s: %v", link.UserID, err)
+		return internalErrorText
+	}
+
+	if len(args) == 0 {
+		if !prefs.Enabled {
+			return "Reminders are off. Send /reminders on to enable them."
+		}
+		return fmt.Sprintf("Reminders are on: %d days before renewals and %d days before trial ends.", prefs.RenewalDaysBefore, prefs.TrialDaysBefore)
+	}
+
+	switch strings.ToLower(args[0]) {
+	case "on":
+		prefs.Enabled = true
+	case "off":
+		prefs.Enabled = false
+	default:
+		return "Usage: /reminders on|off"
+	}
+	if err := b.notifications.UpdatePreferences(ctx, prefs); err != nil {
+		logger.Log.Errorf("bot: update preferences of user %s: %v", link.UserID, err)
+		return internalErrorText
+	}
+
+	if prefs.Enabled {
+		return "Reminders are on."
+	}
+	return "Reminders are off."
+}
+
+func (b *Bot) userSubscriptions(ctx context.Context, link *model.ChatLink) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+	err := b.subs.StreamSubscriptions(ctx, &link.UserID, nil, func(sub *model.Subscription) error {
+		subs = append(subs, sub)
+		return nil
+	})
+	return subs, err
+}
+
+// findSubscription accepts a number from /list or a subscription id,
+// subscriptions of other users are not found.
+func (b *Bot) findSubscription(ctx context.Context, link *model.ChatLink, ref string) (*model.Subscription, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		sub, err := b.subs.GetSubscription(ctx, id)
+		if err != nil || sub == nil || sub.UserID != link.UserID || sub.IsDeleted {
+			return nil, err
+		}
+		return sub, nil
+	}
+
+	n, err := strconv.Atoi(ref)
+	if err != nil || n < 1 {
+		return nil, nil
+	}
+	subs, err := b.userSubscriptions(ctx, link)
+	if err != nil || n > len(subs) {
+		return nil, err
+	}
+	return subs[n-1], nil
+}
+
+func billingPeriod(sub *model.Subscription) string {
+	if sub.BillingPeriod == "" {
+		return string(model.BillingPeriodMonthly)
+	}
+	return string(sub.BillingPeriod)
+}
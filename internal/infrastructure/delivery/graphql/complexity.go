@@ -0,0 +1,194 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// defaultListSize is assumed for lists without a limit, like members of a subscription.
+	defaultListSize = 10
+	// costWeight is charged for fields calculating costs on top of loading subscriptions.
+	costWeight = 10
+
+	complexityCap = 1 << 40
+)
+
+var fieldWeights = map[string]int{
+	"Query.cost":          costWeight,
+	"Query.costByService": costWeight,
+	"User.cost":           costWeight,
+}
+
+// Limits bound queries before they are executed. Every object field costs 1 or its weight,
+// a list field adds 1 per item plus the cost of its selection multiplied by the expected list size.
+type Limits struct {
+	MaxDepth      int
+	MaxComplexity int
+}
+
+type queryCost struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]any
+	depth     int
+}
+
+// checkLimits expects a validated document, so unknown fields and fragment cycles are already rejected.
+func checkLimits(schema *graphql.Schema, doc *ast.Document, operationName string, variables map[string]any, limits Limits) error {
+	q := &queryCost{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			q.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		}
+	}
+	if operation == nil {
+		return nil
+	}
+
+	complexity := q.selectionSet(schema.QueryType(), operation.SelectionSet, 1)
+	if limits.MaxDepth > 0 && q.depth > limits.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", q.depth, limits.MaxDepth)
+	}
+	if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", complexity, limits.MaxComplexity)
+	}
+	return nil
+}
+
+func (q *queryCost) selectionSet(parent graphql.Type, set *ast.SelectionSet, depth int) int {
+	if set == nil {
+		return 0
+	}
+
+	total := 0
+	for _, selection := range set.Selections {
+		switch sel := selection.(type) {
+		case *ast.Field:
+			total = add(total, q.field(parent, sel, depth))
+		case *ast.InlineFragment:
+			total = add(total, q.selectionSet(q.typeCondition(sel.TypeCondition, parent), sel.SelectionSet, depth))
+		case *ast.FragmentSpread:
+			if fragment, ok := q.fragments[sel.Name.Value]; ok {
+				total = add(total, q.selectionSet(q.typeCondition(fragment.TypeCondition, parent), fragment.SelectionSet, depth))
+			}
+		}
+	}
+	return total
+}
+
+func (q *queryCost) field(parent graphql.Type, field *ast.Field, depth int) int {
+	q.depth = max(q.depth, depth)
+
+	object, ok := parent.(*graphql.Object)
+	if !ok || strings.HasPrefix(field.Name.Value, "__") {
+		return 0
+	}
+	def, ok := object.Fields()[field.Name.Value]
+	if !ok || field.SelectionSet == nil {
+		return 0
+	}
+
+	weight := 1
+	if w, ok := fieldWeights[object.Name()+"."+def.Name]; ok {
+		weight = w
+	}
+	named, _ := graphql.GetNamed(def.Type).(graphql.Type)
+	children := q.selectionSet(named, field.SelectionSet, depth+1)
+	if _, isList := graphql.GetNullable(def.Type).(*graphql.List); isList {
+		children = mul(add(children, 1), q.listSize(def, field))
+	}
+	return add(weight, children)
+}
+
+// listSize takes the expected size from the limit argument or the number of requested ids.
+func (q *queryCost) listSize(def *graphql.FieldDefinition, field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		switch arg.Name.Value {
+		case "limit":
+			if n, ok := q.intValue(arg.Value); ok {
+				return min(max(n, 0), maxLimit)
+			}
+		case "ids":
+			if n, ok := q.listLength(arg.Value); ok {
+				return min(n, maxUserIDs)
+			}
+		}
+	}
+	for _, arg := range def.Args {
+		if arg.Name() == "limit" {
+			if n, ok := arg.DefaultValue.(int); ok {
+				return n
+			}
+		}
+	}
+	return defaultListSize
+}
+
+func (q *queryCost) intValue(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(v.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := q.variables[v.Name.Value].(type) {
+		case int:
+			return n, true
+		case float64:
+			return int(min(n, complexityCap)), true
+		}
+	}
+	return 0, false
+}
+
+func (q *queryCost) listLength(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.ListValue:
+		return len(v.Values), true
+	case *ast.Variable:
+		if list, ok := q.variables[v.Name.Value].([]any); ok {
+			return len(list), true
+		}
+	}
+	return 0, false
+}
+
+func (q *queryCost) typeCondition(named *ast.Named, parent graphql.Type) graphql.Type {
+	if named == nil {
+		return parent
+	}
+	if t := q.schema.Type(named.Name.Value); t != nil {
+		return t
+	}
+	return parent
+}
+
+// add and mul saturate, so a deeply nested query can't overflow its way under the limit.
+func add(a, b int) int {
+	return min(a+b, complexityCap)
+}
+
+func mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	if a > complexityCap/b {
+		return complexityCap
+	}
+	return a * b
+}
@@ -0,0 +1,88 @@
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type Handler struct {
+	schema  graphql.Schema
+	service port.SubscriptionService
+	limits  Limits
+}
+
+func NewHandler(service port.SubscriptionService, limits Limits) (*Handler, error) {
+	schema, err := NewSchema(service)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: schema, service: service, limits: limits}, nil
+}
+
+// Query godoc
+// @Summary GraphQL query
+// @Description Read-only GraphQL API over subscriptions, users and cost aggregates. Queries over the depth or complexity limit are rejected before execution. GET takes query, operationName and JSON-encoded variables as query parameters.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body dto.GraphQLRequest true "GraphQL request"
+// @Success 200 {object} object
+// @Failure 400 {object} object
+// @Router /graphql [post]
+func (h *Handler) Query(c *gin.Context) {
+	var req dto.GraphQLRequest
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+		if vars := c.Query("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				c.JSON(http.StatusBadRequest, errorResult("invalid variables: "+err.Error()))
+				return
+			}
+		}
+		if req.Query == "" {
+			c.JSON(http.StatusBadRequest, errorResult("query is required"))
+			return
+		}
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResult(err.Error()))
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"})})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.FormatError(err)}})
+		return
+	}
+	if validation := graphql.ValidateDocument(&h.schema, doc, nil); !validation.IsValid {
+		c.JSON(http.StatusBadRequest, &graphql.Result{Errors: validation.Errors})
+		return
+	}
+	if err := checkLimits(&h.schema, doc, req.OperationName, req.Variables, h.limits); err != nil {
+		logger.Log.Infof("GraphQL: rejected query: %v", err)
+		c.JSON(http.StatusBadRequest, errorResult(err.Error()))
+		return
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        h.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       withLoaders(c.Request.Context(), h.service),
+	})
+	c.JSON(http.StatusOK, result)
+}
+
+func errorResult(message string) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{gqlerrors.NewFormattedError(message)}}
+}
@@ -0,0 +1,140 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// loader batches lookups by key within one request. Resolvers register a key and return a thunk,
+// the executor calls thunks only after resolving the whole level of the query, so the first call
+// fetches every key registered so far with a single query.
+type loader[V any] struct {
+	fetch func(ctx context.Context, keys []uuid.UUID) (map[uuid.UUID]V, error)
+
+	mu      sync.Mutex
+	pending []uuid.UUID
+	queued  map[uuid.UUID]bool
+	results map[uuid.UUID]V
+	errs    map[uuid.UUID]error
+}
+
+func newLoader[V any](fetch func(ctx context.Context, keys []uuid.UUID) (map[uuid.UUID]V, error)) *loader[V] {
+	return &loader[V]{
+		fetch:   fetch,
+		queued:  make(map[uuid.UUID]bool),
+		results: make(map[uuid.UUID]V),
+		errs:    make(map[uuid.UUID]error),
+	}
+}
+
+func (l *loader[V]) load(ctx context.Context, key uuid.UUID) func() (any, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (any, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			keys := l.pending
+			l.pending = nil
+			results, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				if err != nil {
+					l.errs[k] = err
+					continue
+				}
+				l.results[k] = results[k]
+			}
+		}
+		if err := l.errs[key]; err != nil {
+			return nil, err
+		}
+		return l.results[key], nil
+	}
+}
+
+type loaders struct {
+	service                    port.SubscriptionService
+	membersBySubscription      *loader[[]*model.SubscriptionMember]
+	priceChangesBySubscription *loader[[]*model.PriceChange]
+
+	mu        sync.Mutex
+	userPages map[page]*loader[[]*model.Subscription]
+	userCosts map[costFilter]*loader[model.CostBreakdown]
+}
+
+type page struct {
+	limit, offset int
+}
+
+// costFilter holds the arguments of User.cost, empty strings stand for unset filters.
+type costFilter struct {
+	serviceName, currency string
+	from, to              time.Time
+	includeDeleted        bool
+}
+
+type loadersKey struct{}
+
+// withLoaders attaches fresh loaders to a request, results are never shared between requests.
+func withLoaders(ctx context.Context, service port.SubscriptionService) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		service:                    service,
+		membersBySubscription:      newLoader(service.ListMembersBySubscriptions),
+		priceChangesBySubscription: newLoader(service.ListPriceChangesBySubscriptions),
+		userPages:                  make(map[page]*loader[[]*model.Subscription]),
+		userCosts:                  make(map[costFilter]*loader[model.CostBreakdown]),
+	})
+}
+
+// subscriptionsByUser batches users requested with the same page.
+func (l *loaders) subscriptionsByUser(limit, offset int) *loader[[]*model.Subscription] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := page{limit: limit, offset: offset}
+	if ld, ok := l.userPages[key]; ok {
+		return ld
+	}
+	ld := newLoader(func(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]*model.Subscription, error) {
+		return l.service.ListSubscriptionsByUsers(ctx, userIDs, limit, offset)
+	})
+	l.userPages[key] = ld
+	return ld
+}
+
+// costByUser batches users whose cost is requested with the same filter into one cost calculation.
+func (l *loaders) costByUser(serviceName, currency *string, from, to time.Time, includeDeleted bool) *loader[model.CostBreakdown] {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	key := costFilter{from: from, to: to, includeDeleted: includeDeleted}
+	if serviceName != nil {
+		key.serviceName = *serviceName
+	}
+	if currency != nil {
+		key.currency = *currency
+	}
+	if ld, ok := l.userCosts[key]; ok {
+		return ld
+	}
+	ld := newLoader(func(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID]model.CostBreakdown, error) {
+		return l.service.CalculateTotalCostByUsers(ctx, userIDs, serviceName, currency, from, to, includeDeleted)
+	})
+	l.userCosts[key] = ld
+	return ld
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
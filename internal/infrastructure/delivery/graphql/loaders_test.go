@@ -0,0 +1,118 @@
+package graphql
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/application/usecase"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+// countingRepo serves cost calculations from memory and counts the queries they make.
+type countingRepo struct {
+	port.SubscriptionRepository
+	subs    []*model.Subscription
+	members []*model.SubscriptionMember
+	calls   map[string]int
+}
+
+func (r *countingRepo) GetByFilter(ctx context.Context, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) ([]*model.Subscription, error) {
+	r.calls["GetByFilter"]++
+	return r.owned([]uuid.UUID{*userID}), nil
+}
+
+func (r *countingRepo) GetByFilterForUsers(ctx context.Context, userIDs []uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) ([]*model.Subscription, error) {
+	r.calls["GetByFilterForUsers"]++
+	return r.owned(userIDs), nil
+}
+
+func (r *countingRepo) ListPriceChanges(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.PriceChange, error) {
+	r.calls["ListPriceChanges"]++
+	return nil, nil
+}
+
+func (r *countingRepo) ListMembers(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.SubscriptionMember, error) {
+	r.calls["ListMembers"]++
+	return r.members, nil
+}
+
+func (r *countingRepo) ListDiscounts(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.Discount, error) {
+	r.calls["ListDiscounts"]++
+	return nil, nil
+}
+
+// owned returns subscriptions owned by or shared with any of userIDs.
+func (r *countingRepo) owned(userIDs []uuid.UUID) []*model.Subscription {
+	wanted := make(map[uuid.UUID]bool)
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+	var subs []*model.Subscription
+	for _, sub := range r.subs {
+		match := wanted[sub.UserID]
+		for _, m := range r.members {
+			match = match || (m.SubscriptionID == sub.ID && wanted[m.UserID])
+		}
+		if match {
+			subs = append(subs, sub)
+		}
+	}
+	return subs
+}
+
+func TestUserCostIsBatched(t *testing.T) {
+	alice, bob, carol, dave := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	netflix := &model.Subscription{ID: uuid.New(), ServiceName: "Netflix", Price: model.NewMoney(100000, "RUB"), UserID: alice, StartDate: start, BillingPeriod: model.BillingPeriodMonthly}
+	spotify := &model.Subscription{ID: uuid.New(), ServiceName: "Spotify", Price: model.NewMoney(30000, "RUB"), UserID: carol, StartDate: start, BillingPeriod: model.BillingPeriodMonthly}
+	repo := &countingRepo{
+		subs:    []*model.Subscription{netflix, spotify},
+		members: []*model.SubscriptionMember{{SubscriptionID: netflix.ID, UserID: bob, SplitType: model.SplitEqual}},
+		calls:   make(map[string]int),
+	}
+	service := usecase.NewSubscriptionService(repo, nil, nil, nil)
+	schema, err := NewSchema(service)
+	if err != nil {
+		t.Fatalf("NewSchema() error = %v", err)
+	}
+
+	query := `{ users(ids: ["` + strings.Join([]string{alice.String(), bob.String(), carol.String(), dave.String()}, `", "`) + `"]) {
+		id
+		cost(from: "01-2025", to: "02-2025") { gross }
+	} }`
+	result := graphql.Do(graphql.Params{Schema: schema, RequestString: query, Context: withLoaders(context.Background(), service)})
+	if len(result.Errors) > 0 {
+		t.Fatalf("query errors: %v", result.Errors)
+	}
+
+	// Одна выборка подписок и по одной на дочерние записи, сколько бы пользователей ни было
+	want := map[string]int{"GetByFilterForUsers": 1, "ListPriceChanges": 1, "ListMembers": 1, "ListDiscounts": 1}
+	for name, n := range want {
+		if repo.calls[name] != n {
+			t.Errorf("%s called %d times, want %d", name, repo.calls[name], n)
+		}
+	}
+	if repo.calls["GetByFilter"] != 0 {
+		t.Errorf("GetByFilter called %d times, want costs of all users in one query", repo.calls["GetByFilter"])
+	}
+
+	// Общая подписка делится поровну, пользователь без подписок получает ноль
+	gross := map[string]string{alice.String(): "1000.00", bob.String(): "1000.00", carol.String(): "600.00", dave.String(): "0.00"}
+	users := result.Data.(map[string]any)["users"].([]any)
+	if len(users) != len(gross) {
+		t.Fatalf("got %d users, want %d", len(users), len(gross))
+	}
+	for _, u := range users {
+		u := u.(map[string]any)
+		id := u["id"].(string)
+		got := u["cost"].(map[string]any)["gross"]
+		if got != gross[id] {
+			t.Errorf("cost of %s = %v, want %s", id, got, gross[id])
+		}
+	}
+}
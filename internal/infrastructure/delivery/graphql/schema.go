@@ -0,0 +1,594 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	monthLayout = "01-2006"
+
+	defaultLimit = 100
+	maxLimit     = 500
+	maxUserIDs   = 100
+)
+
+// serviceCost is a line of the costByService query.
+type serviceCost struct {
+	ServiceName string
+	Cost        model.CostBreakdown
+}
+
+type schemaBuilder struct {
+	service port.SubscriptionService
+}
+
+// NewSchema builds a read-only schema over subscriptions, their users and cost aggregates.
+// Users are not stored separately, a user is the ID subscriptions are owned by or shared with.
+func NewSchema(service port.SubscriptionService) (graphql.Schema, error) {
+	b := &schemaBuilder{service: service}
+
+	billingPeriod := graphql.NewEnum(graphql.EnumConfig{
+		Name: "BillingPeriod",
+		Values: graphql.EnumValueConfigMap{
+			"MONTHLY":   {Value: model.BillingPeriodMonthly},
+			"QUARTERLY": {Value: model.BillingPeriodQuarterly},
+			"YEARLY":    {Value: model.BillingPeriodYearly},
+		},
+	})
+	status := graphql.NewEnum(graphql.EnumConfig{
+		Name: "SubscriptionStatus",
+		Values: graphql.EnumValueConfigMap{
+			"SCHEDULED": {Value: model.StatusScheduled},
+			"TRIAL":     {Value: model.StatusTrial},
+			"ACTIVE":    {Value: model.StatusActive},
+			"EXPIRED":   {Value: model.StatusExpired},
+			"CANCELLED": {Value: model.StatusCancelled},
+		},
+	})
+	deletionReason := graphql.NewEnum(graphql.EnumConfig{
+		Name: "DeletionReason",
+		Values: graphql.EnumValueConfigMap{
+			"MISTAKE": {Value: model.DeletionMistake},
+			"ENDED":   {Value: model.DeletionEnded},
+		},
+	})
+	splitType := graphql.NewEnum(graphql.EnumConfig{
+		Name: "SplitType",
+		Values: graphql.EnumValueConfigMap{
+			"EQUAL":      {Value: model.SplitEqual},
+			"PERCENTAGE": {Value: model.SplitPercentage},
+			"FIXED":      {Value: model.SplitFixed},
+		},
+	})
+
+	costBreakdown := graphql.NewObject(graphql.ObjectConfig{
+		Name:        "CostBreakdown",
+		Description: "Gross = beforeDiscount - discount = net + tax.",
+		Fields: graphql.Fields{
+			"beforeDiscount": costField(func(r dto.TotalCostResponse) model.Money { return r.BeforeDiscount }),
+			"discount":       costField(func(r dto.TotalCostResponse) model.Money { return r.Discount }),
+			"net":            costField(func(r dto.TotalCostResponse) model.Money { return r.Net }),
+			"tax":            costField(func(r dto.TotalCostResponse) model.Money { return r.Tax }),
+			"gross":          costField(func(r dto.TotalCostResponse) model.Money { return r.Gross }),
+			"currency": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(dto.TotalCostResponse).Currency, nil
+				},
+			},
+		},
+	})
+	serviceCostType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "ServiceCost",
+		Fields: graphql.Fields{
+			"serviceName": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.String),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return p.Source.(serviceCost).ServiceName, nil
+				},
+			},
+			"cost": &graphql.Field{
+				Type: graphql.NewNonNull(costBreakdown),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					return mapper.ToTotalCostResponse(p.Source.(serviceCost).Cost), nil
+				},
+			},
+		},
+	})
+
+	var subscription, user *graphql.Object
+
+	member := graphql.NewObject(graphql.ObjectConfig{
+		Name: "SubscriptionMember",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"user": &graphql.Field{
+					Type: graphql.NewNonNull(user),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(dto.SubscriptionMemberResponse).UserID, nil
+					},
+				},
+				"splitType": &graphql.Field{
+					Type: graphql.NewNonNull(splitType),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return model.SplitType(p.Source.(dto.SubscriptionMemberResponse).SplitType), nil
+					},
+				},
+				"value": &graphql.Field{
+					Type:        graphql.String,
+					Description: "Percentage for PERCENTAGE, amount for FIXED, empty for EQUAL.",
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(dto.SubscriptionMemberResponse).Value, nil
+					},
+				},
+			}
+		}),
+	})
+	priceChange := graphql.NewObject(graphql.ObjectConfig{
+		Name: "PriceChange",
+		Fields: graphql.Fields{
+			"id":            priceChangeField(graphql.ID, func(r dto.PriceChangeResponse) any { return r.ID }),
+			"price":         priceChangeField(graphql.String, func(r dto.PriceChangeResponse) any { return r.Price.String() }),
+			"currency":      priceChangeField(graphql.String, func(r dto.PriceChangeResponse) any { return r.Currency }),
+			"effectiveFrom": priceChangeField(graphql.String, func(r dto.PriceChangeResponse) any { return r.EffectiveFrom }),
+		},
+	})
+
+	subscription = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Subscription",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          subscriptionField(graphql.ID, func(s *model.Subscription) any { return s.ID.String() }),
+				"serviceName": subscriptionField(graphql.String, func(s *model.Subscription) any { return s.ServiceName }),
+				"price":       subscriptionField(graphql.String, func(s *model.Subscription) any { return s.Price.String() }),
+				"currency":    subscriptionField(graphql.String, func(s *model.Subscription) any { return s.Price.Currency }),
+				"startDate":   subscriptionField(graphql.String, func(s *model.Subscription) any { return s.StartDate.Format(monthLayout) }),
+				"endDate": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return optionalMonth(p.Source.(*model.Subscription).EndDate), nil
+					},
+				},
+				"billingPeriod": subscriptionField(billingPeriod, func(s *model.Subscription) any { return s.BillingPeriod }),
+				"trialEndDate": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return optionalMonth(p.Source.(*model.Subscription).TrialEndDate), nil
+					},
+				},
+				"taxRate":          subscriptionField(graphql.Float, func(s *model.Subscription) any { return float64(s.TaxRate) / 100 }),
+				"priceIncludesTax": subscriptionField(graphql.Boolean, func(s *model.Subscription) any { return s.PriceIncludesTax }),
+				"status":           subscriptionField(status, func(s *model.Subscription) any { return s.Status }),
+				"cancelledAt": &graphql.Field{
+					Type: graphql.DateTime,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(*model.Subscription).CancelledAt, nil
+					},
+				},
+				"endedAt": &graphql.Field{
+					Type: graphql.DateTime,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(*model.Subscription).EndedAt, nil
+					},
+				},
+				"deleted": subscriptionField(graphql.Boolean, func(s *model.Subscription) any { return s.IsDeleted }),
+				"deletionReason": &graphql.Field{
+					Type: deletionReason,
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						if reason := p.Source.(*model.Subscription).DeletionReason; reason != "" {
+							return reason, nil
+						}
+						return nil, nil
+					},
+				},
+				"user": &graphql.Field{
+					Type:        graphql.NewNonNull(user),
+					Description: "Owner paying for the subscription.",
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(*model.Subscription).UserID.String(), nil
+					},
+				},
+				"members": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(member))),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						sub := p.Source.(*model.Subscription)
+						thunk := loadersFrom(p.Context).membersBySubscription.load(p.Context, sub.ID)
+						return then(thunk, "failed to load members", func(members []*model.SubscriptionMember) any {
+							resp := make([]dto.SubscriptionMemberResponse, 0, len(members))
+							for _, m := range members {
+								resp = append(resp, mapper.ToSubscriptionMemberResponse(*m, sub.Price.Currency))
+							}
+							return resp
+						}), nil
+					},
+				},
+				"priceChanges": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(priceChange))),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						sub := p.Source.(*model.Subscription)
+						thunk := loadersFrom(p.Context).priceChangesBySubscription.load(p.Context, sub.ID)
+						return then(thunk, "failed to load price changes", func(changes []*model.PriceChange) any {
+							resp := make([]dto.PriceChangeResponse, 0, len(changes))
+							for _, c := range changes {
+								resp = append(resp, mapper.ToPriceChangeResponse(*c))
+							}
+							return resp
+						}), nil
+					},
+				},
+			}
+		}),
+	})
+
+	user = graphql.NewObject(graphql.ObjectConfig{
+		Name: "User",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id": &graphql.Field{
+					Type: graphql.NewNonNull(graphql.ID),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						return p.Source.(string), nil
+					},
+				},
+				"subscriptions": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(subscription))),
+					Description: "Subscriptions owned by the user, deleted ones are skipped.",
+					Args:        pageArgs(),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						limit, offset, err := paging(p.Args)
+						if err != nil {
+							return nil, err
+						}
+						id := uuid.MustParse(p.Source.(string))
+						thunk := loadersFrom(p.Context).subscriptionsByUser(limit, offset).load(p.Context, id)
+						return then(thunk, "failed to load subscriptions", func(subs []*model.Subscription) any {
+							return subs
+						}), nil
+					},
+				},
+				"cost": &graphql.Field{
+					Type:        graphql.NewNonNull(costBreakdown),
+					Description: "What the user pays for own and shared subscriptions.",
+					Args:        costArgs(false),
+					Resolve: func(p graphql.ResolveParams) (any, error) {
+						from, to, err := period(p.Args)
+						if err != nil {
+							return nil, err
+						}
+						currency, err := currencyFilter(p.Args)
+						if err != nil {
+							return nil, err
+						}
+						id := uuid.MustParse(p.Source.(string))
+						ld := loadersFrom(p.Context).costByUser(optionalString(p.Args, "serviceName"), currency, from, to, p.Args["includeDeleted"].(bool))
+						return then(ld.load(p.Context, id), "failed to calculate total cost", func(total model.CostBreakdown) any {
+							return mapper.ToTotalCostResponse(total)
+						}), nil
+					},
+				},
+			}
+		}),
+	})
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"subscriptions": &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(subscription))),
+				Description: "Subscriptions running during [from, to], selected like for cost calculations.",
+				Args: mergeArgs(costArgs(true), pageArgs(), graphql.FieldConfigArgument{
+					"includeDeleted": {Type: graphql.Boolean, DefaultValue: false},
+				}),
+				Resolve: b.resolveSubscriptions,
+			},
+			"subscription": &graphql.Field{
+				Type: subscription,
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: b.resolveSubscription,
+			},
+			"user": &graphql.Field{
+				Type: graphql.NewNonNull(user),
+				Args: graphql.FieldConfigArgument{
+					"id": {Type: graphql.NewNonNull(graphql.ID)},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					id, err := parseUserID(p.Args["id"])
+					if err != nil {
+						return nil, err
+					}
+					return id.String(), nil
+				},
+			},
+			"users": &graphql.Field{
+				Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(user))),
+				Args: graphql.FieldConfigArgument{
+					"ids": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.ID)))},
+				},
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					values, _ := p.Args["ids"].([]any)
+					if len(values) > maxUserIDs {
+						return nil, errors.New("too many ids, at most 100 are allowed")
+					}
+					ids := make([]string, 0, len(values))
+					for _, v := range values {
+						id, err := parseUserID(v)
+						if err != nil {
+							return nil, err
+						}
+						ids = append(ids, id.String())
+					}
+					return ids, nil
+				},
+			},
+			"cost": &graphql.Field{
+				Type: graphql.NewNonNull(costBreakdown),
+				Args: costArgs(true),
+				Resolve: func(p graphql.ResolveParams) (any, error) {
+					userID, err := optionalUserID(p.Args)
+					if err != nil {
+						return nil, err
+					}
+					return b.resolveCost(p, userID)
+				},
+			},
+			"costByService": &graphql.Field{
+				Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(serviceCostType))),
+				Args:    costArgs(true),
+				Resolve: b.resolveCostByService,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+func (b *schemaBuilder) resolveSubscriptions(p graphql.ResolveParams) (any, error) {
+	userID, err := optionalUserID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := period(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := currencyFilter(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	limit, offset, err := paging(p.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	subs, err := b.service.FilterSubscriptions(p.Context, userID, optionalString(p.Args, "serviceName"), currency, from, to, p.Args["includeDeleted"].(bool), limit, offset)
+	if err != nil {
+		return nil, resolveError(err, "failed to list subscriptions")
+	}
+	return subs, nil
+}
+
+func (b *schemaBuilder) resolveSubscription(p graphql.ResolveParams) (any, error) {
+	id, err := uuid.Parse(p.Args["id"].(string))
+	if err != nil {
+		return nil, errors.New("invalid subscription id")
+	}
+	sub, err := b.service.GetSubscription(p.Context, id)
+	if err != nil {
+		return nil, resolveError(err, "failed to get subscription")
+	}
+	if sub == nil {
+		return nil, nil
+	}
+	return sub, nil
+}
+
+func (b *schemaBuilder) resolveCost(p graphql.ResolveParams, userID *uuid.UUID) (any, error) {
+	from, to, err := period(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := currencyFilter(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	total, err := b.service.CalculateTotalCost(p.Context, userID, optionalString(p.Args, "serviceName"), currency, from, to, p.Args["includeDeleted"].(bool))
+	if err != nil {
+		return nil, resolveError(err, "failed to calculate total cost")
+	}
+	return mapper.ToTotalCostResponse(total), nil
+}
+
+func (b *schemaBuilder) resolveCostByService(p graphql.ResolveParams) (any, error) {
+	userID, err := optionalUserID(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	from, to, err := period(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	currency, err := currencyFilter(p.Args)
+	if err != nil {
+		return nil, err
+	}
+	report, err := b.service.CostReport(p.Context, userID, optionalString(p.Args, "serviceName"), currency, from, to, p.Args["includeDeleted"].(bool))
+	if err != nil {
+		return nil, resolveError(err, "failed to calculate total cost")
+	}
+
+	var lines []serviceCost
+	index := make(map[string]int)
+	for _, line := range report {
+		name := line.Subscription.ServiceName
+		i, ok := index[name]
+		if !ok {
+			index[name] = len(lines)
+			lines = append(lines, serviceCost{ServiceName: name, Cost: line.Cost})
+			continue
+		}
+		if lines[i].Cost, err = lines[i].Cost.Add(line.Cost); err != nil {
+			return nil, resolveError(err, "failed to calculate total cost")
+		}
+	}
+	return lines, nil
+}
+
+// costArgs mirrors the filter of cost calculations, withUser adds the userId argument for top-level fields.
+func costArgs(withUser bool) graphql.FieldConfigArgument {
+	args := graphql.FieldConfigArgument{
+		"serviceName":    {Type: graphql.String},
+		"currency":       {Type: graphql.String, Description: "ISO 4217 code, required when subscriptions are billed in several currencies."},
+		"from":           {Type: graphql.NewNonNull(graphql.String), Description: "MM-YYYY"},
+		"to":             {Type: graphql.NewNonNull(graphql.String), Description: "MM-YYYY"},
+		"includeDeleted": {Type: graphql.Boolean, DefaultValue: true, Description: "Count months before deletion of subscriptions deleted as ENDED."},
+	}
+	if withUser {
+		args["userId"] = &graphql.ArgumentConfig{Type: graphql.ID, Description: "Owner or member of subscriptions."}
+	}
+	return args
+}
+
+func mergeArgs(args graphql.FieldConfigArgument, overrides ...graphql.FieldConfigArgument) graphql.FieldConfigArgument {
+	for _, o := range overrides {
+		for name, arg := range o {
+			args[name] = arg
+		}
+	}
+	return args
+}
+
+// pageArgs are paged in storage, complexity counts the list at its limit.
+func pageArgs() graphql.FieldConfigArgument {
+	return graphql.FieldConfigArgument{
+		"limit":  {Type: graphql.Int, DefaultValue: defaultLimit},
+		"offset": {Type: graphql.Int, DefaultValue: 0},
+	}
+}
+
+func subscriptionField(typ graphql.Output, get func(*model.Subscription) any) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(typ),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(*model.Subscription)), nil
+		},
+	}
+}
+
+func priceChangeField(typ graphql.Output, get func(dto.PriceChangeResponse) any) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(typ),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(dto.PriceChangeResponse)), nil
+		},
+	}
+}
+
+func costField(get func(dto.TotalCostResponse) model.Money) *graphql.Field {
+	return &graphql.Field{
+		Type: graphql.NewNonNull(graphql.String),
+		Resolve: func(p graphql.ResolveParams) (any, error) {
+			return get(p.Source.(dto.TotalCostResponse)).String(), nil
+		},
+	}
+}
+
+// then converts the value of a loader thunk once it is resolved.
+func then[V any](thunk func() (any, error), message string, convert func(V) any) func() (any, error) {
+	return func() (any, error) {
+		v, err := thunk()
+		if err != nil {
+			return nil, resolveError(err, message)
+		}
+		return convert(v.(V)), nil
+	}
+}
+
+// resolveError keeps errors a client can act on and hides unexpected ones behind message.
+func resolveError(err error, message string) error {
+	switch {
+	case errors.Is(err, model.ErrSubscriptionNotFound),
+		errors.Is(err, model.ErrMoneyOverflow),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return err
+	case errors.Is(err, model.ErrCurrencyMismatch):
+		return errors.New("subscriptions are billed in different currencies, pick one with the currency argument: " + err.Error())
+	}
+
+	logger.Log.Errorf("graphql: %s: %v", message, err)
+	return errors.New(message)
+}
+
+func period(args map[string]any) (from, to time.Time, err error) {
+	if from, err = time.Parse(monthLayout, args["from"].(string)); err != nil {
+		return from, to, errors.New("invalid 'from' date format, use MM-YYYY")
+	}
+	if to, err = time.Parse(monthLayout, args["to"].(string)); err != nil {
+		return from, to, errors.New("invalid 'to' date format, use MM-YYYY")
+	}
+	return from, to, nil
+}
+
+func optionalUserID(args map[string]any) (*uuid.UUID, error) {
+	value, ok := args["userId"]
+	if !ok || value == nil {
+		return nil, nil
+	}
+	id, err := parseUserID(value)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+func parseUserID(value any) (uuid.UUID, error) {
+	s, _ := value.(string)
+	id, err := uuid.Parse(s)
+	if err != nil {
+		return uuid.Nil, errors.New("invalid user id")
+	}
+	return id, nil
+}
+
+func optionalString(args map[string]any, name string) *string {
+	value, _ := args[name].(string)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func paging(args map[string]any) (limit, offset int, err error) {
+	limit, offset = args["limit"].(int), args["offset"].(int)
+	if limit < 1 || limit > maxLimit {
+		return 0, 0, errors.New("'limit' must be between 1 and 500")
+	}
+	if offset < 0 {
+		return 0, 0, errors.New("'offset' must not be negative")
+	}
+	return limit, offset, nil
+}
+
+func currencyFilter(args map[string]any) (*string, error) {
+	value, _ := args["currency"].(string)
+	return mapper.ToCurrencyFilter(value)
+}
+
+func optionalMonth(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.Format(monthLayout)
+}
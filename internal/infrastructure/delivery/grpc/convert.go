@@ -0,0 +1,151 @@
+package grpc
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	subscriptionv1 "github.com/Babushkin05/subscription-organizer/pkg/api/subscription/v1"
+	"github.com/gin-gonic/gin/binding"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const monthLayout = "01-2006"
+
+var billingPeriods = map[subscriptionv1.BillingPeriod]model.BillingPeriod{
+	subscriptionv1.BillingPeriod_BILLING_PERIOD_MONTHLY:   model.BillingPeriodMonthly,
+	subscriptionv1.BillingPeriod_BILLING_PERIOD_QUARTERLY: model.BillingPeriodQuarterly,
+	subscriptionv1.BillingPeriod_BILLING_PERIOD_YEARLY:    model.BillingPeriodYearly,
+}
+
+var statuses = map[subscriptionv1.SubscriptionStatus]model.SubscriptionStatus{
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_SCHEDULED: model.StatusScheduled,
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_TRIAL:     model.StatusTrial,
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_ACTIVE:    model.StatusActive,
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_EXPIRED:   model.StatusExpired,
+	subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_CANCELLED: model.StatusCancelled,
+}
+
+var cancelModes = map[subscriptionv1.CancelMode]model.CancelMode{
+	subscriptionv1.CancelMode_CANCEL_MODE_UNSPECIFIED:   model.CancelAtPeriodEnd,
+	subscriptionv1.CancelMode_CANCEL_MODE_AT_PERIOD_END: model.CancelAtPeriodEnd,
+	subscriptionv1.CancelMode_CANCEL_MODE_IMMEDIATELY:   model.CancelImmediately,
+}
+
+var deletionReasons = map[subscriptionv1.DeletionReason]model.DeletionReason{
+	subscriptionv1.DeletionReason_DELETION_REASON_UNSPECIFIED: model.DeletionMistake,
+	subscriptionv1.DeletionReason_DELETION_REASON_MISTAKE:     model.DeletionMistake,
+	subscriptionv1.DeletionReason_DELETION_REASON_ENDED:       model.DeletionEnded,
+}
+
+// toSubscriptionModel validates the input with the same rules as the REST request body.
+func toSubscriptionModel(in *subscriptionv1.SubscriptionInput) (*model.Subscription, error) {
+	if in == nil {
+		return nil, invalidArgument("subscription is required")
+	}
+
+	req := dto.CreateSubscriptionRequest{
+		ServiceName:      in.GetServiceName(),
+		Price:            json.Number(in.GetPrice()),
+		Currency:         in.GetCurrency(),
+		UserID:           in.GetUserId(),
+		StartDate:        in.GetStartDate(),
+		EndDate:          in.GetEndDate(),
+		BillingPeriod:    string(billingPeriods[in.GetBillingPeriod()]),
+		TrialEndDate:     in.GetTrialEndDate(),
+		TaxRate:          in.GetTaxRate(),
+		PriceIncludesTax: in.PriceIncludesTax,
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, invalidArgument(err.Error())
+	}
+
+	sub, err := mapper.ToSubscriptionModel(req)
+	if err != nil {
+		return nil, invalidArgument(err.Error())
+	}
+	return sub, nil
+}
+
+func toSubscriptionProto(sub *model.Subscription) *subscriptionv1.Subscription {
+	resp := &subscriptionv1.Subscription{
+		Id:               sub.ID.String(),
+		ServiceName:      sub.ServiceName,
+		Price:            sub.Price.String(),
+		Currency:         sub.Price.Currency,
+		UserId:           sub.UserID.String(),
+		StartDate:        sub.StartDate.Format(monthLayout),
+		EndDate:          optionalMonth(sub.EndDate),
+		TrialEndDate:     optionalMonth(sub.TrialEndDate),
+		TaxRate:          float64(sub.TaxRate) / 100,
+		PriceIncludesTax: sub.PriceIncludesTax,
+		CancelledAt:      optionalTimestamp(sub.CancelledAt),
+		EndedAt:          optionalTimestamp(sub.EndedAt),
+		Deleted:          sub.IsDeleted,
+	}
+	for k, v := range billingPeriods {
+		if v == sub.BillingPeriod {
+			resp.BillingPeriod = k
+		}
+	}
+	for k, v := range statuses {
+		if v == sub.Status {
+			resp.Status = k
+		}
+	}
+	switch sub.DeletionReason {
+	case model.DeletionMistake:
+		resp.DeletionReason = subscriptionv1.DeletionReason_DELETION_REASON_MISTAKE
+	case model.DeletionEnded:
+		resp.DeletionReason = subscriptionv1.DeletionReason_DELETION_REASON_ENDED
+	}
+	return resp
+}
+
+func toCostBreakdownProto(total model.CostBreakdown) *subscriptionv1.CostBreakdown {
+	resp := mapper.ToTotalCostResponse(total)
+	return &subscriptionv1.CostBreakdown{
+		BeforeDiscount: resp.BeforeDiscount.String(),
+		Discount:       resp.Discount.String(),
+		Net:            resp.Net.String(),
+		Tax:            resp.Tax.String(),
+		Gross:          resp.Gross.String(),
+		Currency:       resp.Currency,
+	}
+}
+
+func toForecastProto(forecast []model.MonthlyCost) *subscriptionv1.ForecastCostResponse {
+	resp := mapper.ToForecastResponse(forecast)
+	out := &subscriptionv1.ForecastCostResponse{
+		From:     resp.From,
+		To:       resp.To,
+		Total:    resp.Total.String(),
+		Currency: resp.Currency,
+		Months:   make([]*subscriptionv1.MonthlyCost, 0, len(resp.Months)),
+	}
+	for _, m := range resp.Months {
+		out.Months = append(out.Months, &subscriptionv1.MonthlyCost{
+			Month:     m.Month,
+			Committed: m.Committed.String(),
+			Projected: m.Projected.String(),
+			Total:     m.Total.String(),
+		})
+	}
+	return out
+}
+
+func optionalMonth(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(monthLayout)
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
@@ -0,0 +1,44 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// toStatus maps domain errors to gRPC status codes the same way the REST API maps them to HTTP statuses.
+// Unexpected errors are logged and hidden behind message.
+func toStatus(err error, method, message string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, model.ErrSubscriptionNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, model.ErrSubscriptionOverlap):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, model.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, model.ErrCurrencyMismatch):
+		return status.Error(codes.FailedPrecondition, "subscriptions are billed in different currencies, pick one with the currency field: "+err.Error())
+	case errors.Is(err, model.ErrMoneyOverflow):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	}
+
+	logger.Log.Errorf("grpc %s: %v", method, err)
+	return status.Error(codes.Internal, message)
+}
+
+func invalidArgument(message string) error {
+	return status.Error(codes.InvalidArgument, message)
+}
@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	subscriptionv1 "github.com/Babushkin05/subscription-organizer/pkg/api/subscription/v1"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	actorKey       = "x-actor"
+	maxActorLength = 255
+)
+
+// NewServer returns a gRPC server exposing the subscription service, with reflection
+// enabled so tools like grpcurl can discover it.
+func NewServer(service port.SubscriptionService) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(recoverInterceptor, logInterceptor, actorInterceptor))
+	subscriptionv1.RegisterSubscriptionServiceServer(server, NewSubscriptionServer(service))
+	reflection.Register(server)
+	return server
+}
+
+// actorInterceptor takes the author of changes for the audit log from the x-actor metadata,
+// like X-Actor in the REST API.
+func actorInterceptor(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(actorKey)
+	if len(values) == 0 {
+		return handler(ctx, req)
+	}
+
+	actor := strings.TrimSpace(values[0])
+	if actor == "" {
+		return handler(ctx, req)
+	}
+	if len(actor) > maxActorLength {
+		return nil, invalidArgument("x-actor is too long")
+	}
+	return handler(port.WithActor(ctx, actor), req)
+}
+
+func logInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	start := time.Now()
+	resp, err := handler(ctx, req)
+	logger.Log.Infof("grpc %s: %s in %s", info.FullMethod, status.Code(err), time.Since(start))
+	return resp, err
+}
+
+func recoverInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log.Errorf("grpc %s: panic: %v", info.FullMethod, r)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
@@ -0,0 +1,300 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	subscriptionv1 "github.com/Babushkin05/subscription-organizer/pkg/api/subscription/v1"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	defaultForecastMonths = 12
+	maxForecastMonths     = 60
+	defaultPageSize       = 100
+	maxPageSize           = 1000
+)
+
+type SubscriptionServer struct {
+	subscriptionv1.UnimplementedSubscriptionServiceServer
+	service port.SubscriptionService
+}
+
+func NewSubscriptionServer(service port.SubscriptionService) *SubscriptionServer {
+	return &SubscriptionServer{service: service}
+}
+
+func (s *SubscriptionServer) CreateSubscription(ctx context.Context, req *subscriptionv1.CreateSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	sub, err := toSubscriptionModel(req.GetSubscription())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.service.CreateSubscription(ctx, sub, req.GetStrict()); err != nil {
+		return nil, toStatus(err, "CreateSubscription", "failed to create subscription")
+	}
+
+	logger.Log.Infof("grpc CreateSubscription: subscription created with ID %s", sub.ID)
+	return toSubscriptionProto(sub), nil
+}
+
+func (s *SubscriptionServer) GetSubscription(ctx context.Context, req *subscriptionv1.GetSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.service.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, toStatus(err, "GetSubscription", "failed to get subscription")
+	}
+	if sub == nil {
+		return nil, toStatus(model.ErrSubscriptionNotFound, "GetSubscription", "")
+	}
+	return toSubscriptionProto(sub), nil
+}
+
+func (s *SubscriptionServer) ListSubscriptions(ctx context.Context, req *subscriptionv1.ListSubscriptionsRequest) (*subscriptionv1.ListSubscriptionsResponse, error) {
+	userID, err := parseOptionalUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	var status *model.SubscriptionStatus
+	if req.GetStatus() != subscriptionv1.SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED {
+		st, ok := statuses[req.GetStatus()]
+		if !ok {
+			return nil, invalidArgument("invalid status")
+		}
+		status = &st
+	}
+
+	pageSize := int(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize {
+		return nil, invalidArgument("page_size must be between 1 and " + strconv.Itoa(maxPageSize))
+	}
+	offset, err := parsePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	// Лишняя строка показывает, есть ли следующая страница
+	subs, err := s.service.ListSubscriptionsPage(ctx, userID, optionalString(req.GetServiceName()), status, pageSize+1, offset)
+	if err != nil {
+		return nil, toStatus(err, "ListSubscriptions", "failed to list subscriptions")
+	}
+
+	resp := &subscriptionv1.ListSubscriptionsResponse{}
+	if len(subs) > pageSize {
+		subs = subs[:pageSize]
+		resp.NextPageToken = pageToken(offset + pageSize)
+	}
+	for _, sub := range subs {
+		resp.Subscriptions = append(resp.Subscriptions, toSubscriptionProto(sub))
+	}
+
+	logger.Log.Infof("grpc ListSubscriptions: returned %d subscriptions", len(resp.Subscriptions))
+	return resp, nil
+}
+
+func (s *SubscriptionServer) UpdateSubscription(ctx context.Context, req *subscriptionv1.UpdateSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	sub, err := toSubscriptionModel(req.GetSubscription())
+	if err != nil {
+		return nil, err
+	}
+	sub.ID = id
+
+	if err := s.service.UpdateSubscription(ctx, sub, req.GetStrict()); err != nil {
+		return nil, toStatus(err, "UpdateSubscription", "failed to update subscription")
+	}
+
+	logger.Log.Infof("grpc UpdateSubscription: updated subscription %s", sub.ID)
+	return toSubscriptionProto(sub), nil
+}
+
+func (s *SubscriptionServer) DeleteSubscription(ctx context.Context, req *subscriptionv1.DeleteSubscriptionRequest) (*emptypb.Empty, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	reason, ok := deletionReasons[req.GetReason()]
+	if !ok {
+		return nil, invalidArgument("invalid reason")
+	}
+
+	if err := s.service.DeleteSubscription(ctx, id, reason); err != nil {
+		return nil, toStatus(err, "DeleteSubscription", "failed to delete subscription")
+	}
+
+	logger.Log.Infof("grpc DeleteSubscription: deleted subscription %s as %s", id, reason)
+	return &emptypb.Empty{}, nil
+}
+
+func (s *SubscriptionServer) RestoreSubscription(ctx context.Context, req *subscriptionv1.RestoreSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	return s.change(ctx, "RestoreSubscription", req.GetId(), s.service.RestoreSubscription)
+}
+
+func (s *SubscriptionServer) CancelSubscription(ctx context.Context, req *subscriptionv1.CancelSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	mode, ok := cancelModes[req.GetMode()]
+	if !ok {
+		return nil, invalidArgument("invalid mode")
+	}
+	return s.change(ctx, "CancelSubscription", req.GetId(), func(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+		return s.service.CancelSubscription(ctx, id, mode)
+	})
+}
+
+func (s *SubscriptionServer) ReactivateSubscription(ctx context.Context, req *subscriptionv1.ReactivateSubscriptionRequest) (*subscriptionv1.Subscription, error) {
+	return s.change(ctx, "ReactivateSubscription", req.GetId(), s.service.ReactivateSubscription)
+}
+
+func (s *SubscriptionServer) change(ctx context.Context, method, idStr string, fn func(ctx context.Context, id uuid.UUID) (*model.Subscription, error)) (*subscriptionv1.Subscription, error) {
+	id, err := parseID(idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := fn(ctx, id)
+	if err != nil {
+		return nil, toStatus(err, method, "failed to change subscription")
+	}
+
+	logger.Log.Infof("grpc %s: subscription %s is %s", method, id, sub.Status)
+	return toSubscriptionProto(sub), nil
+}
+
+func (s *SubscriptionServer) CalculateTotalCost(ctx context.Context, req *subscriptionv1.CalculateTotalCostRequest) (*subscriptionv1.CostBreakdown, error) {
+	userID, err := parseOptionalUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetFrom() == "" || req.GetTo() == "" {
+		return nil, invalidArgument("'from' and 'to' are required, format MM-YYYY")
+	}
+	from, err := parseMonth("from", req.GetFrom())
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseMonth("to", req.GetTo())
+	if err != nil {
+		return nil, err
+	}
+	includeDeleted := req.IncludeDeleted == nil || *req.IncludeDeleted
+	currency, err := parseCurrency(req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := s.service.CalculateTotalCost(ctx, userID, optionalString(req.GetServiceName()), currency, from, to, includeDeleted)
+	if err != nil {
+		return nil, toStatus(err, "CalculateTotalCost", "failed to calculate total cost")
+	}
+
+	logger.Log.Infof("grpc CalculateTotalCost: total cost = %s", total.Gross)
+	return toCostBreakdownProto(total), nil
+}
+
+func (s *SubscriptionServer) ForecastCost(ctx context.Context, req *subscriptionv1.ForecastCostRequest) (*subscriptionv1.ForecastCostResponse, error) {
+	userID, err := parseOptionalUserID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	from := time.Now()
+	if req.GetFrom() != "" {
+		if from, err = parseMonth("from", req.GetFrom()); err != nil {
+			return nil, err
+		}
+	}
+	months := int(req.GetMonths())
+	if months == 0 {
+		months = defaultForecastMonths
+	}
+	if months < 1 || months > maxForecastMonths {
+		return nil, invalidArgument("'months' must be between 1 and 60")
+	}
+	currency, err := parseCurrency(req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	forecast, err := s.service.ForecastCost(ctx, userID, optionalString(req.GetServiceName()), currency, from, months)
+	if err != nil {
+		return nil, toStatus(err, "ForecastCost", "failed to forecast cost")
+	}
+	return toForecastProto(forecast), nil
+}
+
+func parseID(value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, invalidArgument("invalid subscription id")
+	}
+	return id, nil
+}
+
+func parseOptionalUserID(value string) (*uuid.UUID, error) {
+	if value == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return nil, invalidArgument("invalid user_id")
+	}
+	return &id, nil
+}
+
+func parseMonth(name, value string) (time.Time, error) {
+	t, err := time.Parse(monthLayout, value)
+	if err != nil {
+		return time.Time{}, invalidArgument("invalid '" + name + "' date format, use MM-YYYY")
+	}
+	return t, nil
+}
+
+func parseCurrency(value string) (*string, error) {
+	currency, err := mapper.ToCurrencyFilter(value)
+	if err != nil {
+		return nil, invalidArgument(err.Error())
+	}
+	return currency, nil
+}
+
+// Токен страницы непрозрачен для клиентов, внутри — смещение
+func pageToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+func parsePageToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, invalidArgument("invalid page_token")
+	}
+	offset, err := strconv.Atoi(string(raw))
+	if err != nil || offset < 0 {
+		return 0, invalidArgument("invalid page_token")
+	}
+	return offset, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
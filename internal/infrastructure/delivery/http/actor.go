@@ -0,0 +1,32 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	actorHeader    = "X-Actor"
+	maxActorLength = 255
+)
+
+// ActorMiddleware takes the author of changes for the audit log from the X-Actor header.
+func ActorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := strings.TrimSpace(c.GetHeader(actorHeader))
+		if actor == "" {
+			c.Next()
+			return
+		}
+		if len(actor) > maxActorLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "X-Actor is too long"})
+			return
+		}
+		c.Request = c.Request.WithContext(port.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
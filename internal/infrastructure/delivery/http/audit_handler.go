@@ -0,0 +1,127 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AuditHandler struct {
+	service port.AuditService
+}
+
+func NewAuditHandler(service port.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+// GetSubscriptionHistory godoc
+// @Summary Get subscription history
+// @Description Returns every recorded change of the subscription with snapshots before and after it, oldest first. Price changes, members and discounts are recorded with the changed records
+// @Tags audit
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} dto.AuditEntryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/history [get]
+func (h *AuditHandler) GetSubscriptionHistory(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("GetSubscriptionHistory: subscription %s", idStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	entries, err := h.service.History(c.Request.Context(), id)
+	if errors.Is(err, model.ErrSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get subscription history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuditEntryResponses(entries))
+}
+
+// QueryAuditLog godoc
+// @Summary Query audit log
+// @Description Returns changes of all subscriptions, newest first. The time range is half-open: from is inclusive, to is exclusive.
+// @Tags admin
+// @Produce json
+// @Param actor query string false "Author of the changes, as sent in X-Actor"
+// @Param subscription_id query string false "Subscription ID"
+// @Param from query string false "Start of the range, RFC 3339"
+// @Param to query string false "End of the range, RFC 3339"
+// @Param limit query int false "Page size (1-1000)" default(100)
+// @Param offset query int false "Number of entries to skip" default(0)
+// @Success 200 {array} dto.AuditEntryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/audit [get]
+func (h *AuditHandler) QueryAuditLog(c *gin.Context) {
+	var filter model.AuditFilter
+
+	if actor := c.Query("actor"); actor != "" {
+		filter.Actor = &actor
+	}
+	if idStr := c.Query("subscription_id"); idStr != "" {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription_id"})
+			return
+		}
+		filter.SubscriptionID = &id
+	}
+
+	var err error
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "'from' must be before 'to'"})
+		return
+	}
+	if filter.Limit, err = queryInt(c, "limit", 100, 1, 1000); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if filter.Offset, err = queryInt(c, "offset", 0, 0, 1<<30); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	logger.Log.Infof("QueryAuditLog: actor=%s, subscription_id=%s, from=%s, to=%s",
+		c.Query("actor"), c.Query("subscription_id"), c.Query("from"), c.Query("to"))
+
+	entries, err := h.service.Query(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to query audit log"})
+		return
+	}
+
+	c.JSON(http.StatusOK, toAuditEntryResponses(entries))
+}
+
+func toAuditEntryResponses(entries []*model.AuditEntry) []dto.AuditEntryResponse {
+	resp := make([]dto.AuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		resp = append(resp, mapper.ToAuditEntryResponse(*entry))
+	}
+	return resp
+}
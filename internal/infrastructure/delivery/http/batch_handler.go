@@ -0,0 +1,238 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+)
+
+const (
+	batchStatusFailed     = "failed"
+	batchStatusSkipped    = "skipped"
+	batchStatusRolledBack = "rolled_back"
+)
+
+var batchStatuses = map[model.BatchAction]string{
+	model.BatchCreate: "created",
+	model.BatchUpdate: "updated",
+	model.BatchDelete: "deleted",
+}
+
+// BatchCreateSubscriptions godoc
+// @Summary Create subscriptions in bulk
+// @Description Creates up to 1000 subscriptions in a single transaction. In atomic mode (default) nothing is created if any item fails, in best_effort mode valid items are created and failed ones are reported.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param batch body dto.BatchCreateRequest true "Subscriptions to create"
+// @Param Idempotency-Key header string false "Key to safely retry the request, the first response is replayed"
+// @Success 200 {object} dto.BatchResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.BatchResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/batch [post]
+func (h *SubscriptionHandler) BatchCreateSubscriptions(c *gin.Context) {
+	logger.Log.Info("BatchCreateSubscriptions: received request")
+
+	var req dto.BatchCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	batch := newBatch(req.Mode, len(req.Items))
+	for i := range req.Items {
+		sub, err := batchSubscription(&req.Items[i])
+		if err != nil {
+			batch.fail(i, "", err)
+			continue
+		}
+		batch.add(i, model.BatchOperation{Action: model.BatchCreate, Subscription: sub}, sub.ID)
+	}
+
+	h.execBatch(c, "BatchCreateSubscriptions", batch)
+}
+
+// BatchUpdateSubscriptions godoc
+// @Summary Update subscriptions in bulk
+// @Description Replaces up to 1000 subscriptions by ID in a single transaction, with atomic (default) or best_effort mode.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param batch body dto.BatchUpdateRequest true "Subscriptions to update"
+// @Success 200 {object} dto.BatchResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.BatchResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/batch [put]
+func (h *SubscriptionHandler) BatchUpdateSubscriptions(c *gin.Context) {
+	logger.Log.Info("BatchUpdateSubscriptions: received request")
+
+	var req dto.BatchUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	batch := newBatch(req.Mode, len(req.Items))
+	for i := range req.Items {
+		item := &req.Items[i]
+		if err := binding.Validator.ValidateStruct(item); err != nil {
+			batch.fail(i, item.ID, err)
+			continue
+		}
+		sub, err := mapper.ToSubscriptionModel(item.CreateSubscriptionRequest)
+		if err != nil {
+			batch.fail(i, item.ID, err)
+			continue
+		}
+		sub.ID = uuid.MustParse(item.ID)
+		batch.add(i, model.BatchOperation{Action: model.BatchUpdate, Subscription: sub}, sub.ID)
+	}
+
+	h.execBatch(c, "BatchUpdateSubscriptions", batch)
+}
+
+// BatchDeleteSubscriptions godoc
+// @Summary Delete subscriptions in bulk
+// @Description Soft-deletes up to 1000 subscriptions in a single transaction, with atomic (default) or best_effort mode. The reason works as in DELETE /subscriptions/{id}.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param batch body dto.BatchDeleteRequest true "Subscription IDs"
+// @Success 200 {object} dto.BatchResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.BatchResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/batch [delete]
+func (h *SubscriptionHandler) BatchDeleteSubscriptions(c *gin.Context) {
+	logger.Log.Info("BatchDeleteSubscriptions: received request")
+
+	var req dto.BatchDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	reason, err := deletionReason(req.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	batch := newBatch(req.Mode, len(req.IDs))
+	for i, idStr := range req.IDs {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			batch.fail(i, idStr, errors.New("invalid subscription id"))
+			continue
+		}
+		batch.add(i, model.BatchOperation{Action: model.BatchDelete, ID: id, Reason: reason}, id)
+	}
+
+	h.execBatch(c, "BatchDeleteSubscriptions", batch)
+}
+
+// batchSubscription validates a batch item exactly like a CreateSubscription request body.
+func batchSubscription(req *dto.CreateSubscriptionRequest) (*model.Subscription, error) {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return nil, err
+	}
+	return mapper.ToSubscriptionModel(*req)
+}
+
+// batch collects operations of a batch request along with per-item results.
+type batch struct {
+	mode      model.BatchMode
+	ops       []model.BatchOperation
+	positions []int // индекс элемента запроса для каждой операции
+	results   []dto.BatchItemResult
+	invalid   bool
+}
+
+func newBatch(mode string, size int) *batch {
+	b := &batch{mode: model.BatchMode(mode), results: make([]dto.BatchItemResult, size)}
+	if b.mode == "" {
+		b.mode = model.BatchAtomic
+	}
+	for i := range b.results {
+		b.results[i].Index = i
+	}
+	return b
+}
+
+func (b *batch) add(i int, op model.BatchOperation, id uuid.UUID) {
+	b.ops = append(b.ops, op)
+	b.positions = append(b.positions, i)
+	b.results[i].ID = id.String()
+}
+
+func (b *batch) fail(i int, id string, err error) {
+	b.results[i] = dto.BatchItemResult{Index: i, ID: id, Status: batchStatusFailed, Error: err.Error()}
+	b.invalid = true
+}
+
+func (h *SubscriptionHandler) execBatch(c *gin.Context, name string, b *batch) {
+	var errs []error
+	// В атомарном режиме невалидный элемент отменяет весь пакет ещё до обращения к базе
+	if !(b.invalid && b.mode == model.BatchAtomic) {
+		var err error
+		errs, err = h.service.ExecBatch(c.Request.Context(), b.ops, b.mode)
+		if err != nil {
+			logger.Log.Errorf("%s: %v", name, err)
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to execute batch"})
+			return
+		}
+	}
+
+	failed := b.invalid
+	for i, op := range b.ops {
+		result := &b.results[b.positions[i]]
+		result.Status = batchStatuses[op.Action]
+		if i < len(errs) && errs[i] != nil {
+			failed = true
+			result.Status = batchStatusFailed
+			result.Error = batchItemError(op.Action, errs[i])
+		}
+	}
+
+	resp := dto.BatchResponse{Mode: string(b.mode), Items: b.results}
+	for i := range resp.Items {
+		item := &resp.Items[i]
+		if failed && b.mode == model.BatchAtomic && item.Status != batchStatusFailed {
+			item.Status = batchStatusRolledBack
+			if errs == nil {
+				item.Status = batchStatusSkipped
+			}
+		}
+		if item.Status == batchStatusFailed {
+			resp.Failed++
+		} else if item.Status != batchStatusRolledBack && item.Status != batchStatusSkipped {
+			resp.Succeeded++
+		}
+	}
+
+	logger.Log.Infof("%s: %s batch, %d succeeded, %d failed", name, b.mode, resp.Succeeded, resp.Failed)
+	if failed && b.mode == model.BatchAtomic {
+		c.JSON(http.StatusUnprocessableEntity, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// batchItemError hides storage details of failed operations from clients.
+func batchItemError(action model.BatchAction, err error) string {
+	if errors.Is(err, model.ErrSubscriptionNotFound) || errors.Is(err, model.ErrCurrencyMismatch) {
+		return err.Error()
+	}
+	logger.Log.Errorf("batch %s failed: %v", action, err)
+	return "failed to " + string(action) + " subscription"
+}
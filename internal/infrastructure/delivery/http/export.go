@@ -0,0 +1,238 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+const (
+	formatJSON = "json"
+	formatCSV  = "csv"
+	formatXLSX = "xlsx"
+
+	mimeCSV  = "text/csv"
+	mimeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// exportFormat picks the response format from the format query parameter,
+// falling back to the Accept header and then to JSON.
+func exportFormat(c *gin.Context) (string, error) {
+	switch format := strings.ToLower(c.Query("format")); format {
+	case formatJSON, formatCSV, formatXLSX:
+		return format, nil
+	case "":
+	default:
+		return "", errors.New("'format' must be one of json, csv, xlsx")
+	}
+
+	switch c.NegotiateFormat(gin.MIMEJSON, mimeCSV, mimeXLSX) {
+	case mimeCSV:
+		return formatCSV, nil
+	case mimeXLSX:
+		return formatXLSX, nil
+	}
+	return formatJSON, nil
+}
+
+// tableWriter writes rows of a spreadsheet straight into the response.
+// Cells may be string, float64, bool, model.Money, time.Time (a month) or nil.
+type tableWriter interface {
+	WriteRow(values []any) error
+	Close() error
+}
+
+// newTableWriter starts a CSV or XLSX attachment named name with the given header row.
+func newTableWriter(c *gin.Context, format, name string, columns []string) (tableWriter, error) {
+	var w tableWriter
+	switch format {
+	case formatCSV:
+		c.Header("Content-Type", mimeCSV+"; charset=utf-8")
+		w = &csvTableWriter{w: csv.NewWriter(c.Writer)}
+	case formatXLSX:
+		c.Header("Content-Type", mimeXLSX)
+		xw, err := newXLSXTableWriter(c, name)
+		if err != nil {
+			return nil, err
+		}
+		w = xw
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, format))
+
+	header := make([]any, len(columns))
+	for i, col := range columns {
+		header[i] = col
+	}
+	return w, w.WriteRow(header)
+}
+
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+func (t *csvTableWriter) WriteRow(values []any) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case model.Money:
+			record[i] = v.String()
+		case time.Time:
+			record[i] = v.Format(monthLayout)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			record[i] = strconv.FormatBool(v)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+	return t.w.Write(record)
+}
+
+func (t *csvTableWriter) Close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// xlsxTableWriter uses the excelize stream writer which keeps only a small
+// window of rows in memory and spills the rest to a temporary file.
+type xlsxTableWriter struct {
+	c         *gin.Context
+	file      *excelize.File
+	stream    *excelize.StreamWriter
+	row       int
+	dateStyle int
+	// amountStyles is keyed by the number of minor digits of the currency.
+	amountStyles map[int]int
+}
+
+func newXLSXTableWriter(c *gin.Context, sheet string) (*xlsxTableWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", sheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	dateFormat := "mm-yyyy"
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: &dateFormat})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &xlsxTableWriter{
+		c:            c,
+		file:         file,
+		stream:       stream,
+		dateStyle:    dateStyle,
+		amountStyles: make(map[int]int),
+	}, nil
+}
+
+func (t *xlsxTableWriter) WriteRow(values []any) error {
+	cells := make([]any, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case model.Money:
+			cell, err := t.amountCell(v)
+			if err != nil {
+				return err
+			}
+			cells[i] = cell
+		case time.Time:
+			cells[i] = excelize.Cell{StyleID: t.dateStyle, Value: v}
+		default:
+			cells[i] = v
+		}
+	}
+
+	t.row++
+	axis, err := excelize.CoordinatesToCellName(1, t.row)
+	if err != nil {
+		return err
+	}
+	return t.stream.SetRow(axis, cells)
+}
+
+// amountCell stores money as a number formatted with the currency's minor digits.
+func (t *xlsxTableWriter) amountCell(m model.Money) (excelize.Cell, error) {
+	text := m.String()
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return excelize.Cell{}, err
+	}
+
+	digits := 0
+	if dot := strings.IndexByte(text, '.'); dot >= 0 {
+		digits = len(text) - dot - 1
+	}
+	style, ok := t.amountStyles[digits]
+	if !ok {
+		format := "#,##0"
+		if digits > 0 {
+			format += "." + strings.Repeat("0", digits)
+		}
+		if style, err = t.file.NewStyle(&excelize.Style{CustomNumFmt: &format}); err != nil {
+			return excelize.Cell{}, err
+		}
+		t.amountStyles[digits] = style
+	}
+	return excelize.Cell{StyleID: style, Value: value}, nil
+}
+
+func (t *xlsxTableWriter) Close() error {
+	defer t.file.Close()
+	if err := t.stream.Flush(); err != nil {
+		return err
+	}
+	return t.file.Write(t.c.Writer)
+}
+
+// writeTable sends already computed rows as a CSV or XLSX attachment.
+func writeTable(c *gin.Context, format, name string, columns []string, rows [][]any) {
+	w, err := newTableWriter(c, format, name, columns)
+	if err != nil {
+		failExport(c, err)
+		return
+	}
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			failExport(c, err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		failExport(c, err)
+	}
+}
+
+// failExport reports a failed export: as a JSON error while nothing has been
+// sent yet, otherwise the response is cut short.
+func failExport(c *gin.Context, err error) {
+	logger.Log.Errorf("export failed: %v", err)
+	if c.Writer.Written() {
+		c.Abort()
+		return
+	}
+	c.Writer.Header().Del("Content-Disposition")
+	c.Writer.Header().Del("Content-Type")
+	c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to export data"})
+}
@@ -0,0 +1,111 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	maxIdempotencyKeyLen = 255
+	// maxIdempotentBody limits request bodies that are hashed in memory, imports are the largest.
+	maxIdempotentBody = maxImportSize
+)
+
+// recordingWriter keeps a copy of the response so it can be replayed.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes requests carrying an Idempotency-Key header safe to retry:
+// the first response is stored and replayed for retries with the same key and body
+// from the same X-Actor, so different clients may use the same keys;
+// reusing the key with a different body is rejected with 422. Server errors are not
+// stored so the request can be retried, and a request that never finished (e.g. the
+// server crashed) can be retried once its lease runs out.
+func IdempotencyMiddleware(service port.IdempotencyService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(idempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLen {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Idempotency-Key is too long"})
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxIdempotentBody+1))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{Error: "failed to read request body"})
+			return
+		}
+		if len(body) > maxIdempotentBody {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: "request body is too large"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.RequestURI() + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		rec, err := service.Begin(c.Request.Context(), key, requestHash)
+		switch {
+		case errors.Is(err, model.ErrIdempotencyKeyReused):
+			c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.ErrorResponse{Error: err.Error()})
+			return
+		case errors.Is(err, model.ErrIdempotencyKeyInProgress):
+			c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+			return
+		case err != nil:
+			logger.Log.Errorf("idempotency: failed to reserve key %q: %v", key, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to check idempotency key"})
+			return
+		case rec.StatusCode != 0:
+			logger.Log.Infof("idempotency: replaying response for key %q", key)
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(rec.StatusCode, rec.ContentType, rec.Response)
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// Ответ уже отправлен, сохраняем его даже если клиент успел отключиться
+		ctx := context.WithoutCancel(c.Request.Context())
+		if writer.Status() >= http.StatusInternalServerError {
+			err = service.Abort(ctx, rec)
+		} else {
+			err = service.Complete(ctx, rec, writer.Status(), writer.Header().Get("Content-Type"), writer.body.Bytes())
+		}
+		if err != nil {
+			logger.Log.Errorf("idempotency: failed to store result for key %q: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxImportSize limits the size of an uploaded file.
+const maxImportSize = 10 << 20
+
+// ImportSubscriptions godoc
+// @Summary Import subscriptions from CSV
+// @Description Validates every CSV row like CreateSubscription and inserts the valid ones in a single transaction.
+// @Description By default the header must use the request field names (service_name, price, user_id, start_date, ...);
+// @Description other headers can be mapped with mapping[<column>]=<field>.
+// @Tags subscriptions
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file false "CSV file (for multipart requests)"
+// @Param mapping query object false "Column to field mapping, e.g. mapping[Сервис]=service_name"
+// @Param delimiter query string false "Field delimiter, defaults to ','"
+// @Param dry_run query bool false "Only validate rows without storing them"
+// @Success 200 {object} dto.ImportReport
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/import [post]
+func (h *SubscriptionHandler) ImportSubscriptions(c *gin.Context) {
+	logger.Log.Info("ImportSubscriptions: received request")
+	dryRun, err := queryBool(c, "dry_run")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	delimiter, err := queryDelimiter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	body, ok := uploadedFile(c)
+	if !ok {
+		return
+	}
+	defer body.Close()
+
+	reader := csv.NewReader(body)
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "failed to read CSV header"})
+		return
+	}
+	columns, err := mapper.CSVColumns(header, c.QueryMap("mapping"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	report := dto.ImportReport{DryRun: dryRun, Rows: []dto.ImportRowResult{}}
+	var subs []*model.Subscription
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "failed to read CSV file"})
+				return
+			}
+			report.Total++
+			report.Rows = append(report.Rows, dto.ImportRowResult{Row: parseErr.StartLine, Error: parseErr.Err.Error()})
+			continue
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		row, _ := reader.FieldPos(0)
+
+		report.Total++
+		sub, err := importRow(columns, record)
+		if err != nil {
+			report.Rows = append(report.Rows, dto.ImportRowResult{Row: row, Error: err.Error()})
+			continue
+		}
+		subs = append(subs, sub)
+		report.Rows = append(report.Rows, dto.ImportRowResult{Row: row, ID: sub.ID.String()})
+	}
+	report.Valid = len(subs)
+
+	if err := h.service.ImportSubscriptions(c.Request.Context(), subs, dryRun, false); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to import subscriptions"})
+		return
+	}
+	if !dryRun {
+		report.Imported = len(subs)
+	}
+
+	logger.Log.Infof("ImportSubscriptions: %d of %d rows valid, %d imported", report.Valid, report.Total, report.Imported)
+	c.JSON(http.StatusOK, report)
+}
+
+// uploadedFile returns the uploaded file of a multipart request or the raw
+// request body, writing an error response and returning false on failure.
+func uploadedFile(c *gin.Context) (io.ReadCloser, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+	body, err := requestFile(c)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{Error: "file is too large"})
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return nil, false
+	}
+	return body, true
+}
+
+func requestFile(c *gin.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/") {
+		return c.Request.Body, nil
+	}
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, err
+		}
+		return nil, errors.New("missing 'file' form field")
+	}
+	return header.Open()
+}
+
+// importRow validates a CSV record exactly like a CreateSubscription request body.
+func importRow(columns, record []string) (*model.Subscription, error) {
+	req, err := mapper.ToCreateSubscriptionRequest(columns, record)
+	if err != nil {
+		return nil, err
+	}
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
+		return nil, err
+	}
+	return mapper.ToSubscriptionModel(req)
+}
+
+func isBlankRecord(record []string) bool {
+	for _, value := range record {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
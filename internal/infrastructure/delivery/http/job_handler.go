@@ -0,0 +1,110 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	scheduler port.JobScheduler
+}
+
+func NewJobHandler(scheduler port.JobScheduler) *JobHandler {
+	return &JobHandler{scheduler: scheduler}
+}
+
+// ListJobs godoc
+// @Summary List background jobs
+// @Description Returns registered jobs with their schedules, next run time and the last run on any replica
+// @Tags admin
+// @Produce json
+// @Success 200 {array} dto.JobResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	jobs, err := h.scheduler.ListJobs(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list jobs"})
+		return
+	}
+
+	resp := make([]dto.JobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		resp = append(resp, mapper.ToJobResponse(job))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// TriggerJob godoc
+// @Summary Run a job now
+// @Description Starts the job in the background outside of its schedule, the run is tracked in the job history
+// @Tags admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Success 202 {object} dto.JobRunResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/jobs/{name}/run [post]
+func (h *JobHandler) TriggerJob(c *gin.Context) {
+	name := c.Param("name")
+
+	run, err := h.scheduler.TriggerJob(c.Request.Context(), name)
+	switch {
+	case errors.Is(err, model.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	case errors.Is(err, model.ErrJobRunning):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to start job"})
+		return
+	}
+
+	logger.Log.Infof("TriggerJob: started %s, run %s", name, run.ID)
+	c.JSON(http.StatusAccepted, mapper.ToJobRunResponse(*run))
+}
+
+// ListJobRuns godoc
+// @Summary Job run history
+// @Description Returns the latest runs of the job, newest first
+// @Tags admin
+// @Produce json
+// @Param name path string true "Job name"
+// @Param limit query int false "Maximum number of runs (1-1000), defaults to 50"
+// @Success 200 {array} dto.JobRunResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /admin/jobs/{name}/runs [get]
+func (h *JobHandler) ListJobRuns(c *gin.Context) {
+	limit, err := queryInt(c, "limit", 50, 1, 1000)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	runs, err := h.scheduler.ListJobRuns(c.Request.Context(), c.Param("name"), limit)
+	if errors.Is(err, model.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list job runs"})
+		return
+	}
+
+	resp := make([]dto.JobRunResponse, 0, len(runs))
+	for _, run := range runs {
+		resp = append(resp, mapper.ToJobRunResponse(*run))
+	}
+	c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	service port.NotificationService
+}
+
+func NewNotificationHandler(service port.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get reminder preferences
+// @Description Returns when the user is reminded about renewals and trial ends, defaults when nothing was saved
+// @Tags notifications
+// @Produce json
+// @Param id path string true "User UUID"
+// @Success 200 {object} dto.NotificationPreferencesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/notifications [get]
+func (h *NotificationHandler) GetNotificationPreferences(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user id"})
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get notification preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.ToNotificationPreferencesResponse(*prefs))
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update reminder preferences
+// @Description Reminders are sent the given number of days before a charge or a trial end, each one only once
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param id path string true "User UUID"
+// @Param preferences body dto.NotificationPreferencesRequest true "Preferences"
+// @Success 200 {object} dto.NotificationPreferencesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/notifications [put]
+func (h *NotificationHandler) UpdateNotificationPreferences(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user id"})
+		return
+	}
+
+	var req dto.NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	prefs := mapper.ToNotificationPreferencesModel(userID, req)
+	if err := h.service.UpdatePreferences(c.Request.Context(), prefs); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update notification preferences"})
+		return
+	}
+
+	logger.Log.Infof("UpdateNotificationPreferences: user %s, enabled=%t", userID, prefs.Enabled)
+	c.JSON(http.StatusOK, mapper.ToNotificationPreferencesResponse(*prefs))
+}
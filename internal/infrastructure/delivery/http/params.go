@@ -0,0 +1,136 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const monthLayout = "01-2006"
+
+// queryUserID parses the optional user_id query parameter.
+func queryUserID(c *gin.Context) (*uuid.UUID, error) {
+	userIDStr := c.Query("user_id")
+	if userIDStr == "" {
+		return nil, nil
+	}
+	uid, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, errors.New("invalid user_id")
+	}
+	return &uid, nil
+}
+
+// queryServiceName returns the optional service_name query parameter.
+func queryServiceName(c *gin.Context) *string {
+	serviceName := c.Query("service_name")
+	if serviceName == "" {
+		return nil
+	}
+	return &serviceName
+}
+
+// queryCurrency parses the optional currency query parameter.
+func queryCurrency(c *gin.Context) (*string, error) {
+	return mapper.ToCurrencyFilter(c.Query("currency"))
+}
+
+// queryStatus parses the optional status query parameter.
+func queryStatus(c *gin.Context) (*model.SubscriptionStatus, error) {
+	value := c.Query("status")
+	if value == "" {
+		return nil, nil
+	}
+	for _, status := range model.SubscriptionStatuses {
+		if string(status) == value {
+			return &status, nil
+		}
+	}
+	return nil, errors.New("invalid status, use one of scheduled, trial, active, expired, cancelled")
+}
+
+// queryDeletionReason parses the optional reason query parameter, by default a deletion is a mistake.
+func queryDeletionReason(c *gin.Context) (model.DeletionReason, error) {
+	return deletionReason(c.Query("reason"))
+}
+
+func deletionReason(value string) (model.DeletionReason, error) {
+	switch reason := model.DeletionReason(value); reason {
+	case "":
+		return model.DeletionMistake, nil
+	case model.DeletionMistake, model.DeletionEnded:
+		return reason, nil
+	}
+	return "", errors.New("invalid reason, use mistake or ended")
+}
+
+// queryMonth parses a MM-YYYY query parameter, falling back to def when it is absent.
+func queryMonth(c *gin.Context, name string, def time.Time) (time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return def, nil
+	}
+	t, err := time.Parse(monthLayout, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid '" + name + "' date format, use MM-YYYY")
+	}
+	return t, nil
+}
+
+// queryInt parses an integer query parameter within [min, max], falling back to def when it is absent.
+func queryInt(c *gin.Context, name string, def, min, max int) (int, error) {
+	value := c.Query(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < min || n > max {
+		return 0, errors.New("'" + name + "' must be an integer between " + strconv.Itoa(min) + " and " + strconv.Itoa(max))
+	}
+	return n, nil
+}
+
+// queryBool parses an optional boolean query parameter, absent means false.
+func queryBool(c *gin.Context, name string) (bool, error) {
+	value := c.Query(name)
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.New("'" + name + "' must be a boolean")
+	}
+	return b, nil
+}
+
+// queryDelimiter parses the optional single character CSV delimiter, defaulting to a comma.
+func queryDelimiter(c *gin.Context) (rune, error) {
+	value := c.Query("delimiter")
+	if value == "" {
+		return ',', nil
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) || r == '"' || r == '\n' || r == '\r' {
+		return 0, errors.New("'delimiter' must be a single character")
+	}
+	return r, nil
+}
+
+// queryTime parses an optional RFC 3339 timestamp query parameter.
+func queryTime(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.New("invalid '" + name + "' format, use RFC 3339")
+	}
+	return &t, nil
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RecommendationHandler struct {
+	service port.RecommendationService
+}
+
+func NewRecommendationHandler(service port.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{service: service}
+}
+
+// GetRecommendations godoc
+// @Summary Get savings recommendations
+// @Description Returns scored and explained savings suggestions for user's active subscriptions
+// @Tags recommendations
+// @Produce json
+// @Param id path string true "User UUID"
+// @Success 200 {array} dto.RecommendationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/recommendations [get]
+func (h *RecommendationHandler) GetRecommendations(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("GetRecommendations: user %s", idStr)
+
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user id"})
+		return
+	}
+
+	recs, err := h.service.Recommend(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to build recommendations"})
+		return
+	}
+
+	resp := make([]dto.RecommendationResponse, 0, len(recs))
+	for _, rec := range recs {
+		resp = append(resp, mapper.ToRecommendationResponse(rec))
+	}
+
+	logger.Log.Infof("GetRecommendations: %d recommendations for user %s", len(resp), userID)
+	c.JSON(http.StatusOK, resp)
+}
@@ -4,14 +4,87 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterRoutes(r *gin.Engine, handler *SubscriptionHandler) {
+type Handlers struct {
+	Subscription   *SubscriptionHandler
+	Recommendation *RecommendationHandler
+	Statement      *StatementHandler
+	Audit          *AuditHandler
+	Webhook        *WebhookHandler
+	Notification   *NotificationHandler
+	Telegram       *TelegramHandler
+	Job            *JobHandler
+	// Idempotency is applied to create and import endpoints, requests pass through when it is nil.
+	Idempotency gin.HandlerFunc
+	// GraphQL serves /graphql, the endpoint is not registered when it is nil.
+	GraphQL gin.HandlerFunc
+}
+
+func RegisterRoutes(r *gin.Engine, h Handlers) {
+	r.Use(ActorMiddleware())
+
+	idempotent := h.Idempotency
+	if idempotent == nil {
+		idempotent = func(c *gin.Context) { c.Next() }
+	}
+
 	s := r.Group("/subscriptions")
 	{
-		s.POST("", handler.CreateSubscription)
-		s.GET("", handler.ListSubscriptions)
-		s.GET("/cost", handler.CalculateTotalCost)
-		s.GET("/:id", handler.GetSubscription)
-		s.PUT("/:id", handler.UpdateSubscription)
-		s.DELETE("/:id", handler.DeleteSubscription)
+		s.POST("", idempotent, h.Subscription.CreateSubscription)
+		s.GET("", h.Subscription.ListSubscriptions)
+		s.GET("/cost", h.Subscription.CalculateTotalCost)
+		s.GET("/cost/compare", h.Subscription.CompareCost)
+		s.GET("/forecast", h.Subscription.ForecastCost)
+		s.GET("/duplicates", h.Subscription.FindDuplicates)
+		s.POST("/import", idempotent, h.Subscription.ImportSubscriptions)
+		s.POST("/batch", idempotent, h.Subscription.BatchCreateSubscriptions)
+		s.PUT("/batch", h.Subscription.BatchUpdateSubscriptions)
+		s.DELETE("/batch", h.Subscription.BatchDeleteSubscriptions)
+		s.GET("/:id", h.Subscription.GetSubscription)
+		s.PUT("/:id", h.Subscription.UpdateSubscription)
+		s.DELETE("/:id", h.Subscription.DeleteSubscription)
+		s.POST("/:id/restore", h.Subscription.RestoreSubscription)
+		s.POST("/:id/cancel", h.Subscription.CancelSubscription)
+		s.POST("/:id/reactivate", h.Subscription.ReactivateSubscription)
+		s.GET("/:id/history", h.Audit.GetSubscriptionHistory)
+		s.POST("/:id/prices", h.Subscription.SchedulePriceChange)
+		s.GET("/:id/prices", h.Subscription.ListPriceChanges)
+		s.PUT("/:id/members", h.Subscription.SetMembers)
+		s.GET("/:id/members", h.Subscription.ListMembers)
+		s.POST("/:id/discounts", h.Subscription.CreateDiscount)
+		s.GET("/:id/discounts", h.Subscription.ListDiscounts)
+		s.DELETE("/:id/discounts/:discount_id", h.Subscription.DeleteDiscount)
+	}
+
+	u := r.Group("/users")
+	{
+		u.GET("/:id/recommendations", h.Recommendation.GetRecommendations)
+		u.POST("/:id/statements/analyze", h.Statement.AnalyzeStatement)
+		u.POST("/:id/statements/confirm", idempotent, h.Statement.ConfirmCandidates)
+		u.GET("/:id/notifications", h.Notification.GetNotificationPreferences)
+		u.PUT("/:id/notifications", h.Notification.UpdateNotificationPreferences)
+		u.POST("/:id/telegram/link", h.Telegram.CreateTelegramLink)
+	}
+
+	w := r.Group("/webhooks")
+	{
+		w.POST("", idempotent, h.Webhook.RegisterWebhook)
+		w.GET("", h.Webhook.ListWebhooks)
+		w.DELETE("/:id", h.Webhook.DeleteWebhook)
+		w.GET("/:id/deliveries", h.Webhook.ListWebhookDeliveries)
+		w.GET("/:id/deliveries/:delivery_id", h.Webhook.GetWebhookDelivery)
+		w.POST("/:id/deliveries/:delivery_id/redeliver", h.Webhook.RedeliverWebhook)
+	}
+
+	if h.GraphQL != nil {
+		r.GET("/graphql", h.GraphQL)
+		r.POST("/graphql", h.GraphQL)
+	}
+
+	a := r.Group("/admin")
+	{
+		a.GET("/audit", h.Audit.QueryAuditLog)
+		a.GET("/jobs", h.Job.ListJobs)
+		a.POST("/jobs/:name/run", h.Job.TriggerJob)
+		a.GET("/jobs/:name/runs", h.Job.ListJobRuns)
 	}
 }
@@ -0,0 +1,212 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/statement"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var currencyPattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// dateFormatLayouts converts user friendly date formats like DD.MM.YYYY to Go layouts.
+var dateFormatLayouts = strings.NewReplacer("YYYY", "2006", "MM", "01", "DD", "02")
+
+type StatementHandler struct {
+	statements    port.StatementService
+	subscriptions port.SubscriptionService
+}
+
+func NewStatementHandler(statements port.StatementService, subscriptions port.SubscriptionService) *StatementHandler {
+	return &StatementHandler{statements: statements, subscriptions: subscriptions}
+}
+
+// AnalyzeStatement godoc
+// @Summary Detect subscriptions in a bank statement
+// @Description Parses an OFX or bank CSV statement, detects recurring charges by merchant, amount and cadence and proposes candidate subscriptions.
+// @Description Candidates the user already tracks have existing_subscription_id set. Nothing is stored.
+// @Description CSV files need date, description and signed amount columns (charges are negative); common header names are recognized, others can be mapped with mapping[<column>]=<field>.
+// @Tags statements
+// @Accept multipart/form-data
+// @Accept text/csv
+// @Accept application/x-ofx
+// @Produce json
+// @Param id path string true "User UUID"
+// @Param file formData file false "Statement file (for multipart requests)"
+// @Param format query string false "Statement format, detected from the content by default" Enums(ofx, csv)
+// @Param currency query string false "Currency when the file does not specify it, defaults to RUB"
+// @Param delimiter query string false "CSV field delimiter, defaults to ','"
+// @Param date_format query string false "CSV date format, e.g. DD.MM.YYYY"
+// @Param slash_dates query string false "Order of day and month in CSV dates like 01/02/2025, such dates are rejected by default" Enums(day_first, month_first)
+// @Param mapping query object false "CSV column to field (date, description, amount, currency) mapping, e.g. mapping[Контрагент]=description"
+// @Success 200 {object} dto.StatementAnalysisResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 413 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/statements/analyze [post]
+func (h *StatementHandler) AnalyzeStatement(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("AnalyzeStatement: user %s, format=%s", idStr, c.Query("format"))
+
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user id"})
+		return
+	}
+
+	currency := strings.ToUpper(c.DefaultQuery("currency", model.DefaultCurrency))
+	if !currencyPattern.MatchString(currency) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid currency"})
+		return
+	}
+	delimiter, err := queryDelimiter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	body, ok := uploadedFile(c)
+	if !ok {
+		return
+	}
+	defer body.Close()
+	reader := bufio.NewReader(body)
+
+	format := strings.ToLower(c.Query("format"))
+	if format == "" {
+		// OFX начинается с заголовка OFXHEADER или XML-пролога с тегом <OFX>
+		head, _ := reader.Peek(1024)
+		format = "csv"
+		if bytes.Contains(bytes.ToUpper(head), []byte("OFX")) {
+			format = "ofx"
+		}
+	}
+
+	var parser port.StatementParser
+	switch format {
+	case "ofx":
+		parser = statement.NewOFXParser(currency)
+	case "csv":
+		slashDates := statement.SlashDateOrder(c.Query("slash_dates"))
+		switch slashDates {
+		case "", statement.SlashDayFirst, statement.SlashMonthFirst:
+		default:
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "'slash_dates' must be one of day_first, month_first"})
+			return
+		}
+		parser = statement.NewCSVParser(statement.CSVOptions{
+			Delimiter:  delimiter,
+			Mapping:    c.QueryMap("mapping"),
+			DateLayout: dateFormatLayouts.Replace(c.Query("date_format")),
+			SlashDates: slashDates,
+			Currency:   currency,
+		})
+	default:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "'format' must be one of ofx, csv"})
+		return
+	}
+
+	transactions, err := parser.Parse(reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: fmt.Sprintf("failed to parse statement: %v", err)})
+		return
+	}
+
+	candidates, err := h.statements.DetectSubscriptions(c.Request.Context(), userID, transactions)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to analyze statement"})
+		return
+	}
+
+	resp := dto.StatementAnalysisResponse{
+		Transactions: len(transactions),
+		Candidates:   make([]dto.SubscriptionCandidateResponse, 0, len(candidates)),
+	}
+	for _, candidate := range candidates {
+		resp.Candidates = append(resp.Candidates, mapper.ToSubscriptionCandidateResponse(candidate))
+	}
+
+	logger.Log.Infof("AnalyzeStatement: %d transactions, %d candidates for user %s", resp.Transactions, len(resp.Candidates), userID)
+	c.JSON(http.StatusOK, resp)
+}
+
+// ConfirmCandidates godoc
+// @Summary Create subscriptions from statement candidates
+// @Description Creates subscriptions for the candidates the user confirmed, possibly edited. All candidates are stored in one transaction or none is.
+// @Tags statements
+// @Accept json
+// @Produce json
+// @Param id path string true "User UUID"
+// @Param candidates body dto.ConfirmCandidatesRequest true "Confirmed candidates"
+// @Param strict query bool false "Reject candidates overlapping an existing subscription or each other"
+// @Param Idempotency-Key header string false "Key to safely retry the request, the first response is replayed"
+// @Success 201 {array} dto.SubscriptionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/statements/confirm [post]
+func (h *StatementHandler) ConfirmCandidates(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("ConfirmCandidates: user %s", idStr)
+
+	userID, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user id"})
+		return
+	}
+	strict, err := queryBool(c, "strict")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var req dto.ConfirmCandidatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	subs := make([]*model.Subscription, 0, len(req.Candidates))
+	for i, candidate := range req.Candidates {
+		sub, err := mapper.ToSubscriptionModelFromCandidate(userID.String(), candidate)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: fmt.Sprintf("candidate %d: %v", i, err)})
+			return
+		}
+		subs = append(subs, sub)
+	}
+
+	err = h.subscriptions.ImportSubscriptions(c.Request.Context(), subs, false, strict)
+	switch {
+	case errors.Is(err, model.ErrSubscriptionOverlap):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	case errors.Is(err, model.ErrCurrencyMismatch), errors.Is(err, model.ErrInvalidMoney), errors.Is(err, model.ErrMoneyOverflow):
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{Error: err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to create subscriptions"})
+		return
+	}
+
+	resp := make([]dto.SubscriptionResponse, 0, len(subs))
+	for _, sub := range subs {
+		resp = append(resp, mapper.ToSubscriptionResponse(*sub))
+	}
+
+	logger.Log.Infof("ConfirmCandidates: created %d subscriptions for user %s", len(resp), userID)
+	c.JSON(http.StatusCreated, resp)
+}
@@ -1,10 +1,14 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
 	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
 	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
 	"github.com/Babushkin05/subscription-organizer/pkg/logger"
@@ -27,12 +31,22 @@ func NewSubscriptionHandler(service port.SubscriptionService) *SubscriptionHandl
 // @Accept json
 // @Produce json
 // @Param subscription body dto.CreateSubscriptionRequest true "Subscription to create"
+// @Param strict query bool false "Reject subscriptions overlapping an existing one of the same user and service"
+// @Param Idempotency-Key header string false "Key to safely retry the request, the first response is replayed"
 // @Success 201 {object} dto.SubscriptionResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /subscriptions [post]
 func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
 	logger.Log.Info("CreateSubscription: received request")
+	strict, err := queryBool(c, "strict")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	var req dto.CreateSubscriptionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
@@ -41,11 +55,15 @@ func (h *SubscriptionHandler) CreateSubscription(c *gin.Context) {
 
 	sub, err := mapper.ToSubscriptionModel(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid date format, use MM-YYYY"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
-	err = h.service.CreateSubscription(c.Request.Context(), sub)
+	err = h.service.CreateSubscription(c.Request.Context(), sub, strict)
+	if errors.Is(err, model.ErrSubscriptionOverlap) {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to create subscription"})
 		return
@@ -77,7 +95,7 @@ func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
 	}
 
 	sub, err := h.service.GetSubscription(c.Request.Context(), id)
-	if err != nil {
+	if err != nil || sub == nil {
 		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
 		return
 	}
@@ -89,14 +107,17 @@ func (h *SubscriptionHandler) GetSubscription(c *gin.Context) {
 
 // UpdateSubscription godoc
 // @Summary Update a subscription
-// @Description Updates a subscription by ID
+// @Description Updates a subscription by ID. The currency can't change while fixed member shares or discounts are set in it (409).
 // @Tags subscriptions
 // @Accept json
 // @Produce json
 // @Param id path string true "Subscription ID"
 // @Param subscription body dto.CreateSubscriptionRequest true "Updated subscription data"
+// @Param strict query bool false "Reject changes making the subscription overlap another one of the same user and service"
 // @Success 200 {object} dto.SubscriptionResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /subscriptions/{id} [put]
 func (h *SubscriptionHandler) UpdateSubscription(c *gin.Context) {
@@ -108,6 +129,12 @@ func (h *SubscriptionHandler) UpdateSubscription(c *gin.Context) {
 		return
 	}
 
+	strict, err := queryBool(c, "strict")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
 	var req dto.CreateSubscriptionRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
@@ -116,13 +143,21 @@ func (h *SubscriptionHandler) UpdateSubscription(c *gin.Context) {
 
 	sub, err := mapper.ToSubscriptionModel(req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid date format, use MM-YYYY"})
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
 		return
 	}
 
 	sub.ID = id
 
-	err = h.service.UpdateSubscription(c.Request.Context(), sub)
+	err = h.service.UpdateSubscription(c.Request.Context(), sub, strict)
+	if errors.Is(err, model.ErrSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	}
+	if errors.Is(err, model.ErrSubscriptionOverlap) || errors.Is(err, model.ErrCurrencyMismatch) {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to update subscription"})
 		return
@@ -135,12 +170,14 @@ func (h *SubscriptionHandler) UpdateSubscription(c *gin.Context) {
 
 // DeleteSubscription godoc
 // @Summary Delete a subscription
-// @Description Soft-deletes a subscription by ID
+// @Description Soft-deletes a subscription by ID. A mistake (default) disappears from all costs, a subscription that ended keeps counting in past costs up to the month it was deleted. Deleting again changes the reason.
 // @Tags subscriptions
 // @Produce json
 // @Param id path string true "Subscription ID"
+// @Param reason query string false "Why the subscription is deleted" Enums(mistake, ended) default(mistake)
 // @Success 200 {object} dto.MessageResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /subscriptions/{id} [delete]
 func (h *SubscriptionHandler) DeleteSubscription(c *gin.Context) {
@@ -153,23 +190,73 @@ func (h *SubscriptionHandler) DeleteSubscription(c *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteSubscription(c.Request.Context(), id)
+	reason, err := queryDeletionReason(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = h.service.DeleteSubscription(c.Request.Context(), id, reason)
+	if errors.Is(err, model.ErrSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to delete subscription"})
 		return
 	}
 
-	logger.Log.Infof("DeleteSubscription: deleted subscription %s", id)
+	logger.Log.Infof("DeleteSubscription: deleted subscription %s as %s", id, reason)
 	c.JSON(http.StatusOK, dto.MessageResponse{Message: "subscription deleted"})
 }
 
+// RestoreSubscription godoc
+// @Summary Restore a deleted subscription
+// @Description Undoes a soft delete. Restoring an active subscription changes nothing.
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/restore [post]
+func (h *SubscriptionHandler) RestoreSubscription(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("RestoreSubscription: restoring subscription %s", idStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	sub, err := h.service.RestoreSubscription(c.Request.Context(), id)
+	if errors.Is(err, model.ErrSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to restore subscription"})
+		return
+	}
+
+	logger.Log.Infof("RestoreSubscription: restored subscription %s", id)
+	c.JSON(http.StatusOK, mapper.ToSubscriptionResponse(*sub))
+}
+
 // ListSubscriptions godoc
 // @Summary List all subscriptions
-// @Description Returns a list of subscriptions (optionally filtered by user_id and service_name)
+// @Description Returns a list of subscriptions (optionally filtered by user_id, service_name and status).
+// @Description CSV and XLSX exports are streamed from the database; the format comes from the format parameter or the Accept header.
 // @Tags subscriptions
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param user_id query string false "User UUID"
 // @Param service_name query string false "Service Name"
+// @Param status query string false "Status" Enums(scheduled, trial, active, expired, cancelled)
+// @Param format query string false "Response format" Enums(json, csv, xlsx)
 // @Success 200 {array} dto.SubscriptionResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -189,6 +276,22 @@ func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
 		userID = &uid
 	}
 
+	status, err := queryStatus(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	format, err := exportFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if format != formatJSON {
+		h.exportSubscriptions(c, format, userID, queryServiceName(c), status)
+		return
+	}
+
 	subs, err := h.service.ListSubscriptions(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list subscriptions"})
@@ -203,6 +306,9 @@ func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
 		if serviceName != "" && s.ServiceName != serviceName {
 			continue
 		}
+		if status != nil && s.Status != *status {
+			continue
+		}
 		filtered = append(filtered, mapper.ToSubscriptionResponse(*s))
 	}
 
@@ -210,17 +316,72 @@ func (h *SubscriptionHandler) ListSubscriptions(c *gin.Context) {
 	c.JSON(http.StatusOK, filtered)
 }
 
+func (h *SubscriptionHandler) exportSubscriptions(c *gin.Context, format string, userID *uuid.UUID, serviceName *string, status *model.SubscriptionStatus) {
+	w, err := newTableWriter(c, format, "subscriptions", mapper.SubscriptionExportColumns)
+	if err != nil {
+		failExport(c, err)
+		return
+	}
+
+	count := 0
+	err = h.service.StreamSubscriptions(c.Request.Context(), userID, serviceName, func(sub *model.Subscription) error {
+		if status != nil && sub.Status != *status {
+			return nil
+		}
+		count++
+		return w.WriteRow(mapper.ToSubscriptionExportRow(*sub))
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		failExport(c, err)
+		return
+	}
+
+	logger.Log.Infof("ListSubscriptions: exported %d subscriptions as %s", count, format)
+}
+
+func (h *SubscriptionHandler) exportCostReport(c *gin.Context, format string, userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) {
+	w, err := newTableWriter(c, format, "cost", mapper.CostReportExportColumns)
+	if err != nil {
+		failExport(c, err)
+		return
+	}
+
+	count := 0
+	err = h.service.StreamCostReport(c.Request.Context(), userID, serviceName, currency, from, to, includeDeleted, func(line model.SubscriptionCost) error {
+		count++
+		return w.WriteRow(mapper.ToCostReportExportRow(line))
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		failExport(c, err)
+		return
+	}
+
+	logger.Log.Infof("CalculateTotalCost: exported cost of %d subscriptions as %s", count, format)
+}
+
 // CalculateTotalCost godoc
 // @Summary Calculate total subscription cost
-// @Description Calculates the total cost of subscriptions over a time period with optional filters
+// @Description Calculates the total cost of subscriptions over a time period with optional filters. With user_id, shared subscriptions count only the user's share. The result shows the amount before discounts, the discount, and the paid amount split into net and tax.
 // @Tags subscriptions
 // @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
 // @Param user_id query string false "User UUID"
 // @Param service_name query string false "Service Name"
+// @Param currency query string false "Only subscriptions billed in this currency (ISO 4217), needed when they use several"
 // @Param from query string true "Start period in MM-YYYY"
 // @Param to query string true "End period in MM-YYYY"
-// @Success 200 {object} map[string]int
+// @Param include_deleted query bool false "Count subscriptions deleted as ended up to the month of deletion, deleted mistakes are never counted" default(true)
+// @Param format query string false "Response format, csv and xlsx list the cost of every subscription" Enums(json, csv, xlsx)
+// @Success 200 {object} dto.TotalCostResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /subscriptions/cost [get]
 func (h *SubscriptionHandler) CalculateTotalCost(c *gin.Context) {
@@ -262,12 +423,660 @@ func (h *SubscriptionHandler) CalculateTotalCost(c *gin.Context) {
 		svcNamePtr = &serviceName
 	}
 
-	total, err := h.service.CalculateTotalCost(c.Request.Context(), userID, svcNamePtr, from, to)
+	currency, err := queryCurrency(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// По умолчанию прошлые расходы не зависят от того, удалили ли потом закончившуюся подписку
+	includeDeleted := true
+	if c.Query("include_deleted") != "" {
+		if includeDeleted, err = queryBool(c, "include_deleted"); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	format, err := exportFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if format != formatJSON {
+		h.exportCostReport(c, format, userID, svcNamePtr, currency, from, to, includeDeleted)
+		return
+	}
+
+	total, err := h.service.CalculateTotalCost(c.Request.Context(), userID, svcNamePtr, currency, from, to, includeDeleted)
+	if err != nil {
+		writeCostError(c, err, "failed to calculate total cost")
+		return
+	}
+
+	logger.Log.Infof("CalculateTotalCost: total cost = %s", total.Gross)
+	c.JSON(http.StatusOK, mapper.ToTotalCostResponse(total))
+}
+
+// ForecastCost godoc
+// @Summary Forecast monthly spending
+// @Description Projects monthly spending for the upcoming months, taking end dates, scheduled price changes, trials and billing periods into account. Spending of open-ended subscriptions is reported as projected.
+// @Tags subscriptions
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param user_id query string false "User UUID"
+// @Param service_name query string false "Service Name"
+// @Param currency query string false "Only subscriptions billed in this currency (ISO 4217), needed when they use several"
+// @Param from query string false "First forecast month in MM-YYYY, defaults to the current month"
+// @Param months query int false "Number of months to forecast (1-60), defaults to 12"
+// @Param format query string false "Response format" Enums(json, csv, xlsx)
+// @Success 200 {object} dto.ForecastResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/forecast [get]
+func (h *SubscriptionHandler) ForecastCost(c *gin.Context) {
+	logger.Log.Infof("ForecastCost: user_id=%s, service_name=%s, from=%s, months=%s",
+		c.Query("user_id"), c.Query("service_name"), c.Query("from"), c.Query("months"))
+
+	userID, err := queryUserID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	from, err := queryMonth(c, "from", time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	months, err := queryInt(c, "months", 12, 1, 60)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	currency, err := queryCurrency(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	format, err := exportFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	forecast, err := h.service.ForecastCost(c.Request.Context(), userID, queryServiceName(c), currency, from, months)
+	if err != nil {
+		writeCostError(c, err, "failed to forecast cost")
+		return
+	}
+	if format != formatJSON {
+		writeTable(c, format, "forecast", mapper.ForecastExportColumns, mapper.ToForecastExportRows(forecast))
+		return
+	}
+
+	resp := mapper.ToForecastResponse(forecast)
+	logger.Log.Infof("ForecastCost: forecast total = %s", resp.Total)
+	c.JSON(http.StatusOK, resp)
+}
+
+// CompareCost godoc
+// @Summary Compare spending of two periods
+// @Description Returns totals of a base and a current period, absolute and percentage deltas and per-service contributions to the change. Subscriptions deleted as ended count up to the month of deletion.
+// @Tags subscriptions
+// @Produce json
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param user_id query string false "User UUID"
+// @Param service_name query string false "Service Name"
+// @Param currency query string false "Only subscriptions billed in this currency (ISO 4217), needed when they use several"
+// @Param base_from query string true "Base period start in MM-YYYY"
+// @Param base_to query string true "Base period end in MM-YYYY"
+// @Param from query string true "Current period start in MM-YYYY"
+// @Param to query string true "Current period end in MM-YYYY"
+// @Param format query string false "Response format, csv and xlsx list per-service changes" Enums(json, csv, xlsx)
+// @Success 200 {object} dto.CostComparisonResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/cost/compare [get]
+func (h *SubscriptionHandler) CompareCost(c *gin.Context) {
+	logger.Log.Infof("CompareCost: user_id=%s, service_name=%s, base=%s..%s, current=%s..%s",
+		c.Query("user_id"), c.Query("service_name"),
+		c.Query("base_from"), c.Query("base_to"), c.Query("from"), c.Query("to"))
+
+	var periods [2]model.Period
+	for i, names := range [2][2]string{{"base_from", "base_to"}, {"from", "to"}} {
+		if c.Query(names[0]) == "" || c.Query(names[1]) == "" {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "'base_from', 'base_to', 'from' and 'to' query parameters required, format MM-YYYY"})
+			return
+		}
+		from, err := queryMonth(c, names[0], time.Time{})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		to, err := queryMonth(c, names[1], time.Time{})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+			return
+		}
+		if to.Before(from) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "'" + names[1] + "' must not be before '" + names[0] + "'"})
+			return
+		}
+		periods[i] = model.Period{From: from, To: to}
+	}
+
+	userID, err := queryUserID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	currency, err := queryCurrency(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	format, err := exportFormat(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	comparison, err := h.service.CompareCost(c.Request.Context(), userID, queryServiceName(c), currency, periods[0], periods[1])
+	if err != nil {
+		writeCostError(c, err, "failed to compare cost")
+		return
+	}
+	if format != formatJSON {
+		writeTable(c, format, "cost_comparison", mapper.CostComparisonExportColumns, mapper.ToCostComparisonExportRows(*comparison))
+		return
+	}
+
+	logger.Log.Infof("CompareCost: base = %s, current = %s", comparison.BaseTotal, comparison.CurrentTotal)
+	c.JSON(http.StatusOK, mapper.ToCostComparisonResponse(*comparison))
+}
+
+// CancelSubscription godoc
+// @Summary Cancel a subscription
+// @Description at_period_end (default) ends the subscription with the billing period in progress, a trial ends before the first charge. The status stays the same until then, and the cancellation can be undone with reactivate.
+// @Description immediately ends it now: the current month stays in the costs as already paid, later months are dropped. A pending cancellation can be made immediate.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param cancel body dto.CancelSubscriptionRequest false "Cancellation mode"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/cancel [post]
+func (h *SubscriptionHandler) CancelSubscription(c *gin.Context) {
+	var req dto.CancelSubscriptionRequest
+	// Тело необязательно
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	mode := model.CancelAtPeriodEnd
+	if req.Mode != "" {
+		mode = model.CancelMode(req.Mode)
+	}
+
+	h.transition(c, "CancelSubscription", func(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+		return h.service.CancelSubscription(ctx, id, mode)
+	})
+}
+
+// ReactivateSubscription godoc
+// @Summary Undo a cancellation
+// @Description Makes a cancelled subscription open-ended again, possible until its paid period ends
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} dto.SubscriptionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/reactivate [post]
+func (h *SubscriptionHandler) ReactivateSubscription(c *gin.Context) {
+	h.transition(c, "ReactivateSubscription", h.service.ReactivateSubscription)
+}
+
+func (h *SubscriptionHandler) transition(c *gin.Context, name string, fn func(ctx context.Context, id uuid.UUID) (*model.Subscription, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	sub, err := fn(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, model.ErrSubscriptionNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	case errors.Is(err, model.ErrInvalidTransition):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{Error: err.Error()})
+		return
+	case err != nil:
+		logger.Log.Errorf("%s: %v", name, err)
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to change subscription status"})
+		return
+	}
+
+	logger.Log.Infof("%s: subscription %s is %s", name, id, sub.Status)
+	c.JSON(http.StatusOK, mapper.ToSubscriptionResponse(*sub))
+}
+
+// SchedulePriceChange godoc
+// @Summary Schedule a price change
+// @Description Sets a new subscription price starting from the given month. Scheduling another change for the same month replaces it.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param change body dto.CreatePriceChangeRequest true "Price change"
+// @Success 201 {object} dto.PriceChangeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/prices [post]
+func (h *SubscriptionHandler) SchedulePriceChange(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("SchedulePriceChange: subscription %s", idStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	var req dto.CreatePriceChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	currency, ok := h.subscriptionCurrency(c, id)
+	if !ok {
+		return
+	}
+
+	change, err := mapper.ToPriceChangeModel(id, currency, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = h.service.SchedulePriceChange(c.Request.Context(), change)
+	if errors.Is(err, model.ErrSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	}
+	if errors.Is(err, model.ErrCurrencyMismatch) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to schedule price change"})
+		return
+	}
+
+	logger.Log.Infof("SchedulePriceChange: price of %s changes from %s", id, req.EffectiveFrom)
+	c.JSON(http.StatusCreated, mapper.ToPriceChangeResponse(*change))
+}
+
+// ListPriceChanges godoc
+// @Summary List price changes
+// @Description Returns past and scheduled price changes of a subscription
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} dto.PriceChangeResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/prices [get]
+func (h *SubscriptionHandler) ListPriceChanges(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("ListPriceChanges: subscription %s", idStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	changes, err := h.service.ListPriceChanges(c.Request.Context(), id)
+	if errors.Is(err, model.ErrSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list price changes"})
+		return
+	}
+
+	resp := make([]dto.PriceChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		resp = append(resp, mapper.ToPriceChangeResponse(*change))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// SetMembers godoc
+// @Summary Set members of a shared subscription
+// @Description Replaces the list of users sharing the subscription and their split rules. The owner pays whatever is left after fixed and percentage members, split equally with equal members.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param members body dto.SetMembersRequest true "Members"
+// @Success 200 {array} dto.SubscriptionMemberResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/members [put]
+func (h *SubscriptionHandler) SetMembers(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("SetMembers: subscription %s", idStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	var req dto.SetMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	currency, ok := h.subscriptionCurrency(c, id)
+	if !ok {
+		return
+	}
+
+	members, err := mapper.ToSubscriptionMembers(id, currency, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = h.service.SetMembers(c.Request.Context(), id, members)
+	switch {
+	case errors.Is(err, model.ErrSubscriptionNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	case errors.Is(err, model.ErrInvalidMembers):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to set members"})
+		return
+	}
+
+	resp := make([]dto.SubscriptionMemberResponse, 0, len(members))
+	for _, m := range members {
+		resp = append(resp, mapper.ToSubscriptionMemberResponse(*m, currency))
+	}
+
+	logger.Log.Infof("SetMembers: subscription %s shared with %d members", id, len(resp))
+	c.JSON(http.StatusOK, resp)
+}
+
+// ListMembers godoc
+// @Summary List members of a shared subscription
+// @Description Returns users sharing the subscription and their split rules
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} dto.SubscriptionMemberResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/members [get]
+func (h *SubscriptionHandler) ListMembers(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("ListMembers: subscription %s", idStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	currency, ok := h.subscriptionCurrency(c, id)
+	if !ok {
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), id)
+	if errors.Is(err, model.ErrSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to calculate total cost"})
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list members"})
 		return
 	}
 
-	logger.Log.Infof("CalculateTotalCost: total cost = %d", total)
-	c.JSON(http.StatusOK, gin.H{"total_cost": total})
+	resp := make([]dto.SubscriptionMemberResponse, 0, len(members))
+	for _, m := range members {
+		resp = append(resp, mapper.ToSubscriptionMemberResponse(*m, currency))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateDiscount godoc
+// @Summary Add a discount
+// @Description Adds a percentage or fixed discount (optionally from a promo code) applied to every billed month between start_date and end_date
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param discount body dto.CreateDiscountRequest true "Discount"
+// @Success 201 {object} dto.DiscountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/discounts [post]
+func (h *SubscriptionHandler) CreateDiscount(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("CreateDiscount: subscription %s", idStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	var req dto.CreateDiscountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	currency, ok := h.subscriptionCurrency(c, id)
+	if !ok {
+		return
+	}
+
+	discount, err := mapper.ToDiscountModel(id, currency, req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	err = h.service.CreateDiscount(c.Request.Context(), discount)
+	switch {
+	case errors.Is(err, model.ErrSubscriptionNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	case errors.Is(err, model.ErrInvalidDiscount):
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to create discount"})
+		return
+	}
+
+	logger.Log.Infof("CreateDiscount: discount %s added to subscription %s", discount.ID, id)
+	c.JSON(http.StatusCreated, mapper.ToDiscountResponse(*discount, currency))
+}
+
+// ListDiscounts godoc
+// @Summary List discounts
+// @Description Returns discounts of a subscription
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {array} dto.DiscountResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/discounts [get]
+func (h *SubscriptionHandler) ListDiscounts(c *gin.Context) {
+	idStr := c.Param("id")
+	logger.Log.Infof("ListDiscounts: subscription %s", idStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+
+	currency, ok := h.subscriptionCurrency(c, id)
+	if !ok {
+		return
+	}
+
+	discounts, err := h.service.ListDiscounts(c.Request.Context(), id)
+	if errors.Is(err, model.ErrSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list discounts"})
+		return
+	}
+
+	resp := make([]dto.DiscountResponse, 0, len(discounts))
+	for _, d := range discounts {
+		resp = append(resp, mapper.ToDiscountResponse(*d, currency))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteDiscount godoc
+// @Summary Delete a discount
+// @Description Removes a discount from a subscription
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param discount_id path string true "Discount ID"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/{id}/discounts/{discount_id} [delete]
+func (h *SubscriptionHandler) DeleteDiscount(c *gin.Context) {
+	idStr, discountIDStr := c.Param("id"), c.Param("discount_id")
+	logger.Log.Infof("DeleteDiscount: subscription %s, discount %s", idStr, discountIDStr)
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid subscription id"})
+		return
+	}
+	discountID, err := uuid.Parse(discountIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid discount id"})
+		return
+	}
+
+	err = h.service.DeleteDiscount(c.Request.Context(), id, discountID)
+	switch {
+	case errors.Is(err, model.ErrSubscriptionNotFound), errors.Is(err, model.ErrDiscountNotFound):
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to delete discount"})
+		return
+	}
+
+	logger.Log.Infof("DeleteDiscount: deleted discount %s", discountID)
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "discount deleted"})
+}
+
+// FindDuplicates godoc
+// @Summary Find duplicate subscriptions
+// @Description Returns groups of subscriptions of the same user to the same service (case-insensitive) whose date ranges overlap
+// @Tags subscriptions
+// @Produce json
+// @Param user_id query string false "User UUID"
+// @Success 200 {array} dto.DuplicateGroupResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /subscriptions/duplicates [get]
+func (h *SubscriptionHandler) FindDuplicates(c *gin.Context) {
+	logger.Log.Infof("FindDuplicates: user_id=%s", c.Query("user_id"))
+
+	userID, err := queryUserID(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	groups, err := h.service.FindDuplicates(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to find duplicates"})
+		return
+	}
+
+	resp := make([]dto.DuplicateGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		resp = append(resp, mapper.ToDuplicateGroupResponse(group))
+	}
+
+	logger.Log.Infof("FindDuplicates: found %d groups", len(resp))
+	c.JSON(http.StatusOK, resp)
+}
+
+// subscriptionCurrency returns the currency of the subscription, writing
+// an error response and returning false when it cannot be loaded.
+func (h *SubscriptionHandler) subscriptionCurrency(c *gin.Context, id uuid.UUID) (string, bool) {
+	sub, err := h.service.GetSubscription(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get subscription"})
+		return "", false
+	}
+	if sub == nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "subscription not found"})
+		return "", false
+	}
+	return sub.Price.Currency, true
+}
+
+// writeCostError maps errors of cost calculations to responses.
+func writeCostError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, model.ErrCurrencyMismatch):
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{Error: "subscriptions are billed in different currencies, pick one with the currency parameter: " + err.Error()})
+	case errors.Is(err, model.ErrMoneyOverflow):
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{Error: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: message})
+	}
 }
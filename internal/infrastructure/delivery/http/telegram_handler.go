@@ -0,0 +1,56 @@
+package http
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TelegramHandler struct {
+	service     port.ChatLinkService
+	botUsername string
+}
+
+func NewTelegramHandler(service port.ChatLinkService, botUsername string) *TelegramHandler {
+	return &TelegramHandler{service: service, botUsername: botUsername}
+}
+
+// CreateTelegramLink godoc
+// @Summary Create a Telegram link token
+// @Description Returns a one-time token that links a Telegram chat to the user when sent to the bot as /link <token>
+// @Tags telegram
+// @Produce json
+// @Param id path string true "User UUID"
+// @Success 201 {object} dto.TelegramLinkResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/telegram/link [post]
+func (h *TelegramHandler) CreateTelegramLink(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid user id"})
+		return
+	}
+
+	token, expiresAt, err := h.service.CreateLinkToken(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to create link token"})
+		return
+	}
+
+	resp := dto.TelegramLinkResponse{
+		Token:     token,
+		ExpiresAt: expiresAt.Format(time.RFC3339),
+	}
+	if h.botUsername != "" {
+		resp.URL = "https://t.me/" + h.botUsername + "?start=" + token
+	}
+
+	logger.Log.Infof("CreateTelegramLink: token for user %s", userID)
+	c.JSON(http.StatusCreated, resp)
+}
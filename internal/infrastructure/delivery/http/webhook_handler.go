@@ -0,0 +1,247 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type WebhookHandler struct {
+	service port.WebhookService
+}
+
+func NewWebhookHandler(service port.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// RegisterWebhook godoc
+// @Summary Register a webhook
+// @Description Subscribes the URL to the given event types. Every request carries X-Webhook-Signature:
+// @Description sha256=HMAC-SHA256(secret, X-Webhook-Timestamp + "." + body) in hex. The secret is generated
+// @Description when omitted and is returned only in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param webhook body dto.CreateWebhookRequest true "Webhook"
+// @Success 201 {object} dto.WebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks [post]
+func (h *WebhookHandler) RegisterWebhook(c *gin.Context) {
+	var req dto.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	endpoint, err := mapper.ToWebhookEndpointModel(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.service.RegisterEndpoint(c.Request.Context(), endpoint); err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to register webhook"})
+		return
+	}
+
+	logger.Log.Infof("RegisterWebhook: registered webhook %s for %s", endpoint.ID, endpoint.URL)
+	c.JSON(http.StatusCreated, mapper.ToWebhookResponse(*endpoint, true))
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Tags webhooks
+// @Produce json
+// @Success 200 {array} dto.WebhookResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	endpoints, err := h.service.ListEndpoints(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list webhooks"})
+		return
+	}
+
+	resp := make([]dto.WebhookResponse, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		resp = append(resp, mapper.ToWebhookResponse(*endpoint, false))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Removes the webhook together with its delivery log
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} dto.MessageResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	err := h.service.DeleteEndpoint(c.Request.Context(), id)
+	if errors.Is(err, model.ErrWebhookNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to delete webhook"})
+		return
+	}
+
+	logger.Log.Infof("DeleteWebhook: deleted webhook %s", id)
+	c.JSON(http.StatusOK, dto.MessageResponse{Message: "webhook deleted"})
+}
+
+// ListWebhookDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Returns the delivery log of the webhook, newest first
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param status query string false "Delivery status" Enums(pending, succeeded, failed)
+// @Param limit query int false "Number of deliveries (1-500)" default(50)
+// @Success 200 {array} dto.WebhookDeliveryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	id, ok := webhookID(c)
+	if !ok {
+		return
+	}
+
+	var status *model.DeliveryStatus
+	switch value := model.DeliveryStatus(c.Query("status")); value {
+	case "":
+	case model.DeliveryPending, model.DeliverySucceeded, model.DeliveryFailed:
+		status = &value
+	default:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid status"})
+		return
+	}
+
+	limit, err := queryInt(c, "limit", 50, 1, 500)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Request.Context(), id, status, limit)
+	if errors.Is(err, model.ErrWebhookNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to list deliveries"})
+		return
+	}
+
+	resp := make([]dto.WebhookDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		resp = append(resp, mapper.ToWebhookDeliveryResponse(*d, nil))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetWebhookDelivery godoc
+// @Summary Get a webhook delivery
+// @Description Returns the delivery with the log of every attempt
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 200 {object} dto.WebhookDeliveryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks/{id}/deliveries/{delivery_id} [get]
+func (h *WebhookHandler) GetWebhookDelivery(c *gin.Context) {
+	id, deliveryID, ok := webhookDeliveryID(c)
+	if !ok {
+		return
+	}
+
+	delivery, attempts, err := h.service.GetDelivery(c.Request.Context(), id, deliveryID)
+	if errors.Is(err, model.ErrDeliveryNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to get delivery"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mapper.ToWebhookDeliveryResponse(*delivery, attempts))
+}
+
+// RedeliverWebhook godoc
+// @Summary Redeliver a webhook
+// @Description Queues the delivery to be sent again right away with a fresh retry budget.
+// @Description The payload and event id stay the same, so receivers can deduplicate it.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param delivery_id path string true "Delivery ID"
+// @Success 202 {object} dto.WebhookDeliveryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /webhooks/{id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhook(c *gin.Context) {
+	id, deliveryID, ok := webhookDeliveryID(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.service.Redeliver(c.Request.Context(), id, deliveryID)
+	if errors.Is(err, model.ErrDeliveryNotFound) {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "failed to redeliver"})
+		return
+	}
+
+	logger.Log.Infof("RedeliverWebhook: queued delivery %s again", deliveryID)
+	c.JSON(http.StatusAccepted, mapper.ToWebhookDeliveryResponse(*delivery, nil))
+}
+
+func webhookID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid webhook id"})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func webhookDeliveryID(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	id, ok := webhookID(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	deliveryID, err := uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "invalid delivery id"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, deliveryID, true
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+type subscriber struct {
+	publisher port.EventPublisher
+	types     map[model.EventType]bool
+}
+
+// Bus is an in-process EventPublisher passing events on to its subscribers in
+// the order they subscribed. A failing subscriber doesn't keep the events from
+// the others, but all of them see the events again when they are retried.
+type Bus struct {
+	subscribers []subscriber
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers p for the given event types, or for all events when none are given.
+// It must not be called concurrently with Publish.
+func (b *Bus) Subscribe(p port.EventPublisher, types ...model.EventType) {
+	s := subscriber{publisher: p}
+	if len(types) > 0 {
+		s.types = make(map[model.EventType]bool, len(types))
+		for _, t := range types {
+			s.types[t] = true
+		}
+	}
+	b.subscribers = append(b.subscribers, s)
+}
+
+func (b *Bus) Publish(ctx context.Context, events ...model.Event) error {
+	var errs []error
+	for _, s := range b.subscribers {
+		matched := events
+		if s.types != nil {
+			matched = make([]model.Event, 0, len(events))
+			for _, e := range events {
+				if s.types[e.Type] {
+					matched = append(matched, e)
+				}
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		if err := s.publisher.Publish(ctx, matched...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
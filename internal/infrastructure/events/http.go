@@ -0,0 +1,56 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/mapper"
+)
+
+type httpPublisher struct {
+	url    string
+	client *http.Client
+}
+
+// NewHTTPPublisher posts events to url as a JSON array of dto.EventPayload.
+// Any 2xx response acknowledges the whole batch.
+func NewHTTPPublisher(url string, timeout time.Duration) port.EventPublisher {
+	return &httpPublisher{url: url, client: &http.Client{Timeout: timeout}}
+}
+
+func (p *httpPublisher) Publish(ctx context.Context, events ...model.Event) error {
+	payload := make([]dto.EventPayload, 0, len(events))
+	for _, e := range events {
+		payload = append(payload, mapper.ToEventPayload(e))
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event sink responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"context"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+)
+
+type logPublisher struct{}
+
+// NewLogPublisher writes every event to the application log.
+func NewLogPublisher() port.EventPublisher {
+	return logPublisher{}
+}
+
+func (logPublisher) Publish(ctx context.Context, events ...model.Event) error {
+	for _, e := range events {
+		var subscriptionID string
+		if e.Subscription != nil {
+			subscriptionID = e.Subscription.ID.String()
+		}
+		logger.Log.Infof("event %s: %s, subscription %s", e.ID, e.Type, subscriptionID)
+	}
+	return nil
+}
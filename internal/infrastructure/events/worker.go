@@ -0,0 +1,48 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+)
+
+// Worker relays the outbox every interval until ctx is cancelled.
+type Worker struct {
+	relay    port.OutboxRelay
+	interval time.Duration
+}
+
+func NewWorker(relay port.OutboxRelay, interval time.Duration) *Worker {
+	return &Worker{relay: relay, interval: interval}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.relayEvents(ctx)
+		}
+	}
+}
+
+// relayEvents keeps publishing until the outbox is drained or an event fails.
+func (w *Worker) relayEvents(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := w.relay.Relay(ctx)
+		if err != nil {
+			logger.Log.Errorf("outbox relay: published %d events, the rest failed: %v", n, err)
+			return
+		}
+		if n == 0 {
+			return
+		}
+		logger.Log.Infof("outbox relay: published %d events", n)
+	}
+}
@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+type emailChannel struct {
+	mailer Mailer
+}
+
+func NewEmailChannel(mailer Mailer) port.ReminderChannel {
+	return &emailChannel{mailer: mailer}
+}
+
+func (c *emailChannel) Name() string {
+	return "email"
+}
+
+func (c *emailChannel) Send(ctx context.Context, prefs *model.NotificationPreferences, reminder model.Reminder) error {
+	if prefs.Email == "" {
+		return model.ErrNoRecipient
+	}
+	subject, body, err := Render(reminder, time.Now())
+	if err != nil {
+		return err
+	}
+	return c.mailer.Send(ctx, prefs.Email, subject, body)
+}
@@ -0,0 +1,264 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// fakeSMTP is a minimal SMTP server without STARTTLS, it accepts AUTH PLAIN and
+// records every message. Recipients listed in reject are refused with 550.
+type fakeSMTP struct {
+	listener net.Listener
+	reject   map[string]bool
+
+	mu       sync.Mutex
+	auth     []string
+	messages []smtpMessage
+}
+
+type smtpMessage struct {
+	from string
+	to   []string
+	data []byte
+}
+
+func newFakeSMTP(t *testing.T, reject ...string) *fakeSMTP {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTP{listener: listener, reject: make(map[string]bool)}
+	for _, r := range reject {
+		s.reject[r] = true
+	}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeSMTP) config() SMTPConfig {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{
+		Host:     "127.0.0.1",
+		Port:     addr.Port,
+		Username: "reminders",
+		Password: "secret",
+		From:     "Subscriptions <noreply@example.com>",
+		Timeout:  5 * time.Second,
+	}
+}
+
+func (s *fakeSMTP) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeSMTP) handle(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	reply := func(format string, args ...any) { tp.PrintfLine(format, args...) }
+
+	reply("220 localhost ESMTP fake")
+	var msg smtpMessage
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			mechanism, initial, _ := strings.Cut(arg, " ")
+			creds, err := base64.StdEncoding.DecodeString(initial)
+			if mechanism != "PLAIN" || err != nil {
+				reply("504 unsupported")
+				continue
+			}
+			s.mu.Lock()
+			s.auth = strings.Split(string(creds), "\x00")
+			s.mu.Unlock()
+			reply("235 authenticated")
+		case "MAIL":
+			msg = smtpMessage{from: address(arg)}
+			reply("250 ok")
+		case "RCPT":
+			to := address(arg)
+			if s.reject[to] {
+				reply("550 no such user")
+				continue
+			}
+			msg.to = append(msg.to, to)
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.data = data
+			s.mu.Lock()
+			s.messages = append(s.messages, msg)
+			s.mu.Unlock()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// address extracts the mailbox from "FROM:<a@b>" or "TO:<a@b>".
+func address(arg string) string {
+	start, end := strings.IndexByte(arg, '<'), strings.IndexByte(arg, '>')
+	if start < 0 || end < start {
+		return ""
+	}
+	return arg[start+1 : end]
+}
+
+func (s *fakeSMTP) received() ([]smtpMessage, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpMessage(nil), s.messages...), s.auth
+}
+
+func renewalReminder() model.Reminder {
+	return model.Reminder{
+		Kind: model.ReminderRenewal,
+		Subscription: &model.Subscription{
+			ID:            uuid.New(),
+			ServiceName:   "Кинопоиск",
+			Price:         model.NewMoney(39900, "RUB"),
+			BillingPeriod: model.BillingPeriodMonthly,
+		},
+		Date:   time.Now().AddDate(0, 0, 3),
+		Amount: model.NewMoney(39900, "RUB"),
+	}
+}
+
+func TestEmailChannelSendsReminderOverSMTP(t *testing.T) {
+	server := newFakeSMTP(t)
+	mailer, err := NewSMTPMailer(server.config())
+	if err != nil {
+		t.Fatalf("NewSMTPMailer() error = %v", err)
+	}
+	channel := NewEmailChannel(mailer)
+	reminder := renewalReminder()
+
+	prefs := &model.NotificationPreferences{UserID: uuid.New(), Email: "Alice <alice@example.com>", Enabled: true}
+	if err := channel.Send(context.Background(), prefs, reminder); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	messages, auth := server.received()
+	if want := []string{"", "reminders", "secret"}; strings.Join(auth, "|") != strings.Join(want, "|") {
+		t.Errorf("AUTH PLAIN = %q, want %q", auth, want)
+	}
+	if len(messages) != 1 {
+		t.Fatalf("got %d messages, want 1", len(messages))
+	}
+	got := messages[0]
+	if got.from != "noreply@example.com" || len(got.to) != 1 || got.to[0] != "alice@example.com" {
+		t.Errorf("envelope = %s -> %v, want noreply@example.com -> alice@example.com", got.from, got.to)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(got.data))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	wantSubject, wantBody, err := Render(reminder, time.Now())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != wantSubject {
+		t.Errorf("Subject = %q, %v, want %q", subject, err, wantSubject)
+	}
+	if to := msg.Header.Get("To"); !strings.Contains(to, "alice@example.com") {
+		t.Errorf("To = %q, want alice@example.com", to)
+	}
+	if msg.Header.Get("Message-ID") == "" || msg.Header.Get("Date") == "" {
+		t.Error("Message-ID and Date headers are required")
+	}
+	if cte := msg.Header.Get("Content-Transfer-Encoding"); cte != "quoted-printable" {
+		t.Fatalf("Content-Transfer-Encoding = %q, want quoted-printable", cte)
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != wantBody {
+		t.Errorf("body = %q, want %q", got, wantBody)
+	}
+	if !strings.Contains(wantBody, "Кинопоиск") || !strings.Contains(wantBody, "399.00 RUB") {
+		t.Errorf("body %q lacks the service name or the amount", wantBody)
+	}
+}
+
+func TestSMTPMailerRejectedRecipient(t *testing.T) {
+	server := newFakeSMTP(t, "bob@example.com")
+	mailer, err := NewSMTPMailer(server.config())
+	if err != nil {
+		t.Fatalf("NewSMTPMailer() error = %v", err)
+	}
+
+	err = mailer.Send(context.Background(), "bob@example.com", "subject", "body")
+	var protoErr *textproto.Error
+	if !errors.As(err, &protoErr) || protoErr.Code != 550 {
+		t.Fatalf("Send() error = %v, want 550", err)
+	}
+	if messages, _ := server.received(); len(messages) != 0 {
+		t.Errorf("got %d messages, want none", len(messages))
+	}
+}
+
+func TestSMTPMailerUnreachableServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	mailer, err := NewSMTPMailer(SMTPConfig{Host: "127.0.0.1", Port: port, From: "noreply@example.com", Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewSMTPMailer() error = %v", err)
+	}
+	if err := mailer.Send(context.Background(), "alice@example.com", "subject", "body"); err == nil {
+		t.Error("Send() succeeded without a server")
+	}
+}
+
+func TestEmailChannelWithoutAddress(t *testing.T) {
+	channel := NewEmailChannel(nil)
+	err := channel.Send(context.Background(), &model.NotificationPreferences{UserID: uuid.New()}, renewalReminder())
+	if !errors.Is(err, model.ErrNoRecipient) {
+		t.Errorf("Send() error = %v, want ErrNoRecipient", err)
+	}
+}
@@ -0,0 +1,53 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/telegram"
+)
+
+type telegramChannel struct {
+	client telegram.Client
+	links  port.ChatLinkService
+}
+
+// NewTelegramChannel sends reminders to every chat linked to the user.
+func NewTelegramChannel(client telegram.Client, links port.ChatLinkService) port.ReminderChannel {
+	return &telegramChannel{client: client, links: links}
+}
+
+func (c *telegramChannel) Name() string {
+	return "telegram"
+}
+
+func (c *telegramChannel) Send(ctx context.Context, prefs *model.NotificationPreferences, reminder model.Reminder) error {
+	links, err := c.links.ListLinks(ctx, prefs.UserID)
+	if err != nil {
+		return err
+	}
+	if len(links) == 0 {
+		return model.ErrNoRecipient
+	}
+
+	subject, body, err := Render(reminder, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// Напоминание считается отправленным, если дошло хотя бы до одного чата,
+	// иначе повтор продублирует его в остальных
+	var errs []error
+	for _, link := range links {
+		if err := c.client.SendMessage(ctx, link.ChatID, subject+"\n\n"+body); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) < len(links) {
+		return nil
+	}
+	return errors.Join(errs...)
+}
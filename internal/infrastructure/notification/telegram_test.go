@@ -0,0 +1,109 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/telegram"
+	"github.com/google/uuid"
+)
+
+// staticLinks returns the same chats for every user, only ListLinks is used by the channel.
+type staticLinks struct {
+	port.ChatLinkService
+	chats []int64
+}
+
+func (l staticLinks) ListLinks(ctx context.Context, userID uuid.UUID) ([]*model.ChatLink, error) {
+	links := make([]*model.ChatLink, 0, len(l.chats))
+	for _, id := range l.chats {
+		links = append(links, &model.ChatLink{ChatID: id, UserID: userID})
+	}
+	return links, nil
+}
+
+// fakeBotAPI accepts sendMessage and answers 403 for chats in blocked.
+func fakeBotAPI(t *testing.T, blocked ...int64) (*httptest.Server, func() map[int64]string) {
+	t.Helper()
+	var mu sync.Mutex
+	sent := make(map[int64]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bottoken/sendMessage" {
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+		var params struct {
+			ChatID int64  `json:"chat_id"`
+			Text   string `json:"text"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		for _, id := range blocked {
+			if id == params.ChatID {
+				w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+				return
+			}
+		}
+		mu.Lock()
+		sent[params.ChatID] = params.Text
+		mu.Unlock()
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() map[int64]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return sent
+	}
+}
+
+func TestTelegramChannelSendsReminder(t *testing.T) {
+	server, sent := fakeBotAPI(t, 3)
+	channel := NewTelegramChannel(telegram.NewClient(server.URL, "token", time.Second), staticLinks{chats: []int64{1, 2, 3}})
+	reminder := renewalReminder()
+
+	if err := channel.Send(context.Background(), &model.NotificationPreferences{UserID: uuid.New()}, reminder); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	subject, body, err := Render(reminder, time.Now())
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	got := sent()
+	if len(got) != 2 {
+		t.Fatalf("sent to %d chats, want 2", len(got))
+	}
+	for _, id := range []int64{1, 2} {
+		if got[id] != subject+"\n\n"+body {
+			t.Errorf("chat %d got %q, want %q", id, got[id], subject+"\n\n"+body)
+		}
+	}
+}
+
+func TestTelegramChannelAllChatsFail(t *testing.T) {
+	server, _ := fakeBotAPI(t, 1, 2)
+	channel := NewTelegramChannel(telegram.NewClient(server.URL, "token", time.Second), staticLinks{chats: []int64{1, 2}})
+
+	err := channel.Send(context.Background(), &model.NotificationPreferences{UserID: uuid.New()}, renewalReminder())
+	var apiErr *telegram.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != 403 {
+		t.Errorf("Send() error = %v, want 403", err)
+	}
+}
+
+func TestTelegramChannelWithoutChats(t *testing.T) {
+	channel := NewTelegramChannel(nil, staticLinks{})
+	err := channel.Send(context.Background(), &model.NotificationPreferences{UserID: uuid.New()}, renewalReminder())
+	if !errors.Is(err, model.ErrNoRecipient) {
+		t.Errorf("Send() error = %v, want ErrNoRecipient", err)
+	}
+}
@@ -0,0 +1,61 @@
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// Every template defines a "subject" and a "body".
+var templates = map[model.ReminderKind]*template.Template{
+	model.ReminderRenewal:  template.Must(template.ParseFS(templateFiles, "templates/renewal.tmpl")),
+	model.ReminderTrialEnd: template.Must(template.ParseFS(templateFiles, "templates/trial_end.tmpl")),
+}
+
+const dateLayout = "02.01.2006"
+
+type reminderData struct {
+	ServiceName   string
+	Date          string
+	DaysLeft      int
+	Amount        string
+	Currency      string
+	BillingPeriod string
+}
+
+// Render returns the subject and the plain text body of the reminder.
+func Render(reminder model.Reminder, now time.Time) (string, string, error) {
+	tmpl, ok := templates[reminder.Kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for reminder %q", reminder.Kind)
+	}
+
+	data := reminderData{
+		ServiceName:   reminder.Subscription.ServiceName,
+		Date:          reminder.Date.Format(dateLayout),
+		DaysLeft:      int(reminder.Date.Sub(now).Hours()/24 + 0.5),
+		Amount:        reminder.Amount.String(),
+		Currency:      reminder.Amount.Currency,
+		BillingPeriod: string(reminder.Subscription.BillingPeriod),
+	}
+	if data.BillingPeriod == "" {
+		data.BillingPeriod = string(model.BillingPeriodMonthly)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(subject.String()), body.String(), nil
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type auditRow struct {
+	ID             uuid.UUID `db:"id"`
+	SubscriptionID uuid.UUID `db:"subscription_id"`
+	Actor          string    `db:"actor"`
+	Action         string    `db:"action"`
+	Before         []byte    `db:"before"`
+	After          []byte    `db:"after"`
+	Details        []byte    `db:"details"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (row auditRow) toModel() (*model.AuditEntry, error) {
+	before, err := unmarshalSnapshot(row.Before)
+	if err != nil {
+		return nil, err
+	}
+	after, err := unmarshalSnapshot(row.After)
+	if err != nil {
+		return nil, err
+	}
+	entry := &model.AuditEntry{
+		ID:             row.ID,
+		SubscriptionID: row.SubscriptionID,
+		Actor:          row.Actor,
+		Action:         model.AuditAction(row.Action),
+		Before:         before,
+		After:          after,
+		CreatedAt:      row.CreatedAt,
+	}
+	if err := unmarshalAuditDetails(row.Details, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+type auditRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository(db *sqlx.DB) port.AuditRepository {
+	return &auditRepo{db: db}
+}
+
+const auditColumns = `id, subscription_id, actor, action, before, after, details, created_at`
+
+func (r *auditRepo) Append(ctx context.Context, entries ...*model.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	rows := make([]auditRow, 0, len(entries))
+	for _, e := range entries {
+		before, err := marshalSnapshot(e.Before)
+		if err != nil {
+			return err
+		}
+		after, err := marshalSnapshot(e.After)
+		if err != nil {
+			return err
+		}
+		details, err := marshalAuditDetails(e)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, auditRow{
+			ID:             e.ID,
+			SubscriptionID: e.SubscriptionID,
+			Actor:          e.Actor,
+			Action:         string(e.Action),
+			Before:         before,
+			After:          after,
+			Details:        details,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+
+	query := `
+		INSERT INTO subscription_audit_log (` + auditColumns + `)
+		VALUES (:id, :subscription_id, :actor, :action, :before, :after, :details, :created_at)
+	`
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, rows)
+	return err
+}
+
+func (r *auditRepo) ListBySubscription(ctx context.Context, subscriptionID uuid.UUID) ([]*model.AuditEntry, error) {
+	query := `
+		SELECT ` + auditColumns + `
+		FROM subscription_audit_log
+		WHERE subscription_id = $1
+		ORDER BY created_at, id
+	`
+	return r.selectEntries(ctx, query, subscriptionID)
+}
+
+func (r *auditRepo) Query(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	query := `
+		SELECT ` + auditColumns + `
+		FROM subscription_audit_log
+		WHERE true
+	`
+
+	var args []interface{}
+	if filter.SubscriptionID != nil {
+		args = append(args, *filter.SubscriptionID)
+		query += " AND subscription_id = $" + strconv.Itoa(len(args))
+	}
+	if filter.Actor != nil {
+		args = append(args, *filter.Actor)
+		query += " AND actor = $" + strconv.Itoa(len(args))
+	}
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		query += " AND created_at >= $" + strconv.Itoa(len(args))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		query += " AND created_at < $" + strconv.Itoa(len(args))
+	}
+
+	query += " ORDER BY created_at DESC, id"
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += " LIMIT $" + strconv.Itoa(len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += " OFFSET $" + strconv.Itoa(len(args))
+	}
+
+	return r.selectEntries(ctx, query, args...)
+}
+
+func (r *auditRepo) selectEntries(ctx context.Context, query string, args ...interface{}) ([]*model.AuditEntry, error) {
+	var rows []auditRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	entries := make([]*model.AuditEntry, 0, len(rows))
+	for _, row := range rows {
+		entry, err := row.toModel()
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type chatLinkRepo struct {
+	db *sqlx.DB
+}
+
+func NewChatLinkRepository(db *sqlx.DB) port.ChatLinkRepository {
+	return &chatLinkRepo{db: db}
+}
+
+func (r *chatLinkRepo) CreateLinkToken(ctx context.Context, token string, userID uuid.UUID, expiresAt time.Time) error {
+	query := `
+		INSERT INTO telegram_link_tokens (token, user_id, expires_at)
+		VALUES ($1, $2, $3)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, token, userID, expiresAt)
+	return err
+}
+
+func (r *chatLinkRepo) ConsumeLinkToken(ctx context.Context, token string, now time.Time) (*uuid.UUID, error) {
+	var userID uuid.UUID
+
+	// Просроченные токены удаляются заодно с использованным
+	query := `
+		WITH consumed AS (
+			DELETE FROM telegram_link_tokens
+			WHERE token = $1 OR expires_at <= $2
+			RETURNING token, user_id, expires_at
+		)
+		SELECT user_id FROM consumed
+		WHERE token = $1 AND expires_at > $2
+	`
+
+	err := conn(ctx, r.db).GetContext(ctx, &userID, query, token, now)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &userID, nil
+}
+
+func (r *chatLinkRepo) SaveLink(ctx context.Context, link *model.ChatLink) error {
+	query := `
+		INSERT INTO telegram_chats (chat_id, user_id, linked_at)
+		VALUES (:chat_id, :user_id, :linked_at)
+		ON CONFLICT (chat_id) DO UPDATE
+		SET user_id = EXCLUDED.user_id,
+			linked_at = EXCLUDED.linked_at
+	`
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, link)
+	return err
+}
+
+func (r *chatLinkRepo) DeleteLink(ctx context.Context, chatID int64) (bool, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM telegram_chats WHERE chat_id = $1`, chatID)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *chatLinkRepo) GetLink(ctx context.Context, chatID int64) (*model.ChatLink, error) {
+	var link model.ChatLink
+
+	query := `
+		SELECT chat_id, user_id, linked_at
+		FROM telegram_chats
+		WHERE chat_id = $1
+	`
+
+	err := conn(ctx, r.db).GetContext(ctx, &link, query, chatID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &link, err
+}
+
+func (r *chatLinkRepo) ListLinksByUser(ctx context.Context, userID uuid.UUID) ([]*model.ChatLink, error) {
+	var links []*model.ChatLink
+
+	query := `
+		SELECT chat_id, user_id, linked_at
+		FROM telegram_chats
+		WHERE user_id = $1
+		ORDER BY linked_at
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &links, query, userID)
+	return links, err
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// eventData is the stored payload of model.Event, shared by the outbox and webhook deliveries.
+type eventData struct {
+	Subscription *subscriptionSnapshot `json:"subscription,omitempty"`
+	PriceChange  *priceChangeSnapshot  `json:"price_change,omitempty"`
+	RenewsAt     *time.Time            `json:"renews_at,omitempty"`
+}
+
+type priceChangeSnapshot struct {
+	ID            uuid.UUID `json:"id"`
+	Price         int64     `json:"price"`
+	Currency      string    `json:"currency"`
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+func marshalEventData(event model.Event) ([]byte, error) {
+	data := eventData{
+		Subscription: toSnapshot(event.Subscription),
+		RenewsAt:     event.RenewsAt,
+	}
+	if c := event.PriceChange; c != nil {
+		data.PriceChange = &priceChangeSnapshot{
+			ID:            c.ID,
+			Price:         c.Price.Amount,
+			Currency:      c.Price.Currency,
+			EffectiveFrom: c.EffectiveFrom,
+		}
+	}
+	return json.Marshal(data)
+}
+
+func unmarshalEvent(id uuid.UUID, eventType string, occurredAt time.Time, data []byte) (model.Event, error) {
+	var d eventData
+	if err := json.Unmarshal(data, &d); err != nil {
+		return model.Event{}, err
+	}
+	event := model.Event{
+		ID:           id,
+		Type:         model.EventType(eventType),
+		OccurredAt:   occurredAt,
+		Subscription: d.Subscription.toModel(),
+		RenewsAt:     d.RenewsAt,
+	}
+	if c := d.PriceChange; c != nil {
+		event.PriceChange = &model.PriceChange{
+			ID:            c.ID,
+			Price:         model.Money{Amount: c.Price, Currency: c.Currency},
+			EffectiveFrom: c.EffectiveFrom,
+		}
+		if event.Subscription != nil {
+			event.PriceChange.SubscriptionID = event.Subscription.ID
+		}
+	}
+	return event, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+type idempotencyRepo struct {
+	db *sqlx.DB
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) port.IdempotencyRepository {
+	return &idempotencyRepo{db: db}
+}
+
+func (r *idempotencyRepo) Reserve(ctx context.Context, rec *model.IdempotencyRecord) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (scope, key, request_hash, created_at, expires_at, locked_until, lease_token)
+		VALUES (:scope, :key, :request_hash, :created_at, :expires_at, :locked_until, :lease_token)
+		ON CONFLICT (scope, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			status_code = NULL,
+			content_type = NULL,
+			response = NULL,
+			created_at = EXCLUDED.created_at,
+			expires_at = EXCLUDED.expires_at,
+			locked_until = EXCLUDED.locked_until,
+			lease_token = EXCLUDED.lease_token
+		WHERE idempotency_keys.expires_at < EXCLUDED.created_at
+		   OR (idempotency_keys.status_code IS NULL
+		       AND idempotency_keys.locked_until < EXCLUDED.created_at
+		       AND idempotency_keys.request_hash = EXCLUDED.request_hash)
+	`
+
+	res, err := conn(ctx, r.db).NamedExecContext(ctx, query, rec)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *idempotencyRepo) Get(ctx context.Context, scope, key string) (*model.IdempotencyRecord, error) {
+	var rec model.IdempotencyRecord
+
+	query := `
+		SELECT scope, key, request_hash, COALESCE(status_code, 0) AS status_code,
+		       COALESCE(content_type, '') AS content_type, response, created_at, expires_at,
+		       COALESCE(locked_until, created_at) AS locked_until,
+		       COALESCE(lease_token, '') AS lease_token
+		FROM idempotency_keys
+		WHERE scope = $1 AND key = $2
+	`
+
+	err := conn(ctx, r.db).GetContext(ctx, &rec, query, scope, key)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &rec, err
+}
+
+func (r *idempotencyRepo) Complete(ctx context.Context, rec *model.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = :status_code,
+			content_type = :content_type,
+			response = :response,
+			locked_until = NULL,
+			lease_token = NULL
+		WHERE scope = :scope AND key = :key AND lease_token = :lease_token AND status_code IS NULL
+	`
+
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, rec)
+	return err
+}
+
+func (r *idempotencyRepo) Delete(ctx context.Context, rec *model.IdempotencyRecord) error {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE scope = :scope AND key = :key AND lease_token = :lease_token AND status_code IS NULL
+	`
+
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, rec)
+	return err
+}
+
+func (r *idempotencyRepo) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at < $1`, now)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/jmoiron/sqlx"
+)
+
+const jobRunColumns = `id, job, trigger, status, scheduled_at, started_at, finished_at, result, error`
+
+type jobRunRepo struct {
+	db *sqlx.DB
+}
+
+func NewJobRunRepository(db *sqlx.DB) port.JobRunRepository {
+	return &jobRunRepo{db: db}
+}
+
+func (r *jobRunRepo) Create(ctx context.Context, run *model.JobRun) error {
+	query := `
+		INSERT INTO job_runs (` + jobRunColumns + `)
+		VALUES (:id, :job, :trigger, :status, :scheduled_at, :started_at, :finished_at, :result, :error)
+	`
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, run)
+	return err
+}
+
+func (r *jobRunRepo) Claim(ctx context.Context, run *model.JobRun) (bool, error) {
+	query := `
+		INSERT INTO job_runs (` + jobRunColumns + `)
+		VALUES (:id, :job, :trigger, :status, :scheduled_at, :started_at, :finished_at, :result, :error)
+		ON CONFLICT (job, scheduled_at) WHERE scheduled_at IS NOT NULL DO NOTHING
+	`
+	res, err := conn(ctx, r.db).NamedExecContext(ctx, query, run)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n == 1, err
+}
+
+func (r *jobRunRepo) Finish(ctx context.Context, run *model.JobRun) error {
+	query := `
+		UPDATE job_runs
+		SET status = :status,
+			finished_at = :finished_at,
+			result = :result,
+			error = :error
+		WHERE id = :id
+	`
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, run)
+	return err
+}
+
+func (r *jobRunRepo) List(ctx context.Context, job string, limit int) ([]*model.JobRun, error) {
+	var runs []*model.JobRun
+
+	query := `
+		SELECT ` + jobRunColumns + `
+		FROM job_runs
+		WHERE job = $1
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &runs, query, job, limit)
+	return runs, err
+}
+
+func (r *jobRunRepo) Latest(ctx context.Context) (map[string]*model.JobRun, error) {
+	var runs []*model.JobRun
+
+	query := `
+		SELECT DISTINCT ON (job) ` + jobRunColumns + `
+		FROM job_runs
+		ORDER BY job, started_at DESC
+	`
+
+	if err := conn(ctx, r.db).SelectContext(ctx, &runs, query); err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]*model.JobRun, len(runs))
+	for _, run := range runs {
+		latest[run.Job] = run
+	}
+	return latest, nil
+}
+
+type jobLocker struct {
+	db *sqlx.DB
+}
+
+// NewJobLocker locks jobs with session-level advisory locks, each held
+// on a dedicated connection for the duration of the run.
+func NewJobLocker(db *sqlx.DB) port.JobLocker {
+	return &jobLocker{db: db}
+}
+
+func (l *jobLocker) TryLock(ctx context.Context, job string) (func(), bool, error) {
+	c, err := l.db.Connx(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := "job:" + job
+	var ok bool
+	if err := c.GetContext(ctx, &ok, `SELECT pg_try_advisory_lock(hashtextextended($1, 0))`, key); err != nil || !ok {
+		c.Close()
+		return nil, false, err
+	}
+
+	unlock := func() {
+		_, err := c.ExecContext(context.Background(), `SELECT pg_advisory_unlock(hashtextextended($1, 0))`, key)
+		if err != nil {
+			// Соединение с неснятой блокировкой нельзя возвращать в пул: закрываем его
+			_ = c.Raw(func(any) error { return driver.ErrBadConn })
+		}
+		c.Close()
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+const preferencesColumns = `user_id, email, enabled, renewal_days_before, trial_days_before, updated_at`
+
+type notificationRepo struct {
+	db *sqlx.DB
+}
+
+func NewNotificationRepository(db *sqlx.DB) port.NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+func (r *notificationRepo) GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferences, error) {
+	var prefs model.NotificationPreferences
+
+	query := `
+		SELECT ` + preferencesColumns + `
+		FROM notification_preferences
+		WHERE user_id = $1
+	`
+
+	err := conn(ctx, r.db).GetContext(ctx, &prefs, query, userID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &prefs, err
+}
+
+func (r *notificationRepo) SavePreferences(ctx context.Context, prefs *model.NotificationPreferences) error {
+	query := `
+		INSERT INTO notification_preferences (` + preferencesColumns + `)
+		VALUES (:user_id, :email, :enabled, :renewal_days_before, :trial_days_before, :updated_at)
+		ON CONFLICT (user_id) DO UPDATE
+		SET email = EXCLUDED.email,
+			enabled = EXCLUDED.enabled,
+			renewal_days_before = EXCLUDED.renewal_days_before,
+			trial_days_before = EXCLUDED.trial_days_before,
+			updated_at = EXCLUDED.updated_at
+	`
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, prefs)
+	return err
+}
+
+func (r *notificationRepo) ListEnabledPreferences(ctx context.Context) ([]*model.NotificationPreferences, error) {
+	var prefs []*model.NotificationPreferences
+
+	query := `
+		SELECT ` + preferencesColumns + `
+		FROM notification_preferences
+		WHERE enabled = true
+		ORDER BY user_id
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &prefs, query)
+	return prefs, err
+}
+
+func (r *notificationRepo) ReserveReminder(ctx context.Context, channel string, userID uuid.UUID, reminder model.Reminder, now time.Time) (bool, error) {
+	query := `
+		INSERT INTO sent_reminders (channel, subscription_id, kind, due_date, user_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT DO NOTHING
+	`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query,
+		channel, reminder.Subscription.ID, string(reminder.Kind), reminder.Date, userID, now)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *notificationRepo) ReleaseReminder(ctx context.Context, channel string, reminder model.Reminder) error {
+	query := `
+		DELETE FROM sent_reminders
+		WHERE channel = $1 AND subscription_id = $2 AND kind = $3 AND due_date = $4 AND status = 'sending'
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		channel, reminder.Subscription.ID, string(reminder.Kind), reminder.Date)
+	return err
+}
+
+func (r *notificationRepo) MarkReminderSent(ctx context.Context, channel string, reminder model.Reminder, at time.Time) error {
+	query := `
+		UPDATE sent_reminders
+		SET status = 'sent', sent_at = $5
+		WHERE channel = $1 AND subscription_id = $2 AND kind = $3 AND due_date = $4
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		channel, reminder.Subscription.ID, string(reminder.Kind), reminder.Date, at)
+	return err
+}
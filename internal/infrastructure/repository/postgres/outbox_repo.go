@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type outboxRow struct {
+	ID         uuid.UUID `db:"id"`
+	EventType  string    `db:"event_type"`
+	EventData  []byte    `db:"event_data"`
+	OccurredAt time.Time `db:"occurred_at"`
+	Attempts   int       `db:"attempts"`
+}
+
+type outboxRepo struct {
+	db *sqlx.DB
+}
+
+func NewOutboxRepository(db *sqlx.DB) port.OutboxRepository {
+	return &outboxRepo{db: db}
+}
+
+func (r *outboxRepo) Append(ctx context.Context, events ...model.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]outboxRow, 0, len(events))
+	for _, event := range events {
+		data, err := marshalEventData(event)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, outboxRow{
+			ID:         event.ID,
+			EventType:  string(event.Type),
+			EventData:  data,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+
+	query := `
+		INSERT INTO outbox_events (id, event_type, event_data, occurred_at)
+		VALUES (:id, :event_type, :event_data, :occurred_at)
+		ON CONFLICT (id) DO NOTHING
+	`
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, rows)
+	return err
+}
+
+func (r *outboxRepo) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]model.OutboxEvent, error) {
+	var rows []outboxRow
+
+	// RETURNING не гарантирует порядок, поэтому сортировка во внешнем запросе
+	query := `
+		WITH claimed AS (
+			UPDATE outbox_events
+			SET locked_until = $2
+			WHERE id IN (
+				SELECT id
+				FROM outbox_events
+				WHERE published_at IS NULL AND failed_at IS NULL
+				  AND (locked_until IS NULL OR locked_until <= $1)
+				  AND (next_attempt_at IS NULL OR next_attempt_at <= $1)
+				ORDER BY seq
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING seq, id, event_type, event_data, occurred_at, attempts
+		)
+		SELECT id, event_type, event_data, occurred_at, attempts
+		FROM claimed
+		ORDER BY seq
+	`
+
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, now, now.Add(lease), limit); err != nil {
+		return nil, err
+	}
+
+	events := make([]model.OutboxEvent, 0, len(rows))
+	for _, row := range rows {
+		event, err := unmarshalEvent(row.ID, row.EventType, row.OccurredAt, row.EventData)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, model.OutboxEvent{Event: event, Attempts: row.Attempts})
+	}
+	return events, nil
+}
+
+func (r *outboxRepo) MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET published_at = $2, locked_until = NULL
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, at)
+	return err
+}
+
+func (r *outboxRepo) MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $2, locked_until = NULL, next_attempt_at = $3
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, reason, retryAt)
+	return err
+}
+
+func (r *outboxRepo) MarkDead(ctx context.Context, id uuid.UUID, reason string, at time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, last_error = $2, locked_until = NULL, failed_at = $3
+		WHERE id = $1
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, reason, at)
+	return err
+}
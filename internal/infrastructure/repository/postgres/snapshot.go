@@ -0,0 +1,190 @@
+package postgres
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// subscriptionSnapshot is the JSON form of model.Subscription stored in the
+// audit log and in queued events. Money is kept in minor units so snapshots
+// don't lose the currency.
+type subscriptionSnapshot struct {
+	ID               uuid.UUID                `json:"id"`
+	ServiceName      string                   `json:"service_name"`
+	Price            int64                    `json:"price"`
+	Currency         string                   `json:"currency"`
+	UserID           uuid.UUID                `json:"user_id"`
+	StartDate        time.Time                `json:"start_date"`
+	EndDate          *time.Time               `json:"end_date,omitempty"`
+	BillingPeriod    model.BillingPeriod      `json:"billing_period"`
+	TrialEndDate     *time.Time               `json:"trial_end_date,omitempty"`
+	TaxRate          int                      `json:"tax_rate"`
+	PriceIncludesTax bool                     `json:"price_includes_tax"`
+	IsDeleted        bool                     `json:"is_deleted"`
+	Status           model.SubscriptionStatus `json:"status,omitempty"` // нет в записях до появления статусов
+	CancelledAt      *time.Time               `json:"cancelled_at,omitempty"`
+	CancelledEndDate *time.Time               `json:"cancelled_end_date,omitempty"`
+	EndedAt          *time.Time               `json:"ended_at,omitempty"`
+	DeletionReason   model.DeletionReason     `json:"deletion_reason,omitempty"`
+}
+
+func toSnapshot(sub *model.Subscription) *subscriptionSnapshot {
+	if sub == nil {
+		return nil
+	}
+	return &subscriptionSnapshot{
+		ID:               sub.ID,
+		ServiceName:      sub.ServiceName,
+		Price:            sub.Price.Amount,
+		Currency:         sub.Price.Currency,
+		UserID:           sub.UserID,
+		StartDate:        sub.StartDate,
+		EndDate:          sub.EndDate,
+		BillingPeriod:    sub.BillingPeriod,
+		TrialEndDate:     sub.TrialEndDate,
+		TaxRate:          sub.TaxRate,
+		PriceIncludesTax: sub.PriceIncludesTax,
+		IsDeleted:        sub.IsDeleted,
+		Status:           sub.Status,
+		CancelledAt:      sub.CancelledAt,
+		CancelledEndDate: sub.CancelledEndDate,
+		EndedAt:          sub.EndedAt,
+		DeletionReason:   sub.DeletionReason,
+	}
+}
+
+func marshalSnapshot(sub *model.Subscription) ([]byte, error) {
+	if sub == nil {
+		return nil, nil
+	}
+	return json.Marshal(toSnapshot(sub))
+}
+
+func unmarshalSnapshot(data []byte) (*model.Subscription, error) {
+	if data == nil {
+		return nil, nil
+	}
+	var s subscriptionSnapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return s.toModel(), nil
+}
+
+func (s *subscriptionSnapshot) toModel() *model.Subscription {
+	if s == nil {
+		return nil
+	}
+	return &model.Subscription{
+		ID:               s.ID,
+		ServiceName:      s.ServiceName,
+		Price:            model.Money{Amount: s.Price, Currency: s.Currency},
+		UserID:           s.UserID,
+		StartDate:        s.StartDate,
+		EndDate:          s.EndDate,
+		BillingPeriod:    s.BillingPeriod,
+		TrialEndDate:     s.TrialEndDate,
+		TaxRate:          s.TaxRate,
+		PriceIncludesTax: s.PriceIncludesTax,
+		IsDeleted:        s.IsDeleted,
+		Status:           s.Status,
+		CancelledAt:      s.CancelledAt,
+		CancelledEndDate: s.CancelledEndDate,
+		EndedAt:          s.EndedAt,
+		DeletionReason:   s.DeletionReason,
+	}
+}
+
+// auditDetails is the JSON form of related records changed along with the subscription.
+type auditDetails struct {
+	PriceChange *priceChangeSnapshot `json:"price_change,omitempty"`
+	Members     []memberSnapshot     `json:"members,omitempty"`
+	Discount    *discountSnapshot    `json:"discount,omitempty"`
+}
+
+type memberSnapshot struct {
+	UserID    uuid.UUID       `json:"user_id"`
+	SplitType model.SplitType `json:"split_type"`
+	Value     int64           `json:"value"`
+}
+
+type discountSnapshot struct {
+	ID        uuid.UUID          `json:"id"`
+	Kind      model.DiscountKind `json:"kind"`
+	Value     int64              `json:"value"`
+	Code      string             `json:"code,omitempty"`
+	StartDate time.Time          `json:"start_date"`
+	EndDate   *time.Time         `json:"end_date,omitempty"`
+}
+
+func marshalAuditDetails(entry *model.AuditEntry) ([]byte, error) {
+	if entry.PriceChange == nil && entry.Members == nil && entry.Discount == nil {
+		return nil, nil
+	}
+
+	var details auditDetails
+	if c := entry.PriceChange; c != nil {
+		details.PriceChange = &priceChangeSnapshot{
+			ID:            c.ID,
+			Price:         c.Price.Amount,
+			Currency:      c.Price.Currency,
+			EffectiveFrom: c.EffectiveFrom,
+		}
+	}
+	for _, m := range entry.Members {
+		details.Members = append(details.Members, memberSnapshot{UserID: m.UserID, SplitType: m.SplitType, Value: m.Value})
+	}
+	if d := entry.Discount; d != nil {
+		details.Discount = &discountSnapshot{
+			ID:        d.ID,
+			Kind:      d.Kind,
+			Value:     d.Value,
+			Code:      d.Code,
+			StartDate: d.StartDate,
+			EndDate:   d.EndDate,
+		}
+	}
+	return json.Marshal(details)
+}
+
+func unmarshalAuditDetails(data []byte, entry *model.AuditEntry) error {
+	if data == nil {
+		return nil
+	}
+	var details auditDetails
+	if err := json.Unmarshal(data, &details); err != nil {
+		return err
+	}
+
+	if c := details.PriceChange; c != nil {
+		entry.PriceChange = &model.PriceChange{
+			ID:             c.ID,
+			SubscriptionID: entry.SubscriptionID,
+			Price:          model.Money{Amount: c.Price, Currency: c.Currency},
+			EffectiveFrom:  c.EffectiveFrom,
+		}
+	}
+	for _, m := range details.Members {
+		entry.Members = append(entry.Members, &model.SubscriptionMember{
+			SubscriptionID: entry.SubscriptionID,
+			UserID:         m.UserID,
+			SplitType:      m.SplitType,
+			Value:          m.Value,
+		})
+	}
+	if d := details.Discount; d != nil {
+		entry.Discount = &model.Discount{
+			ID:             d.ID,
+			SubscriptionID: entry.SubscriptionID,
+			Kind:           d.Kind,
+			Value:          d.Value,
+			Code:           d.Code,
+			StartDate:      d.StartDate,
+			EndDate:        d.EndDate,
+		}
+	}
+	return nil
+}
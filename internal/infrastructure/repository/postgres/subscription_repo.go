@@ -3,14 +3,20 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/Babushkin05/subscription-organizer/internal/application/port"
 	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
+// Money columns are aliased with dotted names so sqlx maps them onto model.Money fields.
+const subscriptionColumns = `id, service_name, price AS "price.amount", currency AS "price.currency", user_id, start_date, end_date, billing_period, trial_end_date, tax_rate, price_includes_tax, is_deleted, status, cancelled_at, cancelled_end_date, ended_at, deletion_reason, deleted_at`
+
 type subscriptionRepo struct {
 	db *sqlx.DB
 }
@@ -19,103 +25,541 @@ func NewSubscriptionRepository(db *sqlx.DB) port.SubscriptionRepository {
 	return &subscriptionRepo{db: db}
 }
 
+const insertSubscriptionQuery = `
+	INSERT INTO subscriptions 
+	(id, service_name, price, currency, user_id, start_date, end_date, billing_period, trial_end_date, tax_rate, price_includes_tax, is_deleted, status, cancelled_at, cancelled_end_date, ended_at, deletion_reason)
+	VALUES (:id, :service_name, :price.amount, :price.currency, :user_id, :start_date, :end_date, :billing_period, :trial_end_date, :tax_rate, :price_includes_tax, :is_deleted, :status, :cancelled_at, :cancelled_end_date, :ended_at, :deletion_reason)
+`
+
 func (r *subscriptionRepo) Create(ctx context.Context, sub *model.Subscription) error {
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, insertSubscriptionQuery, sub)
+	return err
+}
+
+func (r *subscriptionRepo) ExecBatch(ctx context.Context, ops []model.BatchOperation, atomic bool) ([]error, error) {
+	errs := make([]error, len(ops))
+	err := inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		// Пакет целиком под точкой сохранения: атомарный откат не должен
+		// затрагивать остальную внешнюю транзакцию, если она есть
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT batch"); err != nil {
+			return err
+		}
+
+		for i, op := range ops {
+			// В режиме best effort каждая операция под своей точкой сохранения,
+			// чтобы ошибка одной не прерывала всю транзакцию
+			if !atomic {
+				if _, err := tx.ExecContext(ctx, "SAVEPOINT batch_item"); err != nil {
+					return err
+				}
+			}
+
+			errs[i] = execBatchOperation(ctx, tx, op)
+
+			switch {
+			case errs[i] != nil && atomic:
+				_, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch")
+				return err
+			case errs[i] != nil:
+				if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT batch_item"); err != nil {
+					return err
+				}
+			case !atomic:
+				if _, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT batch_item"); err != nil {
+					return err
+				}
+			}
+		}
+
+		_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT batch")
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return errs, nil
+}
+
+func execBatchOperation(ctx context.Context, tx *sqlx.Tx, op model.BatchOperation) error {
+	var (
+		res sql.Result
+		err error
+	)
+	switch op.Action {
+	case model.BatchCreate:
+		_, err = tx.NamedExecContext(ctx, insertSubscriptionQuery, op.Subscription)
+		return err
+	case model.BatchUpdate:
+		res, err = tx.NamedExecContext(ctx, updateSubscriptionQuery+" AND is_deleted = false", op.Subscription)
+	case model.BatchDelete:
+		res, err = tx.ExecContext(ctx, `UPDATE subscriptions SET is_deleted = true, deletion_reason = $2 WHERE id = $1 AND is_deleted = false`, op.ID, op.Reason)
+	default:
+		return fmt.Errorf("unknown batch action %q", op.Action)
+	}
+	if err != nil {
+		return err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return model.ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+func (r *subscriptionRepo) GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	var sub model.Subscription
+
 	query := `
-		INSERT INTO subscriptions 
-		(id, service_name, price, user_id, start_date, end_date, is_deleted)
-		VALUES (:id, :service_name, :price, :user_id, :start_date, :end_date, :is_deleted)
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE id = $1
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, sub)
-	return err
+	err := conn(ctx, r.db).GetContext(ctx, &sub, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return &sub, err
 }
 
-func (r *subscriptionRepo) GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+func (r *subscriptionRepo) GetByIDForUpdate(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
 	var sub model.Subscription
 
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, is_deleted
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions
 		WHERE id = $1
+		FOR UPDATE
 	`
 
-	err := r.db.GetContext(ctx, &sub, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &sub, query, id)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
 	return &sub, err
 }
 
-func (r *subscriptionRepo) Update(ctx context.Context, sub *model.Subscription) error {
+func (r *subscriptionRepo) GetByIDsForUpdate(ctx context.Context, ids []uuid.UUID) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+	if len(ids) == 0 {
+		return subs, nil
+	}
+
 	query := `
-		UPDATE subscriptions
-		SET service_name = :service_name,
-			price = :price,
-			user_id = :user_id,
-			start_date = :start_date,
-			end_date = :end_date,
-			is_deleted = :is_deleted
-		WHERE id = :id
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE id = ANY($1)
+		ORDER BY id
+		FOR UPDATE
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, sub)
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, pq.Array(ids))
+	return subs, err
+}
+
+const updateSubscriptionQuery = `
+	UPDATE subscriptions
+	SET service_name = :service_name,
+		price = :price.amount,
+		currency = :price.currency,
+		user_id = :user_id,
+		start_date = :start_date,
+		end_date = :end_date,
+		billing_period = :billing_period,
+		trial_end_date = :trial_end_date,
+		tax_rate = :tax_rate,
+		price_includes_tax = :price_includes_tax,
+		is_deleted = :is_deleted,
+		status = :status,
+		cancelled_at = :cancelled_at,
+		cancelled_end_date = :cancelled_end_date,
+		ended_at = :ended_at,
+		deletion_reason = :deletion_reason
+	WHERE id = :id`
+
+func (r *subscriptionRepo) Update(ctx context.Context, sub *model.Subscription) error {
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, updateSubscriptionQuery, sub)
 	return err
 }
 
 func (r *subscriptionRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	query := `
 		UPDATE subscriptions
-		SET is_deleted = true
+		SET is_deleted = true, deletion_reason = $2
 		WHERE id = $1
 	`
-	_, err := r.db.ExecContext(ctx, query, id)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, id, model.DeletionMistake)
 	return err
 }
 
+func (r *subscriptionRepo) PurgeDeleted(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM subscriptions
+		WHERE is_deleted = true AND deletion_reason = $1 AND deleted_at < $2
+	`
+	res, err := conn(ctx, r.db).ExecContext(ctx, query, model.DeletionMistake, before)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 func (r *subscriptionRepo) List(ctx context.Context) ([]*model.Subscription, error) {
 	var subs []*model.Subscription
 
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, is_deleted
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions
 		WHERE is_deleted = false
 	`
 
-	err := r.db.SelectContext(ctx, &subs, query)
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query)
 	return subs, err
 }
 
+func (r *subscriptionRepo) ListByStatus(ctx context.Context, statuses []model.SubscriptionStatus) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+
+	names := make([]string, 0, len(statuses))
+	for _, st := range statuses {
+		names = append(names, string(st))
+	}
+
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE is_deleted = false AND status = ANY($1)
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, pq.Array(names))
+	return subs, err
+}
+
+func (r *subscriptionRepo) ListByUsers(ctx context.Context, userIDs []uuid.UUID) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE is_deleted = false AND user_id = ANY($1)
+		ORDER BY start_date, service_name, id
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, pq.Array(userIDs))
+	return subs, err
+}
+
+func (r *subscriptionRepo) ListPageByUsers(ctx context.Context, userIDs []uuid.UUID, limit, offset int) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM (
+			SELECT *, row_number() OVER (PARTITION BY user_id ORDER BY start_date, service_name, id) AS position
+			FROM subscriptions
+			WHERE is_deleted = false AND user_id = ANY($1)
+		) s
+		WHERE position > $2 AND position <= $2 + $3
+		ORDER BY user_id, position
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, pq.Array(userIDs), offset, limit)
+	return subs, err
+}
+
+func (r *subscriptionRepo) ListPage(ctx context.Context, userID *uuid.UUID, serviceName *string, status *model.SubscriptionStatus, limit, offset int) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+
+	query, args := listQuery(userID, serviceName, status)
+	args = append(args, limit, offset)
+	query += " ORDER BY start_date, service_name, id LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, args...)
+	return subs, err
+}
+
+func (r *subscriptionRepo) Stream(ctx context.Context, userID *uuid.UUID, serviceName *string, fn func(*model.Subscription) error) error {
+	query, args := listQuery(userID, serviceName, nil)
+	rows, err := conn(ctx, r.db).QueryxContext(ctx, query+" ORDER BY start_date, service_name, id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sub model.Subscription
+		if err := rows.StructScan(&sub); err != nil {
+			return err
+		}
+		if err := fn(&sub); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func listQuery(userID *uuid.UUID, serviceName *string, status *model.SubscriptionStatus) (string, []interface{}) {
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE is_deleted = false
+	`
+
+	var args []interface{}
+	if userID != nil {
+		args = append(args, *userID)
+		query += " AND user_id = $" + strconv.Itoa(len(args))
+	}
+	if serviceName != nil {
+		args = append(args, *serviceName)
+		query += " AND service_name = $" + strconv.Itoa(len(args))
+	}
+	if status != nil {
+		args = append(args, string(*status))
+		query += " AND status = $" + strconv.Itoa(len(args))
+	}
+	return query, args
+}
+
 func (r *subscriptionRepo) GetByFilter(
 	ctx context.Context,
 	userID *uuid.UUID,
 	serviceName *string,
+	currency *string,
 	from time.Time,
 	to time.Time,
+	includeDeleted bool,
 ) ([]*model.Subscription, error) {
 	var subs []*model.Subscription
 
+	query, args := filterQuery(userID, serviceName, currency, from, to, includeDeleted)
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, args...)
+	return subs, err
+}
+
+func (r *subscriptionRepo) GetByFilterForUsers(
+	ctx context.Context,
+	userIDs []uuid.UUID,
+	serviceName *string,
+	currency *string,
+	from time.Time,
+	to time.Time,
+	includeDeleted bool,
+) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+
+	query, args := filterQuery(nil, serviceName, currency, from, to, includeDeleted)
+	args = append(args, pq.Array(userIDs))
+	n := strconv.Itoa(len(args))
+	query += " AND (user_id = ANY($" + n + ") OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id = ANY($" + n + ")))"
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, args...)
+	return subs, err
+}
+
+func (r *subscriptionRepo) ListByFilter(
+	ctx context.Context,
+	userID *uuid.UUID,
+	serviceName *string,
+	currency *string,
+	from time.Time,
+	to time.Time,
+	includeDeleted bool,
+	limit int,
+	offset int,
+) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+
+	query, args := filterQuery(userID, serviceName, currency, from, to, includeDeleted)
+	args = append(args, limit, offset)
+	query += " ORDER BY start_date, service_name, id LIMIT $" + strconv.Itoa(len(args)-1) + " OFFSET $" + strconv.Itoa(len(args))
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, args...)
+	return subs, err
+}
+
+func (r *subscriptionRepo) StreamByFilter(
+	ctx context.Context,
+	userID *uuid.UUID,
+	serviceName *string,
+	currency *string,
+	from time.Time,
+	to time.Time,
+	includeDeleted bool,
+	fn func(*model.Subscription) error,
+) error {
+	query, args := filterQuery(userID, serviceName, currency, from, to, includeDeleted)
+	rows, err := conn(ctx, r.db).QueryxContext(ctx, query+" ORDER BY start_date, service_name, id", args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sub model.Subscription
+		if err := rows.StructScan(&sub); err != nil {
+			return err
+		}
+		if err := fn(&sub); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func filterQuery(userID *uuid.UUID, serviceName, currency *string, from, to time.Time, includeDeleted bool) (string, []interface{}) {
+	// Закончившиеся подписки, удалённые до начала периода, в него уже не попадают
 	query := `
-		SELECT id, service_name, price, user_id, start_date, end_date, is_deleted
+		SELECT ` + subscriptionColumns + `
 		FROM subscriptions
-		WHERE is_deleted = false
+		WHERE (is_deleted = false OR ($3 AND deletion_reason = 'ended' AND deleted_at >= $2))
 		  AND start_date <= $1
 		  AND (end_date IS NULL OR end_date >= $2)
 	`
 
-	args := []interface{}{to, from}
+	args := []interface{}{to, from, includeDeleted}
 
 	if userID != nil {
-		query += " AND user_id = $3"
 		args = append(args, *userID)
-		if serviceName != nil {
-			query += " AND service_name = $4"
-			args = append(args, *serviceName)
-		}
-	} else if serviceName != nil {
-		query += " AND service_name = $3"
+		n := strconv.Itoa(len(args))
+		query += " AND (user_id = $" + n + " OR id IN (SELECT subscription_id FROM subscription_members WHERE user_id = $" + n + "))"
+	}
+	if serviceName != nil {
 		args = append(args, *serviceName)
+		query += " AND service_name = $" + strconv.Itoa(len(args))
 	}
+	if currency != nil {
+		args = append(args, *currency)
+		query += " AND currency = $" + strconv.Itoa(len(args))
+	}
+	return query, args
+}
+
+func (r *subscriptionRepo) ListByUserAndService(ctx context.Context, userID uuid.UUID, serviceName string) ([]*model.Subscription, error) {
+	var subs []*model.Subscription
+
+	query := `
+		SELECT ` + subscriptionColumns + `
+		FROM subscriptions
+		WHERE is_deleted = false
+		  AND user_id = $1
+		  AND lower(trim(service_name)) = lower(trim($2))
+	`
 
-	err := r.db.SelectContext(ctx, &subs, query, args...)
+	err := conn(ctx, r.db).SelectContext(ctx, &subs, query, userID, serviceName)
 	return subs, err
 }
+
+func (r *subscriptionRepo) LockUser(ctx context.Context, userID uuid.UUID) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtextextended($1, 0))`, "subscriptions:"+userID.String())
+	return err
+}
+
+func (r *subscriptionRepo) LowestPrices(ctx context.Context, period model.BillingPeriod) ([]model.ServicePrice, error) {
+	var prices []model.ServicePrice
+
+	query := `
+		SELECT lower(trim(service_name)) AS service_name, billing_period,
+		       MIN(price) AS "price.amount", currency AS "price.currency"
+		FROM subscriptions
+		WHERE is_deleted = false
+		  AND billing_period = $1
+		GROUP BY lower(trim(service_name)), billing_period, currency
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &prices, query, period)
+	return prices, err
+}
+
+func (r *subscriptionRepo) CreatePriceChange(ctx context.Context, change *model.PriceChange) error {
+	query := `
+		INSERT INTO subscription_price_changes
+		(id, subscription_id, price, effective_from)
+		VALUES (:id, :subscription_id, :price.amount, :effective_from)
+		ON CONFLICT (subscription_id, effective_from)
+		DO UPDATE SET price = EXCLUDED.price
+	`
+
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, change)
+	return err
+}
+
+func (r *subscriptionRepo) ListPriceChanges(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.PriceChange, error) {
+	var changes []*model.PriceChange
+
+	query := `
+		SELECT pc.id, pc.subscription_id, pc.price AS "price.amount", s.currency AS "price.currency", pc.effective_from
+		FROM subscription_price_changes pc
+		JOIN subscriptions s ON s.id = pc.subscription_id
+		WHERE pc.subscription_id = ANY($1)
+		ORDER BY pc.subscription_id, pc.effective_from
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &changes, query, pq.Array(subscriptionIDs))
+	return changes, err
+}
+
+func (r *subscriptionRepo) ReplaceMembers(ctx context.Context, subscriptionID uuid.UUID, members []*model.SubscriptionMember) error {
+	return inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM subscription_members WHERE subscription_id = $1`, subscriptionID); err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO subscription_members
+			(subscription_id, user_id, split_type, value)
+			VALUES (:subscription_id, :user_id, :split_type, :value)
+		`
+		for _, m := range members {
+			if _, err := tx.NamedExecContext(ctx, query, m); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *subscriptionRepo) ListMembers(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.SubscriptionMember, error) {
+	var members []*model.SubscriptionMember
+
+	query := `
+		SELECT subscription_id, user_id, split_type, value
+		FROM subscription_members
+		WHERE subscription_id = ANY($1)
+		ORDER BY subscription_id, user_id
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &members, query, pq.Array(subscriptionIDs))
+	return members, err
+}
+
+func (r *subscriptionRepo) CreateDiscount(ctx context.Context, discount *model.Discount) error {
+	query := `
+		INSERT INTO subscription_discounts
+		(id, subscription_id, kind, value, code, start_date, end_date)
+		VALUES (:id, :subscription_id, :kind, :value, :code, :start_date, :end_date)
+	`
+
+	_, err := conn(ctx, r.db).NamedExecContext(ctx, query, discount)
+	return err
+}
+
+func (r *subscriptionRepo) ListDiscounts(ctx context.Context, subscriptionIDs []uuid.UUID) ([]*model.Discount, error) {
+	var discounts []*model.Discount
+
+	query := `
+		SELECT id, subscription_id, kind, value, code, start_date, end_date
+		FROM subscription_discounts
+		WHERE subscription_id = ANY($1)
+		ORDER BY subscription_id, start_date
+	`
+
+	err := conn(ctx, r.db).SelectContext(ctx, &discounts, query, pq.Array(subscriptionIDs))
+	return discounts, err
+}
+
+func (r *subscriptionRepo) DeleteDiscount(ctx context.Context, id uuid.UUID) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM subscription_discounts WHERE id = $1`, id)
+	return err
+}
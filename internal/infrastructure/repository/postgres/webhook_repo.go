@@ -0,0 +1,291 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type endpointRow struct {
+	ID         uuid.UUID      `db:"id"`
+	URL        string         `db:"url"`
+	Secret     string         `db:"secret"`
+	EventTypes pq.StringArray `db:"event_types"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (row endpointRow) toModel() *model.WebhookEndpoint {
+	types := make([]model.EventType, 0, len(row.EventTypes))
+	for _, t := range row.EventTypes {
+		types = append(types, model.EventType(t))
+	}
+	return &model.WebhookEndpoint{
+		ID:         row.ID,
+		URL:        row.URL,
+		Secret:     row.Secret,
+		EventTypes: types,
+		CreatedAt:  row.CreatedAt,
+	}
+}
+
+const deliveryColumns = `id, endpoint_id, event_id, event_type, event_data, occurred_at, status, attempts, next_attempt_at, created_at, updated_at`
+
+type deliveryRow struct {
+	ID            uuid.UUID `db:"id"`
+	EndpointID    uuid.UUID `db:"endpoint_id"`
+	EventID       uuid.UUID `db:"event_id"`
+	EventType     string    `db:"event_type"`
+	EventData     []byte    `db:"event_data"`
+	OccurredAt    time.Time `db:"occurred_at"`
+	Status        string    `db:"status"`
+	Attempts      int       `db:"attempts"`
+	NextAttemptAt time.Time `db:"next_attempt_at"`
+	CreatedAt     time.Time `db:"created_at"`
+	UpdatedAt     time.Time `db:"updated_at"`
+}
+
+func (row deliveryRow) toModel() (*model.WebhookDelivery, error) {
+	event, err := unmarshalEvent(row.EventID, row.EventType, row.OccurredAt, row.EventData)
+	if err != nil {
+		return nil, err
+	}
+	return &model.WebhookDelivery{
+		ID:            row.ID,
+		EndpointID:    row.EndpointID,
+		Event:         event,
+		Status:        model.DeliveryStatus(row.Status),
+		Attempts:      row.Attempts,
+		NextAttemptAt: row.NextAttemptAt,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+	}, nil
+}
+
+type attemptRow struct {
+	ID          uuid.UUID `db:"id"`
+	DeliveryID  uuid.UUID `db:"delivery_id"`
+	AttemptedAt time.Time `db:"attempted_at"`
+	StatusCode  int       `db:"status_code"`
+	Error       string    `db:"error"`
+	DurationMS  int64     `db:"duration_ms"`
+}
+
+type webhookRepo struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) port.WebhookRepository {
+	return &webhookRepo{db: db}
+}
+
+func (r *webhookRepo) CreateEndpoint(ctx context.Context, endpoint *model.WebhookEndpoint) error {
+	types := make(pq.StringArray, 0, len(endpoint.EventTypes))
+	for _, t := range endpoint.EventTypes {
+		types = append(types, string(t))
+	}
+
+	query := `
+		INSERT INTO webhook_endpoints (id, url, secret, event_types, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, endpoint.ID, endpoint.URL, endpoint.Secret, types, endpoint.CreatedAt)
+	return err
+}
+
+func (r *webhookRepo) GetEndpoint(ctx context.Context, id uuid.UUID) (*model.WebhookEndpoint, error) {
+	var row endpointRow
+
+	query := `
+		SELECT id, url, secret, event_types, created_at
+		FROM webhook_endpoints
+		WHERE id = $1
+	`
+
+	err := conn(ctx, r.db).GetContext(ctx, &row, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel(), nil
+}
+
+func (r *webhookRepo) ListEndpoints(ctx context.Context) ([]*model.WebhookEndpoint, error) {
+	var rows []endpointRow
+
+	query := `
+		SELECT id, url, secret, event_types, created_at
+		FROM webhook_endpoints
+		ORDER BY created_at, id
+	`
+
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query); err != nil {
+		return nil, err
+	}
+	endpoints := make([]*model.WebhookEndpoint, 0, len(rows))
+	for _, row := range rows {
+		endpoints = append(endpoints, row.toModel())
+	}
+	return endpoints, nil
+}
+
+func (r *webhookRepo) DeleteEndpoint(ctx context.Context, id uuid.UUID) (bool, error) {
+	res, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+func (r *webhookRepo) Enqueue(ctx context.Context, event model.Event, now time.Time) error {
+	data, err := marshalEventData(event)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries
+		(id, endpoint_id, event_id, event_type, event_data, occurred_at, next_attempt_at, created_at, updated_at)
+		SELECT gen_random_uuid(), id, $1, $2::text, $3, $4, $5, $5, $5
+		FROM webhook_endpoints
+		WHERE $2::text = ANY(event_types)
+		ON CONFLICT (endpoint_id, event_id) DO NOTHING
+	`
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, event.ID, string(event.Type), data, event.OccurredAt, now)
+	return err
+}
+
+func (r *webhookRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET next_attempt_at = $2
+		WHERE id IN (
+			SELECT id
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns
+	return r.selectDeliveries(ctx, query, now, now.Add(lease), limit)
+}
+
+const updateDeliveryQuery = `
+	UPDATE webhook_deliveries
+	SET status = $2, attempts = $3, next_attempt_at = $4, updated_at = $5
+	WHERE id = $1`
+
+func (r *webhookRepo) RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookAttempt) error {
+	return inTx(ctx, r.db, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, updateDeliveryQuery,
+			delivery.ID, string(delivery.Status), delivery.Attempts, delivery.NextAttemptAt, delivery.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		query := `
+			INSERT INTO webhook_delivery_attempts (id, delivery_id, attempted_at, status_code, error, duration_ms)
+			VALUES ($1, $2, $3, NULLIF($4, 0), $5, $6)
+		`
+		_, err = tx.ExecContext(ctx, query, attempt.ID, attempt.DeliveryID, attempt.AttemptedAt,
+			attempt.StatusCode, attempt.Error, attempt.Duration.Milliseconds())
+		return err
+	})
+}
+
+func (r *webhookRepo) GetDelivery(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	var row deliveryRow
+
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE id = $1
+	`
+
+	err := conn(ctx, r.db).GetContext(ctx, &row, query, id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return row.toModel()
+}
+
+func (r *webhookRepo) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, updateDeliveryQuery,
+		delivery.ID, string(delivery.Status), delivery.Attempts, delivery.NextAttemptAt, delivery.UpdatedAt)
+	return err
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, endpointID uuid.UUID, status *model.DeliveryStatus, limit int) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE endpoint_id = $1
+	`
+
+	args := []interface{}{endpointID}
+	if status != nil {
+		args = append(args, string(*status))
+		query += " AND status = $" + strconv.Itoa(len(args))
+	}
+	args = append(args, limit)
+	query += " ORDER BY created_at DESC, id LIMIT $" + strconv.Itoa(len(args))
+
+	return r.selectDeliveries(ctx, query, args...)
+}
+
+func (r *webhookRepo) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*model.WebhookAttempt, error) {
+	var rows []attemptRow
+
+	query := `
+		SELECT id, delivery_id, attempted_at, COALESCE(status_code, 0) AS status_code, error, duration_ms
+		FROM webhook_delivery_attempts
+		WHERE delivery_id = $1
+		ORDER BY attempted_at, id
+	`
+
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, deliveryID); err != nil {
+		return nil, err
+	}
+	attempts := make([]*model.WebhookAttempt, 0, len(rows))
+	for _, row := range rows {
+		attempts = append(attempts, &model.WebhookAttempt{
+			ID:          row.ID,
+			DeliveryID:  row.DeliveryID,
+			AttemptedAt: row.AttemptedAt,
+			StatusCode:  row.StatusCode,
+			Error:       row.Error,
+			Duration:    time.Duration(row.DurationMS) * time.Millisecond,
+		})
+	}
+	return attempts, nil
+}
+
+func (r *webhookRepo) selectDeliveries(ctx context.Context, query string, args ...interface{}) ([]*model.WebhookDelivery, error) {
+	var rows []deliveryRow
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0, len(rows))
+	for _, row := range rows {
+		delivery, err := row.toModel()
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,266 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+// Job is periodic work, Run returns a short summary of what was done for the run history.
+type Job struct {
+	Name string
+	// Schedule is a cron expression ("*/15 * * * *") or a descriptor ("@hourly", "@every 1h").
+	Schedule string
+	Run      func(ctx context.Context) (string, error)
+}
+
+// Counted adapts functions returning the number of processed items to Job.Run.
+func Counted[N int | int64](format string, fn func(ctx context.Context) (N, error)) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		n, err := fn(ctx)
+		return fmt.Sprintf(format, n), err
+	}
+}
+
+type entry struct {
+	job Job
+	id  cron.EntryID
+}
+
+// Scheduler runs registered jobs on their schedules. Every run takes the job's
+// lock first, so a job runs once at a time across all replicas, and is recorded in the history.
+// A scheduled run also claims its slot, so a replica firing a bit later doesn't repeat
+// a slot another replica has already run.
+type Scheduler struct {
+	cron   *cron.Cron
+	runs   port.JobRunRepository
+	locker port.JobLocker
+
+	mu   sync.Mutex
+	jobs map[string]entry
+	ctx  context.Context // контекст Run, в нём же выполняются запуски вручную
+	wg   sync.WaitGroup
+}
+
+func New(runs port.JobRunRepository, locker port.JobLocker) *Scheduler {
+	return &Scheduler{
+		cron:   cron.New(cron.WithParser(slotParser{})),
+		runs:   runs,
+		locker: locker,
+		jobs:   make(map[string]entry),
+		ctx:    context.Background(),
+	}
+}
+
+func (s *Scheduler) Register(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.jobs[job.Name]; ok {
+		return fmt.Errorf("job %q is already registered", job.Name)
+	}
+	id, err := s.cron.AddFunc(job.Schedule, func() { s.runScheduled(job, s.slot(job.Name)) })
+	if err != nil {
+		return fmt.Errorf("job %q: invalid schedule %q: %w", job.Name, job.Schedule, err)
+	}
+	s.jobs[job.Name] = entry{job: job, id: id}
+	return nil
+}
+
+// Run starts the schedule and blocks until ctx is cancelled and running jobs finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	s.mu.Lock()
+	s.ctx = ctx
+	s.mu.Unlock()
+
+	s.cron.Start()
+	<-ctx.Done()
+	<-s.cron.Stop().Done()
+	s.wg.Wait()
+}
+
+func (s *Scheduler) ListJobs(ctx context.Context) ([]model.JobInfo, error) {
+	latest, err := s.runs.Latest(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	jobs := make([]model.JobInfo, 0, len(s.jobs))
+	for name, e := range s.jobs {
+		jobs = append(jobs, model.JobInfo{
+			Name:      name,
+			Schedule:  e.job.Schedule,
+			NextRunAt: s.cron.Entry(e.id).Next,
+			LastRun:   latest[name],
+		})
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs, nil
+}
+
+func (s *Scheduler) TriggerJob(ctx context.Context, name string) (*model.JobRun, error) {
+	s.mu.Lock()
+	e, ok := s.jobs[name]
+	runCtx := s.ctx
+	s.mu.Unlock()
+	if !ok {
+		return nil, model.ErrJobNotFound
+	}
+	if runCtx.Err() != nil {
+		return nil, runCtx.Err()
+	}
+
+	unlock, ok, err := s.locker.TryLock(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, model.ErrJobRunning
+	}
+
+	run, err := s.start(ctx, e.job, model.JobTriggerManual)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+
+	started := *run
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer unlock()
+		s.execute(runCtx, e.job, run)
+	}()
+	return &started, nil
+}
+
+func (s *Scheduler) ListJobRuns(ctx context.Context, name string, limit int) ([]*model.JobRun, error) {
+	s.mu.Lock()
+	_, ok := s.jobs[name]
+	s.mu.Unlock()
+	if !ok {
+		return nil, model.ErrJobNotFound
+	}
+	return s.runs.List(ctx, name, limit)
+}
+
+// slot returns the activation time the job's entry has just fired for.
+func (s *Scheduler) slot(name string) time.Time {
+	s.mu.Lock()
+	id := s.jobs[name].id
+	s.mu.Unlock()
+	return s.cron.Entry(id).Prev
+}
+
+func (s *Scheduler) runScheduled(job Job, slot time.Time) {
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	unlock, ok, err := s.locker.TryLock(ctx, job.Name)
+	if err != nil {
+		logger.Log.Errorf("scheduler: lock job %s: %v", job.Name, err)
+		return
+	}
+	if !ok {
+		// Уже выполняется здесь или на другой реплике
+		logger.Log.Debugf("scheduler: job %s is locked, skipping", job.Name)
+		return
+	}
+	defer unlock()
+
+	scheduledAt := slot.UTC()
+	run := newRun(job, model.JobTriggerSchedule)
+	run.ScheduledAt = &scheduledAt
+	claimed, err := s.runs.Claim(ctx, run)
+	if err != nil {
+		logger.Log.Errorf("scheduler: record run of job %s: %v", job.Name, err)
+		return
+	}
+	if !claimed {
+		// Слот уже отработала другая реплика
+		logger.Log.Debugf("scheduler: job %s already ran for %s, skipping", job.Name, scheduledAt.Format(time.RFC3339))
+		return
+	}
+	s.execute(ctx, job, run)
+}
+
+func (s *Scheduler) start(ctx context.Context, job Job, trigger model.JobTrigger) (*model.JobRun, error) {
+	run := newRun(job, trigger)
+	if err := s.runs.Create(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func newRun(job Job, trigger model.JobTrigger) *model.JobRun {
+	return &model.JobRun{
+		ID:        uuid.New(),
+		Job:       job.Name,
+		Trigger:   trigger,
+		Status:    model.JobRunRunning,
+		StartedAt: time.Now().UTC(),
+	}
+}
+
+func (s *Scheduler) execute(ctx context.Context, job Job, run *model.JobRun) {
+	result, err := safeRun(ctx, job)
+
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+	run.Result = result
+	if err != nil {
+		run.Status = model.JobRunFailed
+		run.Error = err.Error()
+		logger.Log.Errorf("scheduler: job %s failed: %v", job.Name, err)
+	} else {
+		run.Status = model.JobRunSucceeded
+		logger.Log.Infof("scheduler: job %s: %s (%s)", job.Name, result, finishedAt.Sub(run.StartedAt).Round(time.Millisecond))
+	}
+
+	// Итог записывается и при остановке процесса посреди запуска
+	if err := s.runs.Finish(context.WithoutCancel(ctx), run); err != nil {
+		logger.Log.Errorf("scheduler: record result of job %s: %v", job.Name, err)
+	}
+}
+
+func safeRun(ctx context.Context, job Job) (result string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("panic: %v", p)
+		}
+	}()
+	return job.Run(ctx)
+}
+
+// slotParser parses standard cron specs and aligns "@every" schedules to
+// multiples of the interval, so every replica fires for the same slots.
+type slotParser struct{}
+
+func (slotParser) Parse(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		return alignedEvery(every.Delay), nil
+	}
+	return schedule, nil
+}
+
+type alignedEvery time.Duration
+
+func (d alignedEvery) Next(t time.Time) time.Time {
+	return t.Truncate(time.Duration(d)).Add(time.Duration(d))
+}
@@ -0,0 +1,292 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// memoryRuns keeps the run history in memory and enforces one run per slot like the unique index.
+type memoryRuns struct {
+	mu   sync.Mutex
+	runs []model.JobRun
+}
+
+func (r *memoryRuns) Create(ctx context.Context, run *model.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.runs = append(r.runs, *run)
+	return nil
+}
+
+func (r *memoryRuns) Claim(ctx context.Context, run *model.JobRun) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.runs {
+		if existing.Job == run.Job && existing.ScheduledAt != nil && existing.ScheduledAt.Equal(*run.ScheduledAt) {
+			return false, nil
+		}
+	}
+	r.runs = append(r.runs, *run)
+	return true, nil
+}
+
+func (r *memoryRuns) Finish(ctx context.Context, run *model.JobRun) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.runs {
+		if r.runs[i].ID == run.ID {
+			r.runs[i] = *run
+		}
+	}
+	return nil
+}
+
+func (r *memoryRuns) List(ctx context.Context, job string, limit int) ([]*model.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var runs []*model.JobRun
+	for i := len(r.runs) - 1; i >= 0 && len(runs) < limit; i-- {
+		if run := r.runs[i]; run.Job == job {
+			runs = append(runs, &run)
+		}
+	}
+	return runs, nil
+}
+
+func (r *memoryRuns) Latest(ctx context.Context) (map[string]*model.JobRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	latest := make(map[string]*model.JobRun)
+	for _, run := range r.runs {
+		latest[run.Job] = &run
+	}
+	return latest, nil
+}
+
+func (r *memoryRuns) all() []model.JobRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]model.JobRun(nil), r.runs...)
+}
+
+// memoryLocker is a lock shared by the schedulers of the test, as advisory locks are shared by replicas.
+type memoryLocker struct {
+	mu     sync.Mutex
+	locked map[string]bool
+}
+
+func newMemoryLocker() *memoryLocker {
+	return &memoryLocker{locked: make(map[string]bool)}
+}
+
+func (l *memoryLocker) TryLock(ctx context.Context, job string) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locked[job] {
+		return nil, false, nil
+	}
+	l.locked[job] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		delete(l.locked, job)
+	}, true, nil
+}
+
+func countingJob(name string, calls *atomic.Int32) Job {
+	return Job{
+		Name:     name,
+		Schedule: "@hourly",
+		Run: func(ctx context.Context) (string, error) {
+			calls.Add(1)
+			return "done", nil
+		},
+	}
+}
+
+func TestScheduledSlotRunsOnce(t *testing.T) {
+	runs, locker := &memoryRuns{}, newMemoryLocker()
+	replicas := []*Scheduler{New(runs, locker), New(runs, locker)}
+	var calls atomic.Int32
+	job := countingJob("renewal_scan", &calls)
+
+	slot := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	// Вторая реплика срабатывает после того, как первая отпустила блокировку
+	for _, s := range replicas {
+		s.runScheduled(job, slot)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("job ran %d times for one slot, want 1", calls.Load())
+	}
+
+	replicas[1].runScheduled(job, slot.Add(time.Hour))
+	if calls.Load() != 2 {
+		t.Fatalf("job ran %d times for two slots, want 2", calls.Load())
+	}
+
+	got := runs.all()
+	if len(got) != 2 {
+		t.Fatalf("got %d runs, want 2", len(got))
+	}
+	for i, run := range got {
+		want := slot.Add(time.Duration(i) * time.Hour)
+		if run.Trigger != model.JobTriggerSchedule || run.Status != model.JobRunSucceeded || run.Result != "done" {
+			t.Errorf("run %d = %s %s %q, want a succeeded scheduled run", i, run.Trigger, run.Status, run.Result)
+		}
+		if run.ScheduledAt == nil || !run.ScheduledAt.Equal(want) || run.FinishedAt == nil {
+			t.Errorf("run %d scheduled at %v, finished at %v, want %s", i, run.ScheduledAt, run.FinishedAt, want)
+		}
+	}
+}
+
+func TestScheduledRunSkippedWhileLocked(t *testing.T) {
+	runs, locker := &memoryRuns{}, newMemoryLocker()
+	s := New(runs, locker)
+	var calls atomic.Int32
+	job := countingJob("renewal_scan", &calls)
+
+	unlock, _, _ := locker.TryLock(context.Background(), job.Name)
+	s.runScheduled(job, time.Now())
+	unlock()
+
+	if calls.Load() != 0 || len(runs.all()) != 0 {
+		t.Errorf("locked job ran %d times with %d runs recorded, want none", calls.Load(), len(runs.all()))
+	}
+}
+
+func TestScheduledRunFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		run     func(ctx context.Context) (string, error)
+		wantErr string
+	}{
+		{"error", func(ctx context.Context) (string, error) { return "1 processed", errors.New("db is down") }, "db is down"},
+		{"panic", func(ctx context.Context) (string, error) { panic("boom") }, "panic: boom"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := &memoryRuns{}
+			New(runs, newMemoryLocker()).runScheduled(Job{Name: "purge", Schedule: "@daily", Run: tt.run}, time.Now())
+
+			got := runs.all()
+			if len(got) != 1 || got[0].Status != model.JobRunFailed || !strings.Contains(got[0].Error, tt.wantErr) {
+				t.Errorf("runs = %+v, want one failed run with %q", got, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTriggerJob(t *testing.T) {
+	runs, locker := &memoryRuns{}, newMemoryLocker()
+	s := New(runs, locker)
+	var calls atomic.Int32
+	if err := s.Register(countingJob("renewal_scan", &calls)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	if _, err := s.TriggerJob(context.Background(), "unknown"); !errors.Is(err, model.ErrJobNotFound) {
+		t.Errorf("TriggerJob(unknown) error = %v, want ErrJobNotFound", err)
+	}
+
+	unlock, _, _ := locker.TryLock(context.Background(), "renewal_scan")
+	if _, err := s.TriggerJob(context.Background(), "renewal_scan"); !errors.Is(err, model.ErrJobRunning) {
+		t.Errorf("TriggerJob() while locked error = %v, want ErrJobRunning", err)
+	}
+	unlock()
+
+	run, err := s.TriggerJob(context.Background(), "renewal_scan")
+	if err != nil {
+		t.Fatalf("TriggerJob() error = %v", err)
+	}
+	if run.Trigger != model.JobTriggerManual || run.Status != model.JobRunRunning || run.ScheduledAt != nil {
+		t.Errorf("TriggerJob() = %+v, want a running manual run", run)
+	}
+	s.wg.Wait()
+
+	history, err := s.ListJobRuns(context.Background(), "renewal_scan", 10)
+	if err != nil || len(history) != 1 || history[0].Status != model.JobRunSucceeded {
+		t.Errorf("ListJobRuns() = %+v, %v, want one succeeded run", history, err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("job ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestRegisterRejectsDuplicatesAndInvalidSchedules(t *testing.T) {
+	s := New(&memoryRuns{}, newMemoryLocker())
+	var calls atomic.Int32
+	if err := s.Register(countingJob("renewal_scan", &calls)); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := s.Register(countingJob("renewal_scan", &calls)); err == nil {
+		t.Error("Register() accepted a duplicate job")
+	}
+	if err := s.Register(Job{Name: "broken", Schedule: "every hour"}); err == nil {
+		t.Error("Register() accepted an invalid schedule")
+	}
+}
+
+func TestEverySchedulesAreAligned(t *testing.T) {
+	schedule, err := slotParser{}.Parse("@every 15m")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	// Реплики, запущенные в разное время, получают одни и те же слоты
+	for _, now := range []time.Time{
+		time.Date(2025, 7, 1, 12, 1, 7, 0, time.UTC),
+		time.Date(2025, 7, 1, 12, 14, 59, 0, time.UTC),
+	} {
+		if got, want := schedule.Next(now), time.Date(2025, 7, 1, 12, 15, 0, 0, time.UTC); !got.Equal(want) {
+			t.Errorf("Next(%s) = %s, want %s", now.Format(time.TimeOnly), got, want)
+		}
+	}
+}
+
+func TestReplicasShareScheduledSlots(t *testing.T) {
+	if testing.Short() {
+		t.Skip("waits for the schedule to fire")
+	}
+	runs, locker := &memoryRuns{}, newMemoryLocker()
+	var calls atomic.Int32
+	job := countingJob("reminders", &calls)
+	job.Schedule = "@every 1s"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	var wg sync.WaitGroup
+	for range 2 {
+		s := New(runs, locker)
+		if err := s.Register(job); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	got := runs.all()
+	if len(got) < 2 || int(calls.Load()) != len(got) {
+		t.Fatalf("job ran %d times with %d runs recorded, want a run per slot", calls.Load(), len(got))
+	}
+	slots := make(map[time.Time]bool)
+	for _, run := range got {
+		if run.ScheduledAt == nil || !run.ScheduledAt.Equal(run.ScheduledAt.Truncate(time.Second)) || run.ScheduledAt.After(run.StartedAt) {
+			t.Errorf("run scheduled at %v and started at %s, want an aligned past slot", run.ScheduledAt, run.StartedAt)
+			continue
+		}
+		if slots[*run.ScheduledAt] {
+			t.Errorf("slot %s ran twice", run.ScheduledAt)
+		}
+		slots[*run.ScheduledAt] = true
+	}
+}
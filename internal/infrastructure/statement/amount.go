@@ -0,0 +1,34 @@
+package statement
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// parseAmount parses amounts as banks write them: "-1 299,00", "+9.99", "12.3400".
+func parseAmount(value, currency string) (model.Money, error) {
+	value = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\'':
+			return -1
+		case ',':
+			return '.'
+		}
+		return r
+	}, value)
+	value = strings.TrimPrefix(value, "+")
+
+	// Лишние нули в дробной части не должны ломать разбор по числу знаков валюты
+	if dot := strings.IndexByte(value, '.'); dot >= 0 {
+		value = strings.TrimRight(value, "0")
+		value = strings.TrimSuffix(value, ".")
+	}
+
+	amount, err := model.ParseMoney(value, currency)
+	if err != nil {
+		return model.Money{}, fmt.Errorf("invalid amount %q: %w", value, err)
+	}
+	return amount, nil
+}
@@ -0,0 +1,190 @@
+package statement
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// Fields of a bank CSV statement.
+const (
+	FieldDate        = "date"
+	FieldDescription = "description"
+	FieldAmount      = "amount"
+	FieldCurrency    = "currency"
+)
+
+// columnAliases are header names used by common bank exports.
+var columnAliases = map[string]string{
+	"date":             FieldDate,
+	"transaction date": FieldDate,
+	"posted date":      FieldDate,
+	"дата":             FieldDate,
+	"дата операции":    FieldDate,
+	"description":      FieldDescription,
+	"payee":            FieldDescription,
+	"merchant":         FieldDescription,
+	"name":             FieldDescription,
+	"описание":         FieldDescription,
+	"назначение":       FieldDescription,
+	"amount":           FieldAmount,
+	"сумма":            FieldAmount,
+	"сумма операции":   FieldAmount,
+	"currency":         FieldCurrency,
+	"валюта":           FieldCurrency,
+	"валюта операции":  FieldCurrency,
+}
+
+// dateLayouts are tried in order when CSVOptions.DateLayout is empty.
+var dateLayouts = []string{"2006-01-02", "02.01.2006", "2006-01-02 15:04:05", "02.01.2006 15:04:05"}
+
+// SlashDateOrder tells how a bank writes dates like 01/02/2025.
+type SlashDateOrder string
+
+const (
+	SlashDayFirst   SlashDateOrder = "day_first"
+	SlashMonthFirst SlashDateOrder = "month_first"
+)
+
+// slashDateLayouts are tried after dateLayouts for the bank's order.
+var slashDateLayouts = map[SlashDateOrder][]string{
+	SlashDayFirst:   {"02/01/2006", "02/01/2006 15:04:05"},
+	SlashMonthFirst: {"01/02/2006", "01/02/2006 15:04:05"},
+}
+
+type CSVOptions struct {
+	Delimiter rune
+	// Mapping renames file columns to fields, other columns are matched by common names.
+	Mapping map[string]string
+	// DateLayout is a Go time layout; by default common layouts are tried.
+	DateLayout string
+	// SlashDates enables dates like 01/02/2025 with the bank's order of day and month,
+	// they are rejected by default since banks write them both ways.
+	SlashDates SlashDateOrder
+	// Currency is used when the file has no currency column.
+	Currency string
+}
+
+type csvParser struct {
+	opts CSVOptions
+}
+
+// NewCSVParser reads generic bank CSV exports with date, description and signed amount columns.
+func NewCSVParser(opts CSVOptions) port.StatementParser {
+	if opts.Delimiter == 0 {
+		opts.Delimiter = ','
+	}
+	return &csvParser{opts: opts}
+}
+
+func (p *csvParser) Parse(r io.Reader) ([]model.Transaction, error) {
+	// BOM перед заголовком в кавычках ломает разбор, поэтому убираем его до чтения
+	input := bufio.NewReader(io.LimitReader(r, maxStatementSize))
+	if bom, _ := input.Peek(3); string(bom) == "\ufeff" {
+		input.Discard(len(bom))
+	}
+	reader := csv.NewReader(input)
+	reader.Comma = p.opts.Delimiter
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("failed to read CSV header")
+	}
+	columns, err := p.columns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var transactions []model.Transaction
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		values := make(map[string]string, len(columns))
+		for i, field := range columns {
+			if field != "" && i < len(record) {
+				values[field] = strings.TrimSpace(record[i])
+			}
+		}
+		if values[FieldDate] == "" && values[FieldAmount] == "" {
+			continue
+		}
+
+		date, err := p.parseDate(values[FieldDate])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		currency := strings.ToUpper(values[FieldCurrency])
+		if currency == "" {
+			currency = p.opts.Currency
+		}
+		amount, err := parseAmount(values[FieldAmount], currency)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		transactions = append(transactions, model.Transaction{
+			Date:        date,
+			Description: values[FieldDescription],
+			Amount:      amount,
+		})
+	}
+	return transactions, nil
+}
+
+func (p *csvParser) columns(header []string) ([]string, error) {
+	for column, field := range p.opts.Mapping {
+		switch field {
+		case FieldDate, FieldDescription, FieldAmount, FieldCurrency:
+		default:
+			return nil, fmt.Errorf("mapping for column %q: unknown field %q", column, field)
+		}
+	}
+
+	columns := make([]string, len(header))
+	seen := make(map[string]bool)
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		field, ok := p.opts.Mapping[name]
+		if !ok {
+			field = columnAliases[strings.ToLower(name)]
+		}
+		if field == "" || seen[field] {
+			continue
+		}
+		seen[field] = true
+		columns[i] = field
+	}
+	for _, required := range []string{FieldDate, FieldDescription, FieldAmount} {
+		if !seen[required] {
+			return nil, fmt.Errorf("missing %q column", required)
+		}
+	}
+	return columns, nil
+}
+
+func (p *csvParser) parseDate(value string) (time.Time, error) {
+	layouts := append(dateLayouts[:len(dateLayouts):len(dateLayouts)], slashDateLayouts[p.opts.SlashDates]...)
+	if p.opts.DateLayout != "" {
+		layouts = []string{p.opts.DateLayout}
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
@@ -0,0 +1,124 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// maxStatementSize limits how much of a statement file is read.
+const maxStatementSize = 10 << 20
+
+type ofxParser struct {
+	currency string
+}
+
+// NewOFXParser reads OFX 1.x (SGML) and 2.x (XML) statements. The currency
+// is used when the statement has no CURDEF element.
+func NewOFXParser(currency string) port.StatementParser {
+	return &ofxParser{currency: currency}
+}
+
+func (p *ofxParser) Parse(r io.Reader) ([]model.Transaction, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxStatementSize))
+	if err != nil {
+		return nil, err
+	}
+	content := string(data)
+	start := strings.Index(strings.ToUpper(content), "<OFX>")
+	if start < 0 {
+		return nil, errors.New("not an OFX file")
+	}
+
+	type entry struct {
+		posted, amount, name, memo, currency string
+	}
+	var (
+		currency     = p.currency
+		entries      []entry
+		current      *entry
+		inCurrency   bool
+		transactions []model.Transaction
+	)
+
+	// В SGML-версии OFX листовые элементы не закрываются, поэтому разбираем
+	// поток тегов вручную: значение элемента — текст до следующего '<'.
+	for _, part := range strings.Split(content[start:], "<")[1:] {
+		tag, value, _ := strings.Cut(part, ">")
+		tag = strings.ToUpper(strings.TrimSpace(tag))
+		value = strings.TrimSpace(html.UnescapeString(value))
+
+		switch tag {
+		case "CURDEF":
+			currency = strings.ToUpper(value)
+		case "STMTTRN":
+			entries = append(entries, entry{})
+			current = &entries[len(entries)-1]
+		case "/STMTTRN":
+			current = nil
+		case "CURRENCY", "ORIGCURRENCY", "/CURRENCY", "/ORIGCURRENCY":
+			// Сумма TRNAMT указана в <CURRENCY>, а <ORIGCURRENCY> — лишь исходная
+			// валюта операции, поэтому CURSYM учитываем только внутри <CURRENCY>
+			inCurrency = tag == "CURRENCY"
+		}
+		if current == nil {
+			continue
+		}
+		switch tag {
+		case "DTPOSTED":
+			current.posted = value
+		case "TRNAMT":
+			current.amount = value
+		case "NAME":
+			current.name = value
+		case "MEMO":
+			current.memo = value
+		case "CURSYM":
+			if inCurrency {
+				current.currency = strings.ToUpper(value)
+			}
+		}
+	}
+
+	for i, e := range entries {
+		date, err := parseOFXDate(e.posted)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i+1, err)
+		}
+		txCurrency := currency
+		if e.currency != "" {
+			txCurrency = e.currency
+		}
+		amount, err := parseAmount(e.amount, txCurrency)
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: %w", i+1, err)
+		}
+		description := e.name
+		if description == "" {
+			description = e.memo
+		}
+		transactions = append(transactions, model.Transaction{Date: date, Description: description, Amount: amount})
+	}
+	return transactions, nil
+}
+
+// parseOFXDate parses dates like 20250115, 20250115120000 or 20250115120000.000[-5:EST].
+func parseOFXDate(value string) (time.Time, error) {
+	if i := strings.IndexByte(value, '['); i >= 0 {
+		value = value[:i]
+	}
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q", value)
+	}
+	return date, nil
+}
@@ -0,0 +1,149 @@
+package statement
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func parseFixture(t *testing.T, parser port.StatementParser, name string) ([]model.Transaction, error) {
+	t.Helper()
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("open fixture: %v", err)
+	}
+	defer file.Close()
+	return parser.Parse(file)
+}
+
+func checkTransactions(t *testing.T, got, want []model.Transaction) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d transactions, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if !got[i].Date.Equal(want[i].Date) || got[i].Description != want[i].Description || got[i].Amount != want[i].Amount {
+			t.Errorf("transaction %d = %s %q %+v, want %s %q %+v", i,
+				got[i].Date.Format(time.DateOnly), got[i].Description, got[i].Amount,
+				want[i].Date.Format(time.DateOnly), want[i].Description, want[i].Amount)
+		}
+	}
+}
+
+func TestOFXParser(t *testing.T) {
+	tests := []struct {
+		fixture string
+		want    []model.Transaction
+	}{
+		{
+			// Сумма в <CURRENCY> пересчитана банком, в <ORIGCURRENCY> — нет
+			fixture: "sgml.ofx",
+			want: []model.Transaction{
+				{Date: date(2025, time.January, 15), Description: "NETFLIX.COM", Amount: model.NewMoney(-999, "EUR")},
+				{Date: date(2025, time.February, 3), Description: "SPOTIFY & CO", Amount: model.NewMoney(-1200, "USD")},
+				{Date: date(2025, time.February, 10), Description: "ICLOUD STORAGE", Amount: model.NewMoney(-550, "EUR")},
+			},
+		},
+		{
+			fixture: "xml.ofx",
+			want: []model.Transaction{
+				{Date: date(2025, time.March, 5), Description: "YANDEX PLUS", Amount: model.NewMoney(-29900, "RUB")},
+				{Date: date(2025, time.March, 6), Description: "REFUND", Amount: model.NewMoney(150000, "RUB")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got, err := parseFixture(t, NewOFXParser("RUB"), tt.fixture)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			checkTransactions(t, got, tt.want)
+		})
+	}
+}
+
+func TestOFXParserRejectsOtherFiles(t *testing.T) {
+	if _, err := parseFixture(t, NewOFXParser("RUB"), "bank.csv"); err == nil {
+		t.Error("Parse() accepted a CSV file")
+	}
+}
+
+func TestCSVParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		opts    CSVOptions
+		want    []model.Transaction
+		wantErr string
+	}{
+		{
+			name:    "russian bank export",
+			fixture: "bank.csv",
+			opts:    CSVOptions{Delimiter: ';', Currency: "EUR"},
+			want: []model.Transaction{
+				{Date: time.Date(2025, time.January, 15, 10, 12, 0, 0, time.UTC), Description: "Netflix", Amount: model.NewMoney(-129900, "RUB")},
+				{Date: date(2025, time.February, 15), Description: "Spotify", Amount: model.NewMoney(-16950, "EUR")},
+			},
+		},
+		{
+			name:    "unknown mapped field",
+			fixture: "bank.csv",
+			opts:    CSVOptions{Delimiter: ';', Mapping: map[string]string{"Категория": "category"}},
+			wantErr: `unknown field "category"`,
+		},
+		{
+			name:    "slash dates are ambiguous by default",
+			fixture: "slash.csv",
+			opts:    CSVOptions{Currency: "USD"},
+			wantErr: `line 2: invalid date "01/02/2025"`,
+		},
+		{
+			name:    "day first",
+			fixture: "slash.csv",
+			opts:    CSVOptions{Currency: "USD", SlashDates: SlashDayFirst},
+			want: []model.Transaction{
+				{Date: date(2025, time.February, 1), Description: "Netflix", Amount: model.NewMoney(-999, "USD")},
+				{Date: date(2025, time.February, 13), Description: "Spotify", Amount: model.NewMoney(-499, "USD")},
+			},
+		},
+		{
+			name:    "month first",
+			fixture: "slash.csv",
+			opts:    CSVOptions{Currency: "USD", SlashDates: SlashMonthFirst},
+			wantErr: `line 3: invalid date "13/02/2025"`,
+		},
+		{
+			name:    "explicit layout",
+			fixture: "slash.csv",
+			opts:    CSVOptions{Currency: "USD", DateLayout: "02/01/2006"},
+			want: []model.Transaction{
+				{Date: date(2025, time.February, 1), Description: "Netflix", Amount: model.NewMoney(-999, "USD")},
+				{Date: date(2025, time.February, 13), Description: "Spotify", Amount: model.NewMoney(-499, "USD")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFixture(t, NewCSVParser(tt.opts), tt.fixture)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			checkTransactions(t, got, tt.want)
+		})
+	}
+}
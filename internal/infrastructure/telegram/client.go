@@ -0,0 +1,138 @@
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultAPIURL = "https://api.telegram.org"
+
+// Client is the part of the Bot API the bot relies on.
+type Client interface {
+	// GetUpdates long-polls for updates with ids starting from offset.
+	GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error)
+	SendMessage(ctx context.Context, chatID int64, text string) error
+}
+
+type Update struct {
+	UpdateID int64    `json:"update_id"`
+	Message  *Message `json:"message,omitempty"`
+}
+
+type Message struct {
+	MessageID int64  `json:"message_id"`
+	Chat      Chat   `json:"chat"`
+	From      *User  `json:"from,omitempty"`
+	Text      string `json:"text,omitempty"`
+}
+
+type Chat struct {
+	ID int64 `json:"id"`
+}
+
+type User struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username,omitempty"`
+}
+
+// APIError is returned when the Bot API answers with ok=false.
+type APIError struct {
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram: %d %s", e.Code, e.Description)
+}
+
+type httpClient struct {
+	baseURL string
+	timeout time.Duration
+	http    *http.Client
+}
+
+// NewClient talks to the Bot API at apiURL, which can point to a local fake server.
+// Requests time out after timeout in addition to the long polling time.
+func NewClient(apiURL, token string, timeout time.Duration) Client {
+	return &httpClient{
+		baseURL: strings.TrimRight(apiURL, "/") + "/bot" + token + "/",
+		timeout: timeout,
+		http:    &http.Client{},
+	}
+}
+
+type apiResponse struct {
+	OK          bool            `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+func (c *httpClient) GetUpdates(ctx context.Context, offset int64, timeout time.Duration) ([]Update, error) {
+	req := map[string]any{
+		"offset":          offset,
+		"timeout":         int(timeout.Seconds()),
+		"allowed_updates": []string{"message"},
+	}
+
+	var updates []Update
+	if err := c.call(ctx, "getUpdates", req, &updates, timeout); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+func (c *httpClient) SendMessage(ctx context.Context, chatID int64, text string) error {
+	req := map[string]any{
+		"chat_id": chatID,
+		"text":    text,
+	}
+	return c.call(ctx, "sendMessage", req, nil, 0)
+}
+
+// call extends the request timeout by wait for long polling.
+func (c *httpClient) call(ctx context.Context, method string, params any, result any, wait time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout+wait)
+	defer cancel()
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+method, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		// Токен входит в URL и не должен попадать в логи
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram: %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var res apiResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return fmt.Errorf("telegram: %s: unexpected response with status %d", method, resp.StatusCode)
+	}
+	if !res.OK {
+		return &APIError{Code: res.ErrorCode, Description: res.Description}
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(res.Result, result)
+}
@@ -0,0 +1,195 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testToken = "123:secret-token"
+
+type botCall struct {
+	method string
+	params map[string]any
+}
+
+// fakeBotAPI answers Bot API methods with canned responses and records the calls.
+// Requests with a different token get 401 like the real API.
+type fakeBotAPI struct {
+	server    *httptest.Server
+	responses map[string]string
+
+	mu    sync.Mutex
+	calls []botCall
+}
+
+func newFakeBotAPI(t *testing.T, responses map[string]string) *fakeBotAPI {
+	t.Helper()
+	f := &fakeBotAPI{responses: responses}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeBotAPI) handle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	method, ok := strings.CutPrefix(r.URL.Path, "/bot"+testToken+"/")
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+		return
+	}
+
+	var params map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil || r.Header.Get("Content-Type") != "application/json" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request: invalid body"}`))
+		return
+	}
+	f.mu.Lock()
+	f.calls = append(f.calls, botCall{method: method, params: params})
+	f.mu.Unlock()
+
+	response, ok := f.responses[method]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"ok":false,"error_code":404,"description":"Not Found"}`))
+		return
+	}
+	w.Write([]byte(response))
+}
+
+func (f *fakeBotAPI) only(t *testing.T) botCall {
+	t.Helper()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.calls) != 1 {
+		t.Fatalf("got %d calls, want 1", len(f.calls))
+	}
+	return f.calls[0]
+}
+
+func TestGetUpdates(t *testing.T) {
+	api := newFakeBotAPI(t, map[string]string{
+		"getUpdates": `{"ok":true,"result":[
+			{"update_id":10,"message":{"message_id":1,"chat":{"id":42},"from":{"id":7,"username":"alice"},"text":"/start"}},
+			{"update_id":11}
+		]}`,
+	})
+	client := NewClient(api.server.URL+"/", testToken, time.Second)
+
+	updates, err := client.GetUpdates(context.Background(), 10, 30*time.Second)
+	if err != nil {
+		t.Fatalf("GetUpdates() error = %v", err)
+	}
+	if len(updates) != 2 || updates[0].UpdateID != 10 || updates[1].UpdateID != 11 {
+		t.Fatalf("GetUpdates() = %+v, want updates 10 and 11", updates)
+	}
+	msg := updates[0].Message
+	if msg == nil || msg.Chat.ID != 42 || msg.Text != "/start" || msg.From == nil || msg.From.Username != "alice" {
+		t.Errorf("first message = %+v, want /start from alice in chat 42", msg)
+	}
+	if updates[1].Message != nil {
+		t.Errorf("second update message = %+v, want nil", updates[1].Message)
+	}
+
+	call := api.only(t)
+	if call.params["offset"] != float64(10) || call.params["timeout"] != float64(30) {
+		t.Errorf("getUpdates params = %v, want offset 10 and timeout 30", call.params)
+	}
+	if allowed, _ := call.params["allowed_updates"].([]any); len(allowed) != 1 || allowed[0] != "message" {
+		t.Errorf("allowed_updates = %v, want [message]", call.params["allowed_updates"])
+	}
+}
+
+func TestSendMessage(t *testing.T) {
+	api := newFakeBotAPI(t, map[string]string{
+		"sendMessage": `{"ok":true,"result":{"message_id":5,"chat":{"id":42},"text":"Привет"}}`,
+	})
+
+	if err := NewClient(api.server.URL, testToken, time.Second).SendMessage(context.Background(), 42, "Привет"); err != nil {
+		t.Fatalf("SendMessage() error = %v", err)
+	}
+	call := api.only(t)
+	if call.method != "sendMessage" || call.params["chat_id"] != float64(42) || call.params["text"] != "Привет" {
+		t.Errorf("call = %+v, want sendMessage to chat 42", call)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name  string
+		token string
+		want  APIError
+	}{
+		{"bot blocked", testToken, APIError{Code: 403, Description: "Forbidden: bot was blocked by the user"}},
+		{"wrong token", "456:other", APIError{Code: 401, Description: "Unauthorized"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			api := newFakeBotAPI(t, map[string]string{
+				"sendMessage": `{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`,
+			})
+
+			err := NewClient(api.server.URL, tt.token, time.Second).SendMessage(context.Background(), 42, "text")
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || *apiErr != tt.want {
+				t.Errorf("SendMessage() error = %v, want %v", err, &tt.want)
+			}
+		})
+	}
+}
+
+func TestUnexpectedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("<html>Bad Gateway</html>"))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, testToken, time.Second).SendMessage(context.Background(), 42, "text")
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("SendMessage() error = %v, want an error with status 502", err)
+	}
+}
+
+func TestErrorHidesToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	err := NewClient(url, testToken, time.Second).SendMessage(context.Background(), 42, "text")
+	if err == nil {
+		t.Fatal("SendMessage() succeeded without a server")
+	}
+	if strings.Contains(err.Error(), testToken) {
+		t.Errorf("error %q contains the bot token", err)
+	}
+}
+
+func TestGetUpdatesTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	start := time.Now()
+	_, err := NewClient(server.URL, testToken, 50*time.Millisecond).GetUpdates(context.Background(), 0, 0)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetUpdates() error = %v, want a deadline error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("GetUpdates() returned after %s, want about 50ms", elapsed)
+	}
+}
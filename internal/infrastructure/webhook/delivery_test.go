@@ -0,0 +1,303 @@
+package webhook_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/internal/application/usecase"
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/infrastructure/webhook"
+	"github.com/google/uuid"
+)
+
+// memoryRepo keeps endpoints and deliveries in memory, only what the tested service methods call is implemented.
+type memoryRepo struct {
+	port.WebhookRepository
+
+	mu         sync.Mutex
+	endpoints  map[uuid.UUID]*model.WebhookEndpoint
+	deliveries map[uuid.UUID]*model.WebhookDelivery
+	attempts   []*model.WebhookAttempt
+}
+
+func newMemoryRepo(endpoints ...*model.WebhookEndpoint) *memoryRepo {
+	r := &memoryRepo{
+		endpoints:  make(map[uuid.UUID]*model.WebhookEndpoint),
+		deliveries: make(map[uuid.UUID]*model.WebhookDelivery),
+	}
+	for _, e := range endpoints {
+		r.endpoints[e.ID] = e
+	}
+	return r
+}
+
+func (r *memoryRepo) GetEndpoint(ctx context.Context, id uuid.UUID) (*model.WebhookEndpoint, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.endpoints[id], nil
+}
+
+func (r *memoryRepo) Enqueue(ctx context.Context, event model.Event, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range r.endpoints {
+		if !e.Accepts(event.Type) {
+			continue
+		}
+		id := uuid.New()
+		r.deliveries[id] = &model.WebhookDelivery{
+			ID:            id,
+			EndpointID:    e.ID,
+			Event:         event,
+			Status:        model.DeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []*model.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status != model.DeliveryPending || d.NextAttemptAt.After(now) || len(due) == limit {
+			continue
+		}
+		d.NextAttemptAt = now.Add(lease)
+		claimed := *d
+		due = append(due, &claimed)
+	}
+	return due, nil
+}
+
+func (r *memoryRepo) RecordAttempt(ctx context.Context, delivery *model.WebhookDelivery, attempt *model.WebhookAttempt) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	r.attempts = append(r.attempts, attempt)
+	return nil
+}
+
+func (r *memoryRepo) GetDelivery(ctx context.Context, id uuid.UUID) (*model.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.deliveries[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (r *memoryRepo) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *delivery
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (r *memoryRepo) ListAttempts(ctx context.Context, deliveryID uuid.UUID) ([]*model.WebhookAttempt, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var attempts []*model.WebhookAttempt
+	for _, a := range r.attempts {
+		if a.DeliveryID == deliveryID {
+			attempts = append(attempts, a)
+		}
+	}
+	return attempts, nil
+}
+
+func (r *memoryRepo) only(t *testing.T) model.WebhookDelivery {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(r.deliveries))
+	}
+	for _, d := range r.deliveries {
+		return *d
+	}
+	return model.WebhookDelivery{}
+}
+
+// flakyReceiver fails the first failures requests with 503 and verifies every signature.
+func flakyReceiver(t *testing.T, secret string, failures int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		if got, want := r.Header.Get(webhook.HeaderSignature), webhook.Sign(secret, r.Header.Get(webhook.HeaderTimestamp), body); got != want {
+			t.Errorf("signature = %q, want %q", got, want)
+		}
+		if hits.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+	return server, &hits
+}
+
+func deliverUntilSettled(t *testing.T, service interface {
+	DeliverDue(ctx context.Context) (int, error)
+}, repo *memoryRepo) model.WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := service.DeliverDue(context.Background()); err != nil {
+			t.Fatalf("DeliverDue() error = %v", err)
+		}
+		if d := repo.only(t); d.Status != model.DeliveryPending {
+			return d
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatal("delivery is still pending")
+	return model.WebhookDelivery{}
+}
+
+func publishCreated(t *testing.T, service interface {
+	Publish(ctx context.Context, events ...model.Event) error
+}) {
+	t.Helper()
+	event := model.Event{ID: uuid.New(), Type: model.EventSubscriptionCreated, OccurredAt: time.Now()}
+	if err := service.Publish(context.Background(), event); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+}
+
+func TestDeliveryRetriesUntilSuccess(t *testing.T) {
+	endpoint := &model.WebhookEndpoint{ID: uuid.New(), Secret: "s3cret", EventTypes: []model.EventType{model.EventSubscriptionCreated}}
+	server, hits := flakyReceiver(t, endpoint.Secret, 2)
+	endpoint.URL = server.URL
+	repo := newMemoryRepo(endpoint)
+	service := usecase.NewWebhookService(repo, webhook.NewSender(time.Second), usecase.WebhookOptions{
+		MaxAttempts: 5,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    40 * time.Millisecond,
+		Lease:       time.Second,
+		BatchSize:   10,
+	})
+
+	publishCreated(t, service)
+	d := deliverUntilSettled(t, service, repo)
+
+	if d.Status != model.DeliverySucceeded || d.Attempts != 3 || hits.Load() != 3 {
+		t.Errorf("delivery = %s after %d attempts and %d requests, want succeeded after 3", d.Status, d.Attempts, hits.Load())
+	}
+	if len(repo.attempts) != 3 {
+		t.Fatalf("got %d recorded attempts, want 3", len(repo.attempts))
+	}
+	for i, a := range repo.attempts {
+		wantStatus, wantErr := http.StatusServiceUnavailable, true
+		if i == 2 {
+			wantStatus, wantErr = http.StatusOK, false
+		}
+		if a.StatusCode != wantStatus || (a.Error != "") != wantErr {
+			t.Errorf("attempt %d = %d %q, want status %d", i+1, a.StatusCode, a.Error, wantStatus)
+		}
+	}
+	// Вторая попытка не раньше BaseDelay, третья — не раньше удвоенной задержки
+	if gap := repo.attempts[1].AttemptedAt.Sub(repo.attempts[0].AttemptedAt); gap < 10*time.Millisecond {
+		t.Errorf("second attempt after %s, want at least 10ms", gap)
+	}
+	if gap := repo.attempts[2].AttemptedAt.Sub(repo.attempts[1].AttemptedAt); gap < 20*time.Millisecond {
+		t.Errorf("third attempt after %s, want at least 20ms", gap)
+	}
+}
+
+func TestDeliveryFailsAfterMaxAttempts(t *testing.T) {
+	endpoint := &model.WebhookEndpoint{ID: uuid.New(), Secret: "s3cret", EventTypes: []model.EventType{model.EventSubscriptionCreated}}
+	server, hits := flakyReceiver(t, endpoint.Secret, 1<<30)
+	endpoint.URL = server.URL
+	repo := newMemoryRepo(endpoint)
+	service := usecase.NewWebhookService(repo, webhook.NewSender(time.Second), usecase.WebhookOptions{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		Lease:       time.Second,
+		BatchSize:   10,
+	})
+
+	publishCreated(t, service)
+	d := deliverUntilSettled(t, service, repo)
+
+	if d.Status != model.DeliveryFailed || d.Attempts != 3 || hits.Load() != 3 {
+		t.Errorf("delivery = %s after %d attempts and %d requests, want failed after 3", d.Status, d.Attempts, hits.Load())
+	}
+}
+
+func TestRedeliverFailedDelivery(t *testing.T) {
+	endpoint := &model.WebhookEndpoint{ID: uuid.New(), Secret: "s3cret", EventTypes: []model.EventType{model.EventSubscriptionCreated}}
+	// Получатель лежит всё время первой доставки и поднимается к ручному повтору
+	server, hits := flakyReceiver(t, endpoint.Secret, 2)
+	endpoint.URL = server.URL
+	repo := newMemoryRepo(endpoint)
+	service := usecase.NewWebhookService(repo, webhook.NewSender(time.Second), usecase.WebhookOptions{
+		MaxAttempts: 2,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    time.Millisecond,
+		Lease:       time.Second,
+		BatchSize:   10,
+	})
+	ctx := context.Background()
+
+	publishCreated(t, service)
+	failed := deliverUntilSettled(t, service, repo)
+	if failed.Status != model.DeliveryFailed || failed.Attempts != 2 {
+		t.Fatalf("delivery = %s after %d attempts, want failed after 2", failed.Status, failed.Attempts)
+	}
+
+	if _, err := service.Redeliver(ctx, uuid.New(), failed.ID); !errors.Is(err, model.ErrDeliveryNotFound) {
+		t.Errorf("Redeliver() for another endpoint error = %v, want ErrDeliveryNotFound", err)
+	}
+	if _, err := service.Redeliver(ctx, endpoint.ID, uuid.New()); !errors.Is(err, model.ErrDeliveryNotFound) {
+		t.Errorf("Redeliver() for an unknown delivery error = %v, want ErrDeliveryNotFound", err)
+	}
+
+	queued, err := service.Redeliver(ctx, endpoint.ID, failed.ID)
+	if err != nil {
+		t.Fatalf("Redeliver() error = %v", err)
+	}
+	if queued.Status != model.DeliveryPending || queued.Attempts != 0 || queued.NextAttemptAt.After(time.Now()) {
+		t.Errorf("Redeliver() = %s after %d attempts, next at %s, want pending with a fresh budget now",
+			queued.Status, queued.Attempts, queued.NextAttemptAt)
+	}
+	if stored := repo.only(t); stored.Status != model.DeliveryPending || stored.Attempts != 0 {
+		t.Errorf("stored delivery = %s after %d attempts, want pending with no attempts", stored.Status, stored.Attempts)
+	}
+
+	d := deliverUntilSettled(t, service, repo)
+	if d.Status != model.DeliverySucceeded || d.Attempts != 1 || hits.Load() != 3 {
+		t.Errorf("redelivery = %s after %d attempts and %d requests, want succeeded after 1", d.Status, d.Attempts, hits.Load())
+	}
+
+	got, attempts, err := service.GetDelivery(ctx, endpoint.ID, d.ID)
+	if err != nil {
+		t.Fatalf("GetDelivery() error = %v", err)
+	}
+	if got.Status != model.DeliverySucceeded || len(attempts) != 3 {
+		t.Errorf("GetDelivery() = %s with %d attempts, want succeeded with the whole history of 3", got.Status, len(attempts))
+	}
+	if _, _, err := service.GetDelivery(ctx, uuid.New(), d.ID); !errors.Is(err, model.ErrDeliveryNotFound) {
+		t.Errorf("GetDelivery() for another endpoint error = %v, want ErrDeliveryNotFound", err)
+	}
+}
@@ -0,0 +1,126 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/google/uuid"
+)
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+func newReceiver(t *testing.T, status int) (*httptest.Server, <-chan receivedRequest) {
+	t.Helper()
+	requests := make(chan receivedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("read body: %v", err)
+		}
+		requests <- receivedRequest{header: r.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func testDelivery() *model.WebhookDelivery {
+	return &model.WebhookDelivery{
+		ID: uuid.New(),
+		Event: model.Event{
+			ID:         uuid.New(),
+			Type:       model.EventSubscriptionCreated,
+			OccurredAt: time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC),
+			Subscription: &model.Subscription{
+				ID:            uuid.New(),
+				ServiceName:   "Netflix",
+				Price:         model.NewMoney(79900, "RUB"),
+				UserID:        uuid.New(),
+				StartDate:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+				BillingPeriod: model.BillingPeriodMonthly,
+			},
+		},
+	}
+}
+
+func TestSendSignsDelivery(t *testing.T) {
+	server, requests := newReceiver(t, http.StatusNoContent)
+	endpoint := &model.WebhookEndpoint{ID: uuid.New(), URL: server.URL, Secret: "s3cret"}
+	delivery := testDelivery()
+
+	status, err := NewSender(time.Second).Send(context.Background(), endpoint, delivery)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("Send() = %d, %v, want 204", status, err)
+	}
+	req := <-requests
+
+	for header, want := range map[string]string{
+		"Content-Type": "application/json",
+		HeaderEvent:    string(model.EventSubscriptionCreated),
+		HeaderEventID:  delivery.Event.ID.String(),
+		HeaderDelivery: delivery.ID.String(),
+	} {
+		if got := req.header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	// Подпись проверяем так, как это сделает получатель
+	timestamp := req.header.Get(HeaderTimestamp)
+	mac := hmac.New(sha256.New, []byte(endpoint.Secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(req.body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.header.Get(HeaderSignature); !hmac.Equal([]byte(got), []byte(want)) {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+	}
+	if Sign("other", timestamp, req.body) == want {
+		t.Error("signature doesn't depend on the secret")
+	}
+
+	var payload dto.EventPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.ID != delivery.Event.ID.String() || payload.Type != string(model.EventSubscriptionCreated) {
+		t.Errorf("payload = %+v, want event %s", payload, delivery.Event.ID)
+	}
+	if payload.Subscription == nil || payload.Subscription.ServiceName != "Netflix" {
+		t.Errorf("payload subscription = %+v, want Netflix", payload.Subscription)
+	}
+}
+
+func TestSendFailsOnErrorStatus(t *testing.T) {
+	server, requests := newReceiver(t, http.StatusServiceUnavailable)
+	endpoint := &model.WebhookEndpoint{ID: uuid.New(), URL: server.URL, Secret: "s3cret"}
+
+	status, err := NewSender(time.Second).Send(context.Background(), endpoint, testDelivery())
+	<-requests
+	if err == nil || status != http.StatusServiceUnavailable {
+		t.Errorf("Send() = %d, %v, want 503 and an error", status, err)
+	}
+}
+
+func TestSendFailsWithoutResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	status, err := NewSender(time.Second).Send(context.Background(), &model.WebhookEndpoint{URL: url}, testDelivery())
+	if err == nil || status != 0 {
+		t.Errorf("Send() = %d, %v, want 0 and an error", status, err)
+	}
+}
@@ -0,0 +1,48 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/application/port"
+	"github.com/Babushkin05/subscription-organizer/pkg/logger"
+)
+
+// Worker sends due deliveries every pollInterval until ctx is cancelled.
+type Worker struct {
+	service      port.WebhookService
+	pollInterval time.Duration
+}
+
+func NewWorker(service port.WebhookService, pollInterval time.Duration) *Worker {
+	return &Worker{service: service, pollInterval: pollInterval}
+}
+
+func (w *Worker) Run(ctx context.Context) {
+	poll := time.NewTicker(w.pollInterval)
+	defer poll.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-poll.C:
+			w.deliver(ctx)
+		}
+	}
+}
+
+// deliver keeps sending until nothing is due.
+func (w *Worker) deliver(ctx context.Context) {
+	for ctx.Err() == nil {
+		n, err := w.service.DeliverDue(ctx)
+		if err != nil {
+			logger.Log.Errorf("webhook worker: failed to deliver: %v", err)
+			return
+		}
+		if n == 0 {
+			return
+		}
+		logger.Log.Infof("webhook worker: attempted %d deliveries", n)
+	}
+}
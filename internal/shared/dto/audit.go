@@ -0,0 +1,14 @@
+package dto
+
+type AuditEntryResponse struct {
+	ID             string                       `json:"id"`
+	SubscriptionID string                       `json:"subscription_id"`
+	Actor          string                       `json:"actor"`
+	Action         string                       `json:"action" enums:"create,update,delete,restore,cancel,reactivate,status_change,price_change,members_change,discount_create,discount_delete"`
+	Before         *SubscriptionResponse        `json:"before,omitempty"` // отсутствует для create
+	After          *SubscriptionResponse        `json:"after,omitempty"`
+	PriceChange    *PriceChangeResponse         `json:"price_change,omitempty"` // для price_change
+	Members        []SubscriptionMemberResponse `json:"members,omitempty"`      // новый состав для members_change, пусто, если участников убрали
+	Discount       *DiscountResponse            `json:"discount,omitempty"`     // для discount_create и discount_delete
+	CreatedAt      string                       `json:"created_at"`             // RFC 3339
+}
@@ -0,0 +1,36 @@
+package dto
+
+type BatchCreateRequest struct {
+	Mode  string                      `json:"mode,omitempty" binding:"omitempty,oneof=atomic best_effort"` // по умолчанию atomic
+	Items []CreateSubscriptionRequest `json:"items" binding:"required,min=1,max=1000"`
+}
+
+type BatchUpdateItem struct {
+	ID string `json:"id" binding:"required,uuid"`
+	CreateSubscriptionRequest
+}
+
+type BatchUpdateRequest struct {
+	Mode  string            `json:"mode,omitempty" binding:"omitempty,oneof=atomic best_effort"`
+	Items []BatchUpdateItem `json:"items" binding:"required,min=1,max=1000"`
+}
+
+type BatchDeleteRequest struct {
+	Mode   string   `json:"mode,omitempty" binding:"omitempty,oneof=atomic best_effort"`
+	Reason string   `json:"reason,omitempty" binding:"omitempty,oneof=mistake ended"` // по умолчанию mistake
+	IDs    []string `json:"ids" binding:"required,min=1,max=1000"`
+}
+
+type BatchItemResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"` // created, updated, deleted, failed, skipped или rolled_back
+	Error  string `json:"error,omitempty"`
+}
+
+type BatchResponse struct {
+	Mode      string            `json:"mode"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Items     []BatchItemResult `json:"items"`
+}
@@ -0,0 +1,56 @@
+package dto
+
+import "github.com/Babushkin05/subscription-organizer/internal/domain/model"
+
+// Все суммы — десятичные строки в валюте из поля currency
+
+// TotalCostResponse: gross = before_discount - discount = net + tax
+type TotalCostResponse struct {
+	TotalCost      model.Money `json:"total_cost" swaggertype:"string" example:"5498.00"` // совпадает с gross, оставлено для совместимости
+	BeforeDiscount model.Money `json:"before_discount" swaggertype:"string" example:"5998.00"`
+	Discount       model.Money `json:"discount" swaggertype:"string" example:"500.00"`
+	Net            model.Money `json:"net" swaggertype:"string" example:"4581.67"`
+	Tax            model.Money `json:"tax" swaggertype:"string" example:"916.33"`
+	Gross          model.Money `json:"gross" swaggertype:"string" example:"5498.00"`
+	Currency       string      `json:"currency"`
+}
+
+type MonthlyCostResponse struct {
+	Month     string      `json:"month"`
+	Total     model.Money `json:"total" swaggertype:"string"`
+	Committed model.Money `json:"committed" swaggertype:"string"`
+	Projected model.Money `json:"projected" swaggertype:"string"`
+}
+
+type ForecastResponse struct {
+	From     string                `json:"from"`
+	To       string                `json:"to"`
+	Total    model.Money           `json:"total" swaggertype:"string"`
+	Currency string                `json:"currency"`
+	Months   []MonthlyCostResponse `json:"months"`
+}
+
+type PeriodCostResponse struct {
+	From  string      `json:"from"`
+	To    string      `json:"to"`
+	Total model.Money `json:"total" swaggertype:"string"`
+}
+
+type ServiceCostChangeResponse struct {
+	ServiceName            string      `json:"service_name"`
+	BaseTotal              model.Money `json:"base_total" swaggertype:"string"`
+	CurrentTotal           model.Money `json:"current_total" swaggertype:"string"`
+	Delta                  model.Money `json:"delta" swaggertype:"string"`
+	NewSubscriptions       model.Money `json:"new_subscriptions" swaggertype:"string"`
+	CancelledSubscriptions model.Money `json:"cancelled_subscriptions" swaggertype:"string"`
+	PriceChanges           model.Money `json:"price_changes" swaggertype:"string"`
+}
+
+type CostComparisonResponse struct {
+	Base         PeriodCostResponse          `json:"base"`
+	Current      PeriodCostResponse          `json:"current"`
+	Delta        model.Money                 `json:"delta" swaggertype:"string"`
+	DeltaPercent *float64                    `json:"delta_percent"` // null, если в базовом периоде расходов не было
+	Currency     string                      `json:"currency"`
+	Services     []ServiceCostChangeResponse `json:"services"`
+}
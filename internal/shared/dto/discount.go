@@ -0,0 +1,21 @@
+package dto
+
+import "encoding/json"
+
+type CreateDiscountRequest struct {
+	Kind      string      `json:"kind" binding:"required,oneof=percentage fixed"`
+	Value     json.Number `json:"value" binding:"required" swaggertype:"string" example:"50"` // процент для percentage, сумма в валюте подписки для fixed
+	Code      string      `json:"code,omitempty"`                                             // промокод, если скидка получена по нему
+	StartDate string      `json:"start_date" binding:"required"`                              // формат: "07-2025"
+	EndDate   string      `json:"end_date,omitempty"`
+}
+
+type DiscountResponse struct {
+	ID             string `json:"id"`
+	SubscriptionID string `json:"subscription_id"`
+	Kind           string `json:"kind"`
+	Value          string `json:"value"`
+	Code           string `json:"code,omitempty"`
+	StartDate      string `json:"start_date"`
+	EndDate        string `json:"end_date,omitempty"`
+}
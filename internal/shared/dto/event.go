@@ -0,0 +1,11 @@
+package dto
+
+// EventPayload is the JSON body of events sent to other systems.
+type EventPayload struct {
+	ID           string                `json:"id"`
+	Type         string                `json:"type" example:"subscription.created"`
+	OccurredAt   string                `json:"occurred_at"` // RFC 3339
+	Subscription *SubscriptionResponse `json:"subscription,omitempty"`
+	PriceChange  *PriceChangeResponse  `json:"price_change,omitempty"` // для subscription.price_changed
+	RenewsAt     string                `json:"renews_at,omitempty"`    // формат: "07-2025"
+}
@@ -0,0 +1,15 @@
+package dto
+
+type ImportRowResult struct {
+	Row   int    `json:"row"`             // номер строки в файле, заголовок — строка 1
+	ID    string `json:"id,omitempty"`    // ID подписки для валидной строки
+	Error string `json:"error,omitempty"` // причина, по которой строка отклонена
+}
+
+type ImportReport struct {
+	DryRun   bool              `json:"dry_run"`
+	Total    int               `json:"total"`
+	Valid    int               `json:"valid"`
+	Imported int               `json:"imported"`
+	Rows     []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,20 @@
+package dto
+
+type JobResponse struct {
+	Name      string          `json:"name"`
+	Schedule  string          `json:"schedule"`
+	NextRunAt string          `json:"next_run_at,omitempty"` // RFC 3339
+	LastRun   *JobRunResponse `json:"last_run,omitempty"`
+}
+
+type JobRunResponse struct {
+	ID          string `json:"id"`
+	Job         string `json:"job"`
+	Trigger     string `json:"trigger" enums:"schedule,manual"`
+	Status      string `json:"status" enums:"running,succeeded,failed"`
+	ScheduledAt string `json:"scheduled_at,omitempty"`
+	StartedAt   string `json:"started_at"`
+	FinishedAt  string `json:"finished_at,omitempty"`
+	Result      string `json:"result,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
@@ -0,0 +1,19 @@
+package dto
+
+import "encoding/json"
+
+type SubscriptionMemberRequest struct {
+	UserID    string      `json:"user_id" binding:"required,uuid"`
+	SplitType string      `json:"split_type" binding:"required,oneof=equal percentage fixed"`
+	Value     json.Number `json:"value,omitempty" swaggertype:"string" example:"25"` // процент для percentage, сумма в валюте подписки для fixed
+}
+
+type SetMembersRequest struct {
+	Members []SubscriptionMemberRequest `json:"members" binding:"dive"`
+}
+
+type SubscriptionMemberResponse struct {
+	UserID    string `json:"user_id"`
+	SplitType string `json:"split_type"`
+	Value     string `json:"value"`
+}
@@ -0,0 +1,16 @@
+package dto
+
+type NotificationPreferencesRequest struct {
+	Email             string `json:"email,omitempty" binding:"omitempty,email"`
+	Enabled           bool   `json:"enabled"`
+	RenewalDaysBefore int    `json:"renewal_days_before" binding:"min=0,max=60"` // 0 — не напоминать
+	TrialDaysBefore   int    `json:"trial_days_before" binding:"min=0,max=60"`
+}
+
+type NotificationPreferencesResponse struct {
+	UserID            string `json:"user_id"`
+	Email             string `json:"email,omitempty"`
+	Enabled           bool   `json:"enabled"`
+	RenewalDaysBefore int    `json:"renewal_days_before"`
+	TrialDaysBefore   int    `json:"trial_days_before"`
+}
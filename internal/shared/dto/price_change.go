@@ -0,0 +1,20 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+type CreatePriceChangeRequest struct {
+	Price         json.Number `json:"price" binding:"required" swaggertype:"string" example:"1199.00"` // в валюте подписки
+	EffectiveFrom string      `json:"effective_from" binding:"required"`                               // формат: "07-2025"
+}
+
+type PriceChangeResponse struct {
+	ID             string      `json:"id"`
+	SubscriptionID string      `json:"subscription_id"`
+	Price          model.Money `json:"price" swaggertype:"string" example:"1199.00"`
+	Currency       string      `json:"currency"`
+	EffectiveFrom  string      `json:"effective_from"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import "github.com/Babushkin05/subscription-organizer/internal/domain/model"
+
+type RecommendationResponse struct {
+	Rule            string      `json:"rule"`
+	ServiceName     string      `json:"service_name"`
+	SubscriptionIDs []string    `json:"subscription_ids"`
+	Score           int         `json:"score"`
+	MonthlySavings  model.Money `json:"monthly_savings" swaggertype:"string" example:"199.00"`
+	Currency        string      `json:"currency"`
+	Explanation     string      `json:"explanation"`
+}
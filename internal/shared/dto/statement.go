@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"encoding/json"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+type SubscriptionCandidateResponse struct {
+	ServiceName            string      `json:"service_name"`
+	Merchant               string      `json:"merchant"` // описание списания в выписке
+	Price                  model.Money `json:"price" swaggertype:"string" example:"799.00"`
+	Currency               string      `json:"currency"`
+	BillingPeriod          string      `json:"billing_period"`
+	StartDate              string      `json:"start_date"`
+	EndDate                string      `json:"end_date,omitempty"` // списания прекратились до конца выписки
+	LastCharge             string      `json:"last_charge"`        // формат: "2025-07-15"
+	Occurrences            int         `json:"occurrences"`
+	Confidence             int         `json:"confidence"` // от 0 до 100
+	ExistingSubscriptionID string      `json:"existing_subscription_id,omitempty"`
+}
+
+type StatementAnalysisResponse struct {
+	Transactions int                             `json:"transactions"`
+	Candidates   []SubscriptionCandidateResponse `json:"candidates"`
+}
+
+type ConfirmCandidateRequest struct {
+	ServiceName   string      `json:"service_name" binding:"required"`
+	Price         json.Number `json:"price" binding:"required" swaggertype:"string" example:"799.00"`
+	Currency      string      `json:"currency,omitempty" binding:"omitempty,iso4217"`
+	StartDate     string      `json:"start_date" binding:"required"` // формат: "07-2025"
+	EndDate       string      `json:"end_date,omitempty"`
+	BillingPeriod string      `json:"billing_period,omitempty" binding:"omitempty,oneof=monthly quarterly yearly"`
+}
+
+type ConfirmCandidatesRequest struct {
+	Candidates []ConfirmCandidateRequest `json:"candidates" binding:"required,min=1,dive"`
+}
@@ -1,18 +1,49 @@
 package dto
 
+import (
+	"encoding/json"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
 type CreateSubscriptionRequest struct {
-	ServiceName string `json:"service_name" binding:"required"`
-	Price       int    `json:"price" binding:"required"`
-	UserID      string `json:"user_id" binding:"required,uuid"`
-	StartDate   string `json:"start_date" binding:"required"` // формат: "07-2025"
-	EndDate     string `json:"end_date,omitempty"`
+	ServiceName      string      `json:"service_name" binding:"required"`
+	Price            json.Number `json:"price" binding:"required" swaggertype:"string" example:"999.00"` // число или строка с десятичной дробью
+	Currency         string      `json:"currency,omitempty" binding:"omitempty,iso4217"`                 // по умолчанию RUB
+	UserID           string      `json:"user_id" binding:"required,uuid"`
+	StartDate        string      `json:"start_date" binding:"required"` // формат: "07-2025"
+	EndDate          string      `json:"end_date,omitempty"`
+	BillingPeriod    string      `json:"billing_period,omitempty" binding:"omitempty,oneof=monthly quarterly yearly"`
+	TrialEndDate     string      `json:"trial_end_date,omitempty"`
+	TaxRate          float64     `json:"tax_rate,omitempty" binding:"omitempty,min=0,max=100"` // в процентах, например 20 или 7.7
+	PriceIncludesTax *bool       `json:"price_includes_tax,omitempty"`                         // по умолчанию true
+}
+
+type CancelSubscriptionRequest struct {
+	Mode string `json:"mode,omitempty" binding:"omitempty,oneof=immediately at_period_end"` // по умолчанию at_period_end
 }
 
 type SubscriptionResponse struct {
-	ID          string `json:"id"`
-	ServiceName string `json:"service_name"`
-	Price       int    `json:"price"`
-	UserID      string `json:"user_id"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date,omitempty"`
+	ID               string      `json:"id"`
+	ServiceName      string      `json:"service_name"`
+	Price            model.Money `json:"price" swaggertype:"string" example:"999.00"`
+	Currency         string      `json:"currency"`
+	UserID           string      `json:"user_id"`
+	StartDate        string      `json:"start_date"`
+	EndDate          string      `json:"end_date,omitempty"`
+	BillingPeriod    string      `json:"billing_period"`
+	TrialEndDate     string      `json:"trial_end_date,omitempty"`
+	TaxRate          float64     `json:"tax_rate"`
+	PriceIncludesTax bool        `json:"price_includes_tax"`
+	IsDeleted        bool        `json:"is_deleted,omitempty"`
+	DeletionReason   string      `json:"deletion_reason,omitempty" enums:"mistake,ended"`
+	Status           string      `json:"status" enums:"scheduled,trial,active,expired,cancelled"`
+	CancelledAt      string      `json:"cancelled_at,omitempty"` // RFC 3339; пока подписка не закончилась, её можно возобновить
+	EndedAt          string      `json:"ended_at,omitempty"`     // RFC 3339, только для отменённых сразу
+}
+
+type DuplicateGroupResponse struct {
+	UserID        string                 `json:"user_id"`
+	ServiceName   string                 `json:"service_name"`
+	Subscriptions []SubscriptionResponse `json:"subscriptions"`
 }
@@ -0,0 +1,7 @@
+package dto
+
+type TelegramLinkResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+	URL       string `json:"url,omitempty"` // ссылка на бота, если задан telegram.bot_username
+}
@@ -0,0 +1,34 @@
+package dto
+
+type CreateWebhookRequest struct {
+	URL        string   `json:"url" binding:"required,url"`
+	Secret     string   `json:"secret,omitempty" binding:"omitempty,min=16"` // генерируется, если не задан
+	EventTypes []string `json:"event_types" binding:"required,min=1" example:"subscription.created,subscription.renewal_upcoming"`
+}
+
+type WebhookResponse struct {
+	ID         string   `json:"id"`
+	URL        string   `json:"url"`
+	Secret     string   `json:"secret,omitempty"` // возвращается только при регистрации
+	EventTypes []string `json:"event_types"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID            string                   `json:"id"`
+	WebhookID     string                   `json:"webhook_id"`
+	EventID       string                   `json:"event_id"`
+	EventType     string                   `json:"event_type"`
+	Status        string                   `json:"status" enums:"pending,succeeded,failed"`
+	Attempts      int                      `json:"attempts"`
+	NextAttemptAt string                   `json:"next_attempt_at,omitempty"` // только для pending
+	CreatedAt     string                   `json:"created_at"`
+	Log           []WebhookAttemptResponse `json:"log,omitempty"`
+}
+
+type WebhookAttemptResponse struct {
+	AttemptedAt string `json:"attempted_at"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	Error       string `json:"error,omitempty"`
+	DurationMS  int64  `json:"duration_ms"`
+}
@@ -0,0 +1,58 @@
+package mapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// parseShareValue parses a value which is a whole percentage when percentage
+// is true and an amount of the currency otherwise, returned in minor units.
+func parseShareValue(value json.Number, percentage bool, currency string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	if percentage {
+		n, err := strconv.ParseInt(value.String(), 10, 64)
+		if err != nil || n < 0 || n > 100 {
+			return 0, fmt.Errorf("invalid percentage %q, use a whole number between 0 and 100", value)
+		}
+		return n, nil
+	}
+	amount, err := model.ParseMoney(value.String(), currency)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount: %w", err)
+	}
+	if amount.IsNegative() {
+		return 0, fmt.Errorf("invalid amount %q, must not be negative", value)
+	}
+	return amount.Amount, nil
+}
+
+// formatShareValue is the reverse of parseShareValue.
+func formatShareValue(value int64, percentage bool, currency string) string {
+	if percentage {
+		return strconv.FormatInt(value, 10)
+	}
+	return model.NewMoney(value, currency).String()
+}
+
+// currencyOf returns the currency of the first amount that has one.
+func currencyOf(amounts ...model.Money) string {
+	for _, m := range amounts {
+		if m.Currency != "" {
+			return m.Currency
+		}
+	}
+	return model.DefaultCurrency
+}
+
+// inCurrency fills in the currency of zero amounts so they format consistently.
+func inCurrency(m model.Money, currency string) model.Money {
+	if m.Currency == "" {
+		m.Currency = currency
+	}
+	return m
+}
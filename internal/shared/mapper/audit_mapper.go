@@ -0,0 +1,43 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+)
+
+func ToAuditEntryResponse(entry model.AuditEntry) dto.AuditEntryResponse {
+	resp := dto.AuditEntryResponse{
+		ID:             entry.ID.String(),
+		SubscriptionID: entry.SubscriptionID.String(),
+		Actor:          entry.Actor,
+		Action:         string(entry.Action),
+		CreatedAt:      entry.CreatedAt.Format(time.RFC3339),
+	}
+	if entry.Before != nil {
+		before := ToSubscriptionResponse(*entry.Before)
+		resp.Before = &before
+	}
+	if entry.After != nil {
+		after := ToSubscriptionResponse(*entry.After)
+		resp.After = &after
+	}
+
+	if entry.PriceChange != nil {
+		change := ToPriceChangeResponse(*entry.PriceChange)
+		resp.PriceChange = &change
+	}
+	var currency string
+	if entry.After != nil {
+		currency = entry.After.Price.Currency
+	}
+	for _, m := range entry.Members {
+		resp.Members = append(resp.Members, ToSubscriptionMemberResponse(*m, currency))
+	}
+	if entry.Discount != nil {
+		discount := ToDiscountResponse(*entry.Discount, currency)
+		resp.Discount = &discount
+	}
+	return resp
+}
@@ -0,0 +1,98 @@
+package mapper
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+)
+
+// ToCurrencyFilter normalizes the optional currency of cost calculations, an ISO 4217 code.
+func ToCurrencyFilter(value string) (*string, error) {
+	if value == "" {
+		return nil, nil
+	}
+	value = strings.ToUpper(value)
+	if len(value) != 3 || strings.Trim(value, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return nil, errors.New("invalid currency, use an ISO 4217 code such as RUB")
+	}
+	return &value, nil
+}
+
+func ToTotalCostResponse(total model.CostBreakdown) dto.TotalCostResponse {
+	currency := currencyOf(total.BeforeDiscount, total.Gross)
+	return dto.TotalCostResponse{
+		TotalCost:      inCurrency(total.Gross, currency),
+		BeforeDiscount: inCurrency(total.BeforeDiscount, currency),
+		Discount:       inCurrency(total.Discount, currency),
+		Net:            inCurrency(total.Net, currency),
+		Tax:            inCurrency(total.Tax, currency),
+		Gross:          inCurrency(total.Gross, currency),
+		Currency:       currency,
+	}
+}
+
+func ToForecastResponse(forecast []model.MonthlyCost) dto.ForecastResponse {
+	var total model.Money
+	for _, m := range forecast {
+		// Валюта всех месяцев уже сверена при расчёте прогноза
+		total, _ = total.Add(m.Total)
+	}
+	currency := currencyOf(total)
+
+	resp := dto.ForecastResponse{
+		Total:    inCurrency(total, currency),
+		Currency: currency,
+		Months:   make([]dto.MonthlyCostResponse, 0, len(forecast)),
+	}
+	for _, m := range forecast {
+		resp.Months = append(resp.Months, dto.MonthlyCostResponse{
+			Month:     m.Month.Format(monthLayout),
+			Total:     inCurrency(m.Total, currency),
+			Committed: inCurrency(m.Committed, currency),
+			Projected: inCurrency(m.Projected, currency),
+		})
+	}
+	if len(forecast) > 0 {
+		resp.From = forecast[0].Month.Format(monthLayout)
+		resp.To = forecast[len(forecast)-1].Month.Format(monthLayout)
+	}
+	return resp
+}
+
+func ToCostComparisonResponse(comparison model.CostComparison) dto.CostComparisonResponse {
+	currency := currencyOf(comparison.BaseTotal, comparison.CurrentTotal)
+	resp := dto.CostComparisonResponse{
+		Base: dto.PeriodCostResponse{
+			From:  comparison.Base.From.Format(monthLayout),
+			To:    comparison.Base.To.Format(monthLayout),
+			Total: inCurrency(comparison.BaseTotal, currency),
+		},
+		Current: dto.PeriodCostResponse{
+			From:  comparison.Current.From.Format(monthLayout),
+			To:    comparison.Current.To.Format(monthLayout),
+			Total: inCurrency(comparison.CurrentTotal, currency),
+		},
+		Delta:    inCurrency(comparison.Delta, currency),
+		Currency: currency,
+		Services: make([]dto.ServiceCostChangeResponse, 0, len(comparison.Services)),
+	}
+	if percent, ok := comparison.DeltaPercent(); ok {
+		percent = math.Round(percent*100) / 100
+		resp.DeltaPercent = &percent
+	}
+	for _, svc := range comparison.Services {
+		resp.Services = append(resp.Services, dto.ServiceCostChangeResponse{
+			ServiceName:            svc.ServiceName,
+			BaseTotal:              inCurrency(svc.BaseTotal, currency),
+			CurrentTotal:           inCurrency(svc.CurrentTotal, currency),
+			Delta:                  inCurrency(svc.Delta, currency),
+			NewSubscriptions:       inCurrency(svc.NewSubscriptions, currency),
+			CancelledSubscriptions: inCurrency(svc.CancelledSubscriptions, currency),
+			PriceChanges:           inCurrency(svc.PriceChanges, currency),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,107 @@
+package mapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+)
+
+// csvFields are the CreateSubscriptionRequest JSON names accepted as CSV columns.
+var csvFields = map[string]struct{}{
+	"service_name":       {},
+	"price":              {},
+	"currency":           {},
+	"user_id":            {},
+	"start_date":         {},
+	"end_date":           {},
+	"billing_period":     {},
+	"trial_end_date":     {},
+	"tax_rate":           {},
+	"price_includes_tax": {},
+}
+
+// CSVColumns resolves the CSV header into field names. mapping renames file columns
+// to fields ("Сервис" -> "service_name"); unmapped columns must already be field names
+// and unknown ones are ignored.
+func CSVColumns(header []string, mapping map[string]string) ([]string, error) {
+	for column, field := range mapping {
+		if _, ok := csvFields[field]; !ok {
+			return nil, fmt.Errorf("mapping for column %q: unknown field %q", column, field)
+		}
+	}
+
+	columns := make([]string, len(header))
+	seen := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		field, ok := mapping[name]
+		if !ok {
+			field = strings.ToLower(name)
+		}
+		if _, ok := csvFields[field]; !ok {
+			continue
+		}
+		if seen[field] {
+			return nil, fmt.Errorf("field %q is mapped more than once", field)
+		}
+		seen[field] = true
+		columns[i] = field
+	}
+
+	for _, required := range []string{"service_name", "price", "user_id", "start_date"} {
+		if !seen[required] {
+			return nil, fmt.Errorf("missing required column %q", required)
+		}
+	}
+	return columns, nil
+}
+
+// ToCreateSubscriptionRequest builds a request from a CSV record using columns from CSVColumns.
+func ToCreateSubscriptionRequest(columns []string, record []string) (dto.CreateSubscriptionRequest, error) {
+	var req dto.CreateSubscriptionRequest
+	if len(record) != len(columns) {
+		return req, fmt.Errorf("expected %d columns, got %d", len(columns), len(record))
+	}
+
+	for i, field := range columns {
+		value := strings.TrimSpace(record[i])
+		if field == "" || value == "" {
+			continue
+		}
+		switch field {
+		case "service_name":
+			req.ServiceName = value
+		case "price":
+			req.Price = json.Number(strings.ReplaceAll(value, ",", "."))
+		case "currency":
+			req.Currency = strings.ToUpper(value)
+		case "user_id":
+			req.UserID = value
+		case "start_date":
+			req.StartDate = value
+		case "end_date":
+			req.EndDate = value
+		case "billing_period":
+			req.BillingPeriod = strings.ToLower(value)
+		case "trial_end_date":
+			req.TrialEndDate = value
+		case "tax_rate":
+			rate, err := strconv.ParseFloat(strings.ReplaceAll(value, ",", "."), 64)
+			if err != nil {
+				return req, errors.New("invalid tax_rate")
+			}
+			req.TaxRate = rate
+		case "price_includes_tax":
+			includes, err := strconv.ParseBool(value)
+			if err != nil {
+				return req, errors.New("invalid price_includes_tax")
+			}
+			req.PriceIncludesTax = &includes
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,51 @@
+package mapper
+
+import (
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/google/uuid"
+)
+
+// ToDiscountModel maps a discount of a subscription billed in currency.
+func ToDiscountModel(subscriptionID uuid.UUID, currency string, dto dto.CreateDiscountRequest) (*model.Discount, error) {
+	startDate, err := parseMonth(dto.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseOptionalMonth(dto.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	kind := model.DiscountKind(dto.Kind)
+	value, err := parseShareValue(dto.Value, kind == model.DiscountPercentage, currency)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Discount{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		Kind:           kind,
+		Value:          value,
+		Code:           dto.Code,
+		StartDate:      startDate,
+		EndDate:        endDate,
+	}, nil
+}
+
+func ToDiscountResponse(discount model.Discount, currency string) dto.DiscountResponse {
+	resp := dto.DiscountResponse{
+		ID:             discount.ID.String(),
+		SubscriptionID: discount.SubscriptionID.String(),
+		Kind:           string(discount.Kind),
+		Value:          formatShareValue(discount.Value, discount.Kind == model.DiscountPercentage, currency),
+		Code:           discount.Code,
+		StartDate:      discount.StartDate.Format(monthLayout),
+	}
+	if discount.EndDate != nil {
+		resp.EndDate = discount.EndDate.Format(monthLayout)
+	}
+	return resp
+}
@@ -0,0 +1,110 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+)
+
+// Колонки выгрузок фиксированы: потребители таблиц полагаются на их порядок.
+// Значения строк — string, float64, bool, model.Money или time.Time (месяц),
+// форматирование под CSV/XLSX делает слой доставки.
+
+var SubscriptionExportColumns = []string{
+	"id", "service_name", "user_id", "start_date", "end_date", "billing_period",
+	"trial_end_date", "price", "currency", "tax_rate", "price_includes_tax", "status",
+}
+
+func ToSubscriptionExportRow(sub model.Subscription) []any {
+	return []any{
+		sub.ID.String(),
+		sub.ServiceName,
+		sub.UserID.String(),
+		sub.StartDate,
+		optionalMonth(sub.EndDate),
+		string(sub.BillingPeriod),
+		optionalMonth(sub.TrialEndDate),
+		sub.Price,
+		sub.Price.Currency,
+		float64(sub.TaxRate) / 100,
+		sub.PriceIncludesTax,
+		string(sub.Status),
+	}
+}
+
+var CostReportExportColumns = []string{
+	"id", "service_name", "user_id", "start_date", "end_date", "billing_period",
+	"currency", "before_discount", "discount", "net", "tax", "gross",
+}
+
+func ToCostReportExportRow(line model.SubscriptionCost) []any {
+	sub := line.Subscription
+	currency := sub.Price.Currency
+	return []any{
+		sub.ID.String(),
+		sub.ServiceName,
+		sub.UserID.String(),
+		sub.StartDate,
+		optionalMonth(sub.EndDate),
+		string(sub.BillingPeriod),
+		currency,
+		inCurrency(line.Cost.BeforeDiscount, currency),
+		inCurrency(line.Cost.Discount, currency),
+		inCurrency(line.Cost.Net, currency),
+		inCurrency(line.Cost.Tax, currency),
+		inCurrency(line.Cost.Gross, currency),
+	}
+}
+
+var ForecastExportColumns = []string{"month", "currency", "committed", "projected", "total"}
+
+func ToForecastExportRows(forecast []model.MonthlyCost) [][]any {
+	var total model.Money
+	for _, m := range forecast {
+		total, _ = total.Add(m.Total)
+	}
+	currency := currencyOf(total)
+
+	rows := make([][]any, 0, len(forecast))
+	for _, m := range forecast {
+		rows = append(rows, []any{
+			m.Month,
+			currency,
+			inCurrency(m.Committed, currency),
+			inCurrency(m.Projected, currency),
+			inCurrency(m.Total, currency),
+		})
+	}
+	return rows
+}
+
+var CostComparisonExportColumns = []string{
+	"service_name", "currency", "base_total", "current_total", "delta",
+	"new_subscriptions", "cancelled_subscriptions", "price_changes",
+}
+
+func ToCostComparisonExportRows(comparison model.CostComparison) [][]any {
+	currency := currencyOf(comparison.BaseTotal, comparison.CurrentTotal)
+	rows := make([][]any, 0, len(comparison.Services))
+	for _, svc := range comparison.Services {
+		rows = append(rows, []any{
+			svc.ServiceName,
+			currency,
+			inCurrency(svc.BaseTotal, currency),
+			inCurrency(svc.CurrentTotal, currency),
+			inCurrency(svc.Delta, currency),
+			inCurrency(svc.NewSubscriptions, currency),
+			inCurrency(svc.CancelledSubscriptions, currency),
+			inCurrency(svc.PriceChanges, currency),
+		})
+	}
+	return rows
+}
+
+// optionalMonth returns nil for an absent date so it is exported as an empty cell.
+func optionalMonth(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
@@ -0,0 +1,42 @@
+package mapper
+
+import (
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+)
+
+func ToJobResponse(job model.JobInfo) dto.JobResponse {
+	resp := dto.JobResponse{
+		Name:     job.Name,
+		Schedule: job.Schedule,
+	}
+	if !job.NextRunAt.IsZero() {
+		resp.NextRunAt = job.NextRunAt.Format(time.RFC3339)
+	}
+	if job.LastRun != nil {
+		lastRun := ToJobRunResponse(*job.LastRun)
+		resp.LastRun = &lastRun
+	}
+	return resp
+}
+
+func ToJobRunResponse(run model.JobRun) dto.JobRunResponse {
+	resp := dto.JobRunResponse{
+		ID:        run.ID.String(),
+		Job:       run.Job,
+		Trigger:   string(run.Trigger),
+		Status:    string(run.Status),
+		StartedAt: run.StartedAt.Format(time.RFC3339),
+		Result:    run.Result,
+		Error:     run.Error,
+	}
+	if run.ScheduledAt != nil {
+		resp.ScheduledAt = run.ScheduledAt.Format(time.RFC3339)
+	}
+	if run.FinishedAt != nil {
+		resp.FinishedAt = run.FinishedAt.Format(time.RFC3339)
+	}
+	return resp
+}
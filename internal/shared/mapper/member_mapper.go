@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/google/uuid"
+)
+
+// ToSubscriptionMembers maps members of a subscription billed in currency.
+func ToSubscriptionMembers(subscriptionID uuid.UUID, currency string, req dto.SetMembersRequest) ([]*model.SubscriptionMember, error) {
+	members := make([]*model.SubscriptionMember, 0, len(req.Members))
+	for _, m := range req.Members {
+		splitType := model.SplitType(m.SplitType)
+		value, err := parseShareValue(m.Value, splitType == model.SplitPercentage, currency)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, &model.SubscriptionMember{
+			SubscriptionID: subscriptionID,
+			UserID:         uuid.MustParse(m.UserID),
+			SplitType:      splitType,
+			Value:          value,
+		})
+	}
+	return members, nil
+}
+
+func ToSubscriptionMemberResponse(member model.SubscriptionMember, currency string) dto.SubscriptionMemberResponse {
+	return dto.SubscriptionMemberResponse{
+		UserID:    member.UserID.String(),
+		SplitType: string(member.SplitType),
+		Value:     formatShareValue(member.Value, member.SplitType == model.SplitPercentage, currency),
+	}
+}
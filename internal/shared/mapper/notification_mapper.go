@@ -0,0 +1,27 @@
+package mapper
+
+import (
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/google/uuid"
+)
+
+func ToNotificationPreferencesModel(userID uuid.UUID, req dto.NotificationPreferencesRequest) *model.NotificationPreferences {
+	return &model.NotificationPreferences{
+		UserID:            userID,
+		Email:             req.Email,
+		Enabled:           req.Enabled,
+		RenewalDaysBefore: req.RenewalDaysBefore,
+		TrialDaysBefore:   req.TrialDaysBefore,
+	}
+}
+
+func ToNotificationPreferencesResponse(prefs model.NotificationPreferences) dto.NotificationPreferencesResponse {
+	return dto.NotificationPreferencesResponse{
+		UserID:            prefs.UserID.String(),
+		Email:             prefs.Email,
+		Enabled:           prefs.Enabled,
+		RenewalDaysBefore: prefs.RenewalDaysBefore,
+		TrialDaysBefore:   prefs.TrialDaysBefore,
+	}
+}
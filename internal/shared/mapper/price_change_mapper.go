@@ -0,0 +1,42 @@
+package mapper
+
+import (
+	"fmt"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/google/uuid"
+)
+
+// ToPriceChangeModel maps a price change of a subscription billed in currency.
+func ToPriceChangeModel(subscriptionID uuid.UUID, currency string, dto dto.CreatePriceChangeRequest) (*model.PriceChange, error) {
+	effectiveFrom, err := parseMonth(dto.EffectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+
+	price, err := model.ParseMoney(dto.Price.String(), currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price: %w", err)
+	}
+	if price.IsNegative() {
+		return nil, fmt.Errorf("invalid price: must not be negative")
+	}
+
+	return &model.PriceChange{
+		ID:             uuid.New(),
+		SubscriptionID: subscriptionID,
+		Price:          price,
+		EffectiveFrom:  effectiveFrom,
+	}, nil
+}
+
+func ToPriceChangeResponse(change model.PriceChange) dto.PriceChangeResponse {
+	return dto.PriceChangeResponse{
+		ID:             change.ID.String(),
+		SubscriptionID: change.SubscriptionID.String(),
+		Price:          change.Price,
+		Currency:       change.Price.Currency,
+		EffectiveFrom:  change.EffectiveFrom.Format(monthLayout),
+	}
+}
@@ -0,0 +1,23 @@
+package mapper
+
+import (
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+)
+
+func ToRecommendationResponse(rec model.Recommendation) dto.RecommendationResponse {
+	ids := make([]string, 0, len(rec.SubscriptionIDs))
+	for _, id := range rec.SubscriptionIDs {
+		ids = append(ids, id.String())
+	}
+	currency := currencyOf(rec.MonthlySavings)
+	return dto.RecommendationResponse{
+		Rule:            rec.Rule,
+		ServiceName:     rec.ServiceName,
+		SubscriptionIDs: ids,
+		Score:           rec.Score,
+		MonthlySavings:  inCurrency(rec.MonthlySavings, currency),
+		Currency:        currency,
+		Explanation:     rec.Explanation,
+	}
+}
@@ -0,0 +1,41 @@
+package mapper
+
+import (
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+)
+
+func ToSubscriptionCandidateResponse(candidate model.SubscriptionCandidate) dto.SubscriptionCandidateResponse {
+	resp := dto.SubscriptionCandidateResponse{
+		ServiceName:   candidate.ServiceName,
+		Merchant:      candidate.Merchant,
+		Price:         candidate.Price,
+		Currency:      candidate.Price.Currency,
+		BillingPeriod: string(candidate.BillingPeriod),
+		StartDate:     candidate.StartDate.Format(monthLayout),
+		LastCharge:    candidate.LastCharge.Format("2006-01-02"),
+		Occurrences:   candidate.Occurrences,
+		Confidence:    candidate.Confidence,
+	}
+	if candidate.EndDate != nil {
+		resp.EndDate = candidate.EndDate.Format(monthLayout)
+	}
+	if candidate.ExistingSubscriptionID != nil {
+		resp.ExistingSubscriptionID = candidate.ExistingSubscriptionID.String()
+	}
+	return resp
+}
+
+// ToSubscriptionModelFromCandidate goes through the CreateSubscription mapping
+// so confirmed candidates get the same defaults as manually created subscriptions.
+func ToSubscriptionModelFromCandidate(userID string, req dto.ConfirmCandidateRequest) (*model.Subscription, error) {
+	return ToSubscriptionModel(dto.CreateSubscriptionRequest{
+		ServiceName:   req.ServiceName,
+		Price:         req.Price,
+		Currency:      req.Currency,
+		UserID:        userID,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		BillingPeriod: req.BillingPeriod,
+	})
+}
@@ -1,6 +1,9 @@
 package mapper
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
@@ -8,41 +11,119 @@ import (
 	"github.com/google/uuid"
 )
 
+const monthLayout = "01-2006"
+
+var errInvalidMonth = errors.New("invalid date format, use MM-YYYY")
+
 func ToSubscriptionModel(dto dto.CreateSubscriptionRequest) (*model.Subscription, error) {
-	startDate, err := time.Parse("01-2006", dto.StartDate)
+	startDate, err := parseMonth(dto.StartDate)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, err := parseOptionalMonth(dto.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	trialEndDate, err := parseOptionalMonth(dto.TrialEndDate)
 	if err != nil {
 		return nil, err
 	}
 
-	var endDate *time.Time
-	if dto.EndDate != "" {
-		t, err := time.Parse("01-2006", dto.EndDate)
-		if err != nil {
-			return nil, err
-		}
-		endDate = &t
+	currency := dto.Currency
+	if currency == "" {
+		currency = model.DefaultCurrency
+	}
+	price, err := model.ParseMoney(dto.Price.String(), currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid price: %w", err)
+	}
+	if price.IsNegative() {
+		return nil, errors.New("invalid price: must not be negative")
+	}
+
+	billingPeriod := model.BillingPeriod(dto.BillingPeriod)
+	if billingPeriod == "" {
+		billingPeriod = model.BillingPeriodMonthly
+	}
+
+	priceIncludesTax := true
+	if dto.PriceIncludesTax != nil {
+		priceIncludesTax = *dto.PriceIncludesTax
 	}
 
 	return &model.Subscription{
-		ID:          uuid.New(),
-		ServiceName: dto.ServiceName,
-		Price:       dto.Price,
-		UserID:      uuid.MustParse(dto.UserID),
-		StartDate:   startDate,
-		EndDate:     endDate,
+		ID:               uuid.New(),
+		ServiceName:      dto.ServiceName,
+		Price:            price,
+		UserID:           uuid.MustParse(dto.UserID),
+		StartDate:        startDate,
+		EndDate:          endDate,
+		BillingPeriod:    billingPeriod,
+		TrialEndDate:     trialEndDate,
+		TaxRate:          int(math.Round(dto.TaxRate * 100)),
+		PriceIncludesTax: priceIncludesTax,
 	}, nil
 }
 
 func ToSubscriptionResponse(sub model.Subscription) dto.SubscriptionResponse {
 	resp := dto.SubscriptionResponse{
-		ID:          sub.ID.String(),
-		ServiceName: sub.ServiceName,
-		Price:       sub.Price,
-		UserID:      sub.UserID.String(),
-		StartDate:   sub.StartDate.Format("01-2006"),
+		ID:               sub.ID.String(),
+		ServiceName:      sub.ServiceName,
+		Price:            sub.Price,
+		Currency:         sub.Price.Currency,
+		UserID:           sub.UserID.String(),
+		StartDate:        sub.StartDate.Format(monthLayout),
+		BillingPeriod:    string(sub.BillingPeriod),
+		TaxRate:          float64(sub.TaxRate) / 100,
+		PriceIncludesTax: sub.PriceIncludesTax,
+		IsDeleted:        sub.IsDeleted,
+		DeletionReason:   string(sub.DeletionReason),
+		Status:           string(sub.Status),
 	}
 	if sub.EndDate != nil {
-		resp.EndDate = sub.EndDate.Format("01-2006")
+		resp.EndDate = sub.EndDate.Format(monthLayout)
+	}
+	if sub.TrialEndDate != nil {
+		resp.TrialEndDate = sub.TrialEndDate.Format(monthLayout)
+	}
+	if sub.CancelledAt != nil {
+		resp.CancelledAt = sub.CancelledAt.Format(time.RFC3339)
+	}
+	if sub.EndedAt != nil {
+		resp.EndedAt = sub.EndedAt.Format(time.RFC3339)
 	}
 	return resp
 }
+
+func ToDuplicateGroupResponse(group model.DuplicateGroup) dto.DuplicateGroupResponse {
+	resp := dto.DuplicateGroupResponse{
+		UserID:        group.UserID.String(),
+		ServiceName:   group.ServiceName,
+		Subscriptions: make([]dto.SubscriptionResponse, 0, len(group.Subscriptions)),
+	}
+	for _, sub := range group.Subscriptions {
+		resp.Subscriptions = append(resp.Subscriptions, ToSubscriptionResponse(*sub))
+	}
+	return resp
+}
+
+func parseMonth(value string) (time.Time, error) {
+	t, err := time.Parse(monthLayout, value)
+	if err != nil {
+		return time.Time{}, errInvalidMonth
+	}
+	return t, nil
+}
+
+func parseOptionalMonth(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := parseMonth(value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
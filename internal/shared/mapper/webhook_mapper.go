@@ -0,0 +1,103 @@
+package mapper
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Babushkin05/subscription-organizer/internal/domain/model"
+	"github.com/Babushkin05/subscription-organizer/internal/shared/dto"
+	"github.com/google/uuid"
+)
+
+func ToWebhookEndpointModel(req dto.CreateWebhookRequest) (*model.WebhookEndpoint, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid url: must be an absolute http or https URL")
+	}
+
+	known := make(map[model.EventType]bool, len(model.EventTypes))
+	for _, t := range model.EventTypes {
+		known[t] = true
+	}
+	types := make([]model.EventType, 0, len(req.EventTypes))
+	seen := make(map[model.EventType]bool, len(req.EventTypes))
+	for _, value := range req.EventTypes {
+		t := model.EventType(value)
+		if !known[t] {
+			return nil, fmt.Errorf("unknown event type %q", value)
+		}
+		if !seen[t] {
+			seen[t] = true
+			types = append(types, t)
+		}
+	}
+
+	return &model.WebhookEndpoint{
+		ID:         uuid.New(),
+		URL:        u.String(),
+		Secret:     req.Secret,
+		EventTypes: types,
+	}, nil
+}
+
+// ToWebhookResponse omits the secret unless withSecret is set.
+func ToWebhookResponse(endpoint model.WebhookEndpoint, withSecret bool) dto.WebhookResponse {
+	resp := dto.WebhookResponse{
+		ID:         endpoint.ID.String(),
+		URL:        endpoint.URL,
+		EventTypes: make([]string, 0, len(endpoint.EventTypes)),
+		CreatedAt:  endpoint.CreatedAt.Format(time.RFC3339),
+	}
+	if withSecret {
+		resp.Secret = endpoint.Secret
+	}
+	for _, t := range endpoint.EventTypes {
+		resp.EventTypes = append(resp.EventTypes, string(t))
+	}
+	return resp
+}
+
+func ToWebhookDeliveryResponse(delivery model.WebhookDelivery, attempts []*model.WebhookAttempt) dto.WebhookDeliveryResponse {
+	resp := dto.WebhookDeliveryResponse{
+		ID:        delivery.ID.String(),
+		WebhookID: delivery.EndpointID.String(),
+		EventID:   delivery.Event.ID.String(),
+		EventType: string(delivery.Event.Type),
+		Status:    string(delivery.Status),
+		Attempts:  delivery.Attempts,
+		CreatedAt: delivery.CreatedAt.Format(time.RFC3339),
+	}
+	if delivery.Status == model.DeliveryPending {
+		resp.NextAttemptAt = delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+	for _, a := range attempts {
+		resp.Log = append(resp.Log, dto.WebhookAttemptResponse{
+			AttemptedAt: a.AttemptedAt.Format(time.RFC3339),
+			StatusCode:  a.StatusCode,
+			Error:       a.Error,
+			DurationMS:  a.Duration.Milliseconds(),
+		})
+	}
+	return resp
+}
+
+func ToEventPayload(event model.Event) dto.EventPayload {
+	payload := dto.EventPayload{
+		ID:         event.ID.String(),
+		Type:       string(event.Type),
+		OccurredAt: event.OccurredAt.UTC().Format(time.RFC3339),
+	}
+	if event.Subscription != nil {
+		sub := ToSubscriptionResponse(*event.Subscription)
+		payload.Subscription = &sub
+	}
+	if event.PriceChange != nil {
+		change := ToPriceChangeResponse(*event.PriceChange)
+		payload.PriceChange = &change
+	}
+	if event.RenewsAt != nil {
+		payload.RenewsAt = event.RenewsAt.Format(monthLayout)
+	}
+	return payload
+}
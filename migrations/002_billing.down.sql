@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS subscription_price_changes;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS trial_end_date,
+    DROP COLUMN IF EXISTS billing_period;
@@ -0,0 +1,14 @@
+ALTER TABLE subscriptions
+    ADD COLUMN IF NOT EXISTS billing_period TEXT NOT NULL DEFAULT 'monthly'
+        CHECK (billing_period IN ('monthly', 'quarterly', 'yearly')),
+    ADD COLUMN IF NOT EXISTS trial_end_date DATE;
+
+CREATE TABLE IF NOT EXISTS subscription_price_changes (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    price INTEGER NOT NULL CHECK (price >= 0),
+    effective_from DATE NOT NULL,
+    UNIQUE (subscription_id, effective_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_changes_subscription_id ON subscription_price_changes(subscription_id);
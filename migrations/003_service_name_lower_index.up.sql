@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_subscriptions_user_service_lower ON subscriptions(user_id, lower(trim(service_name)));
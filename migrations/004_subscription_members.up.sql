@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS subscription_members (
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    split_type TEXT NOT NULL CHECK (split_type IN ('equal', 'percentage', 'fixed')),
+    value INTEGER NOT NULL DEFAULT 0 CHECK (value >= 0),
+    PRIMARY KEY (subscription_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_members_user_id ON subscription_members(user_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS subscription_discounts (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('percentage', 'fixed')),
+    value INTEGER NOT NULL CHECK (value > 0),
+    code TEXT NOT NULL DEFAULT '',
+    start_date DATE NOT NULL,
+    end_date DATE,
+    CHECK (kind <> 'percentage' OR value <= 100),
+    CHECK (end_date IS NULL OR end_date >= start_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_subscription_discounts_subscription_id ON subscription_discounts(subscription_id);
@@ -0,0 +1,3 @@
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS price_includes_tax,
+    DROP COLUMN IF EXISTS tax_rate;
@@ -0,0 +1,14 @@
+UPDATE subscription_members SET value = value / 100 WHERE split_type = 'fixed';
+ALTER TABLE subscription_members
+    ALTER COLUMN value TYPE INTEGER;
+
+UPDATE subscription_discounts SET value = value / 100 WHERE kind = 'fixed';
+ALTER TABLE subscription_discounts
+    ALTER COLUMN value TYPE INTEGER;
+
+ALTER TABLE subscription_price_changes
+    ALTER COLUMN price TYPE INTEGER USING (price / 100)::INTEGER;
+
+ALTER TABLE subscriptions
+    DROP COLUMN IF EXISTS currency,
+    ALTER COLUMN price TYPE INTEGER USING (price / 100)::INTEGER;
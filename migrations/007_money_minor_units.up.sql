@@ -0,0 +1,15 @@
+-- Суммы хранятся в минимальных единицах валюты (копейки, центы)
+ALTER TABLE subscriptions
+    ALTER COLUMN price TYPE BIGINT USING price::BIGINT * 100,
+    ADD COLUMN IF NOT EXISTS currency TEXT NOT NULL DEFAULT 'RUB';
+
+ALTER TABLE subscription_price_changes
+    ALTER COLUMN price TYPE BIGINT USING price::BIGINT * 100;
+
+ALTER TABLE subscription_discounts
+    ALTER COLUMN value TYPE BIGINT;
+UPDATE subscription_discounts SET value = value * 100 WHERE kind = 'fixed';
+
+ALTER TABLE subscription_members
+    ALTER COLUMN value TYPE BIGINT;
+UPDATE subscription_members SET value = value * 100 WHERE split_type = 'fixed';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS subscription_audit_log;
+DROP FUNCTION IF EXISTS forbid_audit_log_changes();
@@ -0,0 +1,25 @@
+-- Без внешнего ключа: записи журнала переживают удаление подписки
+CREATE TABLE IF NOT EXISTS subscription_audit_log (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL,
+    actor TEXT NOT NULL,
+    action TEXT NOT NULL,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_subscription ON subscription_audit_log(subscription_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_actor ON subscription_audit_log(actor, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at ON subscription_audit_log(created_at);
+
+-- Журнал только дополняется
+CREATE OR REPLACE FUNCTION forbid_audit_log_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'subscription_audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER subscription_audit_log_append_only
+    BEFORE UPDATE OR DELETE ON subscription_audit_log
+    FOR EACH ROW EXECUTE FUNCTION forbid_audit_log_changes();
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY,
+    email TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT false,
+    renewal_days_before INTEGER NOT NULL DEFAULT 3,
+    trial_days_before INTEGER NOT NULL DEFAULT 3,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Отправленные напоминания: уникальный ключ не даёт отправить одно напоминание дважды
+CREATE TABLE IF NOT EXISTS sent_reminders (
+    channel TEXT NOT NULL,
+    subscription_id UUID NOT NULL,
+    kind TEXT NOT NULL,
+    due_date DATE NOT NULL,
+    user_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'sending',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at TIMESTAMPTZ,
+    PRIMARY KEY (channel, subscription_id, kind, due_date)
+);
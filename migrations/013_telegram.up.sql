@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS telegram_link_tokens (
+    token TEXT PRIMARY KEY,
+    user_id UUID NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS telegram_chats (
+    chat_id BIGINT PRIMARY KEY,
+    user_id UUID NOT NULL,
+    linked_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_telegram_chats_user_id ON telegram_chats(user_id);
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS subscriptions_deleted_at ON subscriptions;
+DROP FUNCTION IF EXISTS set_subscription_deleted_at();
+DROP INDEX IF EXISTS idx_subscriptions_deleted_at;
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS deleted_at;
+DROP TABLE IF EXISTS job_runs;
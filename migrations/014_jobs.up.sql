@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS job_runs (
+    id UUID PRIMARY KEY,
+    job TEXT NOT NULL,
+    trigger TEXT NOT NULL,
+    status TEXT NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    finished_at TIMESTAMPTZ,
+    result TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS idx_job_runs_job ON job_runs(job, started_at DESC);
+
+-- Момент удаления нужен, чтобы удалять записи окончательно по истечении срока хранения
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+UPDATE subscriptions SET deleted_at = now() WHERE is_deleted AND deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_deleted_at ON subscriptions(deleted_at) WHERE is_deleted;
+
+CREATE OR REPLACE FUNCTION set_subscription_deleted_at() RETURNS trigger AS $$
+BEGIN
+    IF NEW.is_deleted AND NOT OLD.is_deleted THEN
+        NEW.deleted_at := now();
+    ELSIF NOT NEW.is_deleted THEN
+        NEW.deleted_at := NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER subscriptions_deleted_at
+    BEFORE UPDATE OF is_deleted ON subscriptions
+    FOR EACH ROW EXECUTE FUNCTION set_subscription_deleted_at();
@@ -0,0 +1,12 @@
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'active';
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMPTZ;
+
+-- Те же правила, что в model.Subscription.StatusAt; дальше статусы обновляет сервис
+UPDATE subscriptions SET status = CASE
+    WHEN end_date < date_trunc('month', current_date) THEN 'expired'
+    WHEN start_date > date_trunc('month', current_date) THEN 'scheduled'
+    WHEN trial_end_date > date_trunc('month', current_date) THEN 'trial'
+    ELSE 'active'
+END;
+
+CREATE INDEX IF NOT EXISTS idx_subscriptions_status ON subscriptions(status);
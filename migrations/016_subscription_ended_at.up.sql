@@ -0,0 +1,2 @@
+-- Момент немедленной отмены: оплаченный месяц остаётся в истории, а подписка уже закончилась
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS ended_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS deletion_reason TEXT NOT NULL DEFAULT '';
+
+-- Раньше удалённые подписки не учитывались в расходах, так и оставляем
+UPDATE subscriptions SET deletion_reason = 'mistake' WHERE is_deleted AND deletion_reason = '';
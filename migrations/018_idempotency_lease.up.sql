@@ -0,0 +1,4 @@
+-- Пока исходный запрос выполняется, ключ занят до locked_until, потом повтор может его перехватить
+ALTER TABLE idempotency_keys ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+UPDATE idempotency_keys SET locked_until = created_at WHERE status_code IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE subscription_audit_log DROP COLUMN IF EXISTS details;
@@ -0,0 +1,2 @@
+-- Изменения связанных записей (цены, участники, скидки): сама подписка в before и after не меняется
+ALTER TABLE subscription_audit_log ADD COLUMN IF NOT EXISTS details JSONB;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(seq) WHERE published_at IS NULL;
+
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS failed_at;
+ALTER TABLE outbox_events DROP COLUMN IF EXISTS next_attempt_at;
@@ -0,0 +1,6 @@
+-- Упавшие события повторяются с растущей задержкой, после events.max_attempts попыток откладываются в failed_at
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ;
+ALTER TABLE outbox_events ADD COLUMN IF NOT EXISTS failed_at TIMESTAMPTZ;
+
+DROP INDEX IF EXISTS idx_outbox_events_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_events_pending ON outbox_events(seq) WHERE published_at IS NULL AND failed_at IS NULL;
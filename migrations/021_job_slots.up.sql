@@ -0,0 +1,4 @@
+-- Запуск по расписанию занимает слот один раз на все реплики
+ALTER TABLE job_runs ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_job_runs_slot ON job_runs(job, scheduled_at) WHERE scheduled_at IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Дата окончания до отмены, возвращается при возобновлении
+ALTER TABLE subscriptions ADD COLUMN IF NOT EXISTS cancelled_end_date DATE;
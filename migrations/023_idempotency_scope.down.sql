@@ -0,0 +1,7 @@
+-- Одинаковые ключи разных авторов не уместятся в прежний первичный ключ, записи живут недолго
+DELETE FROM idempotency_keys;
+
+ALTER TABLE idempotency_keys DROP CONSTRAINT IF EXISTS idempotency_keys_pkey;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS lease_token;
+ALTER TABLE idempotency_keys DROP COLUMN IF EXISTS scope;
+ALTER TABLE idempotency_keys ADD PRIMARY KEY (key);
@@ -0,0 +1,1614 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: subscription/v1/subscription.proto
+
+package subscriptionv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type BillingPeriod int32
+
+const (
+	BillingPeriod_BILLING_PERIOD_UNSPECIFIED BillingPeriod = 0
+	BillingPeriod_BILLING_PERIOD_MONTHLY     BillingPeriod = 1
+	BillingPeriod_BILLING_PERIOD_QUARTERLY   BillingPeriod = 2
+	BillingPeriod_BILLING_PERIOD_YEARLY      BillingPeriod = 3
+)
+
+// Enum value maps for BillingPeriod.
+var (
+	BillingPeriod_name = map[int32]string{
+		0: "BILLING_PERIOD_UNSPECIFIED",
+		1: "BILLING_PERIOD_MONTHLY",
+		2: "BILLING_PERIOD_QUARTERLY",
+		3: "BILLING_PERIOD_YEARLY",
+	}
+	BillingPeriod_value = map[string]int32{
+		"BILLING_PERIOD_UNSPECIFIED": 0,
+		"BILLING_PERIOD_MONTHLY":     1,
+		"BILLING_PERIOD_QUARTERLY":   2,
+		"BILLING_PERIOD_YEARLY":      3,
+	}
+)
+
+func (x BillingPeriod) Enum() *BillingPeriod {
+	p := new(BillingPeriod)
+	*p = x
+	return p
+}
+
+func (x BillingPeriod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BillingPeriod) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[0].Descriptor()
+}
+
+func (BillingPeriod) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[0]
+}
+
+func (x BillingPeriod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BillingPeriod.Descriptor instead.
+func (BillingPeriod) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{0}
+}
+
+type SubscriptionStatus int32
+
+const (
+	SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED SubscriptionStatus = 0
+	SubscriptionStatus_SUBSCRIPTION_STATUS_SCHEDULED   SubscriptionStatus = 1
+	SubscriptionStatus_SUBSCRIPTION_STATUS_TRIAL       SubscriptionStatus = 2
+	SubscriptionStatus_SUBSCRIPTION_STATUS_ACTIVE      SubscriptionStatus = 3
+	SubscriptionStatus_SUBSCRIPTION_STATUS_EXPIRED     SubscriptionStatus = 4
+	SubscriptionStatus_SUBSCRIPTION_STATUS_CANCELLED   SubscriptionStatus = 5
+)
+
+// Enum value maps for SubscriptionStatus.
+var (
+	SubscriptionStatus_name = map[int32]string{
+		0: "SUBSCRIPTION_STATUS_UNSPECIFIED",
+		1: "SUBSCRIPTION_STATUS_SCHEDULED",
+		2: "SUBSCRIPTION_STATUS_TRIAL",
+		3: "SUBSCRIPTION_STATUS_ACTIVE",
+		4: "SUBSCRIPTION_STATUS_EXPIRED",
+		5: "SUBSCRIPTION_STATUS_CANCELLED",
+	}
+	SubscriptionStatus_value = map[string]int32{
+		"SUBSCRIPTION_STATUS_UNSPECIFIED": 0,
+		"SUBSCRIPTION_STATUS_SCHEDULED":   1,
+		"SUBSCRIPTION_STATUS_TRIAL":       2,
+		"SUBSCRIPTION_STATUS_ACTIVE":      3,
+		"SUBSCRIPTION_STATUS_EXPIRED":     4,
+		"SUBSCRIPTION_STATUS_CANCELLED":   5,
+	}
+)
+
+func (x SubscriptionStatus) Enum() *SubscriptionStatus {
+	p := new(SubscriptionStatus)
+	*p = x
+	return p
+}
+
+func (x SubscriptionStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SubscriptionStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[1].Descriptor()
+}
+
+func (SubscriptionStatus) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[1]
+}
+
+func (x SubscriptionStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SubscriptionStatus.Descriptor instead.
+func (SubscriptionStatus) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{1}
+}
+
+type CancelMode int32
+
+const (
+	CancelMode_CANCEL_MODE_UNSPECIFIED   CancelMode = 0
+	CancelMode_CANCEL_MODE_AT_PERIOD_END CancelMode = 1
+	CancelMode_CANCEL_MODE_IMMEDIATELY   CancelMode = 2
+)
+
+// Enum value maps for CancelMode.
+var (
+	CancelMode_name = map[int32]string{
+		0: "CANCEL_MODE_UNSPECIFIED",
+		1: "CANCEL_MODE_AT_PERIOD_END",
+		2: "CANCEL_MODE_IMMEDIATELY",
+	}
+	CancelMode_value = map[string]int32{
+		"CANCEL_MODE_UNSPECIFIED":   0,
+		"CANCEL_MODE_AT_PERIOD_END": 1,
+		"CANCEL_MODE_IMMEDIATELY":   2,
+	}
+)
+
+func (x CancelMode) Enum() *CancelMode {
+	p := new(CancelMode)
+	*p = x
+	return p
+}
+
+func (x CancelMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CancelMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[2].Descriptor()
+}
+
+func (CancelMode) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[2]
+}
+
+func (x CancelMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CancelMode.Descriptor instead.
+func (CancelMode) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{2}
+}
+
+type DeletionReason int32
+
+const (
+	DeletionReason_DELETION_REASON_UNSPECIFIED DeletionReason = 0
+	DeletionReason_DELETION_REASON_MISTAKE     DeletionReason = 1
+	DeletionReason_DELETION_REASON_ENDED       DeletionReason = 2
+)
+
+// Enum value maps for DeletionReason.
+var (
+	DeletionReason_name = map[int32]string{
+		0: "DELETION_REASON_UNSPECIFIED",
+		1: "DELETION_REASON_MISTAKE",
+		2: "DELETION_REASON_ENDED",
+	}
+	DeletionReason_value = map[string]int32{
+		"DELETION_REASON_UNSPECIFIED": 0,
+		"DELETION_REASON_MISTAKE":     1,
+		"DELETION_REASON_ENDED":       2,
+	}
+)
+
+func (x DeletionReason) Enum() *DeletionReason {
+	p := new(DeletionReason)
+	*p = x
+	return p
+}
+
+func (x DeletionReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeletionReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_subscription_v1_subscription_proto_enumTypes[3].Descriptor()
+}
+
+func (DeletionReason) Type() protoreflect.EnumType {
+	return &file_subscription_v1_subscription_proto_enumTypes[3]
+}
+
+func (x DeletionReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeletionReason.Descriptor instead.
+func (DeletionReason) EnumDescriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{3}
+}
+
+type Subscription struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ServiceName      string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price            string                 `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	Currency         string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	UserId           string                 `protobuf:"bytes,5,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	StartDate        string                 `protobuf:"bytes,6,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate          string                 `protobuf:"bytes,7,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	BillingPeriod    BillingPeriod          `protobuf:"varint,8,opt,name=billing_period,json=billingPeriod,proto3,enum=subscription.v1.BillingPeriod" json:"billing_period,omitempty"`
+	TrialEndDate     string                 `protobuf:"bytes,9,opt,name=trial_end_date,json=trialEndDate,proto3" json:"trial_end_date,omitempty"`
+	TaxRate          float64                `protobuf:"fixed64,10,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	PriceIncludesTax bool                   `protobuf:"varint,11,opt,name=price_includes_tax,json=priceIncludesTax,proto3" json:"price_includes_tax,omitempty"`
+	Status           SubscriptionStatus     `protobuf:"varint,12,opt,name=status,proto3,enum=subscription.v1.SubscriptionStatus" json:"status,omitempty"`
+	CancelledAt      *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=cancelled_at,json=cancelledAt,proto3" json:"cancelled_at,omitempty"`
+	EndedAt          *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=ended_at,json=endedAt,proto3" json:"ended_at,omitempty"`
+	Deleted          bool                   `protobuf:"varint,15,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	DeletionReason   DeletionReason         `protobuf:"varint,16,opt,name=deletion_reason,json=deletionReason,proto3,enum=subscription.v1.DeletionReason" json:"deletion_reason,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Subscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscription) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *Subscription) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *Subscription) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Subscription) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Subscription) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *Subscription) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *Subscription) GetBillingPeriod() BillingPeriod {
+	if x != nil {
+		return x.BillingPeriod
+	}
+	return BillingPeriod_BILLING_PERIOD_UNSPECIFIED
+}
+
+func (x *Subscription) GetTrialEndDate() string {
+	if x != nil {
+		return x.TrialEndDate
+	}
+	return ""
+}
+
+func (x *Subscription) GetTaxRate() float64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *Subscription) GetPriceIncludesTax() bool {
+	if x != nil {
+		return x.PriceIncludesTax
+	}
+	return false
+}
+
+func (x *Subscription) GetStatus() SubscriptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED
+}
+
+func (x *Subscription) GetCancelledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CancelledAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetEndedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndedAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *Subscription) GetDeletionReason() DeletionReason {
+	if x != nil {
+		return x.DeletionReason
+	}
+	return DeletionReason_DELETION_REASON_UNSPECIFIED
+}
+
+type SubscriptionInput struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	ServiceName      string                 `protobuf:"bytes,1,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Price            string                 `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Currency         string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	UserId           string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	StartDate        string                 `protobuf:"bytes,5,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	EndDate          string                 `protobuf:"bytes,6,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	BillingPeriod    BillingPeriod          `protobuf:"varint,7,opt,name=billing_period,json=billingPeriod,proto3,enum=subscription.v1.BillingPeriod" json:"billing_period,omitempty"`
+	TrialEndDate     string                 `protobuf:"bytes,8,opt,name=trial_end_date,json=trialEndDate,proto3" json:"trial_end_date,omitempty"`
+	TaxRate          float64                `protobuf:"fixed64,9,opt,name=tax_rate,json=taxRate,proto3" json:"tax_rate,omitempty"`
+	PriceIncludesTax *bool                  `protobuf:"varint,10,opt,name=price_includes_tax,json=priceIncludesTax,proto3,oneof" json:"price_includes_tax,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SubscriptionInput) Reset() {
+	*x = SubscriptionInput{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionInput) ProtoMessage() {}
+
+func (x *SubscriptionInput) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionInput.ProtoReflect.Descriptor instead.
+func (*SubscriptionInput) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SubscriptionInput) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetBillingPeriod() BillingPeriod {
+	if x != nil {
+		return x.BillingPeriod
+	}
+	return BillingPeriod_BILLING_PERIOD_UNSPECIFIED
+}
+
+func (x *SubscriptionInput) GetTrialEndDate() string {
+	if x != nil {
+		return x.TrialEndDate
+	}
+	return ""
+}
+
+func (x *SubscriptionInput) GetTaxRate() float64 {
+	if x != nil {
+		return x.TaxRate
+	}
+	return 0
+}
+
+func (x *SubscriptionInput) GetPriceIncludesTax() bool {
+	if x != nil && x.PriceIncludesTax != nil {
+		return *x.PriceIncludesTax
+	}
+	return false
+}
+
+type CreateSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *SubscriptionInput     `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Strict        bool                   `protobuf:"varint,2,opt,name=strict,proto3" json:"strict,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequest) Reset() {
+	*x = CreateSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateSubscriptionRequest) GetSubscription() *SubscriptionInput {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionRequest) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+type GetSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionRequest) Reset() {
+	*x = GetSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListSubscriptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	Status        SubscriptionStatus     `protobuf:"varint,3,opt,name=status,proto3,enum=subscription.v1.SubscriptionStatus" json:"status,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListSubscriptionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetStatus() SubscriptionStatus {
+	if x != nil {
+		return x.Status
+	}
+	return SubscriptionStatus_SUBSCRIPTION_STATUS_UNSPECIFIED
+}
+
+func (x *ListSubscriptionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSubscriptionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+func (x *ListSubscriptionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type UpdateSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Subscription  *SubscriptionInput     `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	Strict        bool                   `protobuf:"varint,3,opt,name=strict,proto3" json:"strict,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionRequest) Reset() {
+	*x = UpdateSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionRequest) ProtoMessage() {}
+
+func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionRequest) GetSubscription() *SubscriptionInput {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *UpdateSubscriptionRequest) GetStrict() bool {
+	if x != nil {
+		return x.Strict
+	}
+	return false
+}
+
+type DeleteSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        DeletionReason         `protobuf:"varint,2,opt,name=reason,proto3,enum=subscription.v1.DeletionReason" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionRequest) Reset() {
+	*x = DeleteSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeleteSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteSubscriptionRequest) GetReason() DeletionReason {
+	if x != nil {
+		return x.Reason
+	}
+	return DeletionReason_DELETION_REASON_UNSPECIFIED
+}
+
+type RestoreSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreSubscriptionRequest) Reset() {
+	*x = RestoreSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreSubscriptionRequest) ProtoMessage() {}
+
+func (x *RestoreSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*RestoreSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RestoreSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CancelSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mode          CancelMode             `protobuf:"varint,2,opt,name=mode,proto3,enum=subscription.v1.CancelMode" json:"mode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelSubscriptionRequest) Reset() {
+	*x = CancelSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelSubscriptionRequest) ProtoMessage() {}
+
+func (x *CancelSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CancelSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CancelSubscriptionRequest) GetMode() CancelMode {
+	if x != nil {
+		return x.Mode
+	}
+	return CancelMode_CANCEL_MODE_UNSPECIFIED
+}
+
+type ReactivateSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReactivateSubscriptionRequest) Reset() {
+	*x = ReactivateSubscriptionRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactivateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactivateSubscriptionRequest) ProtoMessage() {}
+
+func (x *ReactivateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactivateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*ReactivateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ReactivateSubscriptionRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type CalculateTotalCostRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName    string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	From           string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	To             string                 `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	IncludeDeleted *bool                  `protobuf:"varint,5,opt,name=include_deleted,json=includeDeleted,proto3,oneof" json:"include_deleted,omitempty"`
+	Currency       string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CalculateTotalCostRequest) Reset() {
+	*x = CalculateTotalCostRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CalculateTotalCostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CalculateTotalCostRequest) ProtoMessage() {}
+
+func (x *CalculateTotalCostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CalculateTotalCostRequest.ProtoReflect.Descriptor instead.
+func (*CalculateTotalCostRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CalculateTotalCostRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CalculateTotalCostRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *CalculateTotalCostRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *CalculateTotalCostRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *CalculateTotalCostRequest) GetIncludeDeleted() bool {
+	if x != nil && x.IncludeDeleted != nil {
+		return *x.IncludeDeleted
+	}
+	return false
+}
+
+func (x *CalculateTotalCostRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CostBreakdown struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	BeforeDiscount string                 `protobuf:"bytes,1,opt,name=before_discount,json=beforeDiscount,proto3" json:"before_discount,omitempty"`
+	Discount       string                 `protobuf:"bytes,2,opt,name=discount,proto3" json:"discount,omitempty"`
+	Net            string                 `protobuf:"bytes,3,opt,name=net,proto3" json:"net,omitempty"`
+	Tax            string                 `protobuf:"bytes,4,opt,name=tax,proto3" json:"tax,omitempty"`
+	Gross          string                 `protobuf:"bytes,5,opt,name=gross,proto3" json:"gross,omitempty"`
+	Currency       string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CostBreakdown) Reset() {
+	*x = CostBreakdown{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CostBreakdown) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CostBreakdown) ProtoMessage() {}
+
+func (x *CostBreakdown) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CostBreakdown.ProtoReflect.Descriptor instead.
+func (*CostBreakdown) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CostBreakdown) GetBeforeDiscount() string {
+	if x != nil {
+		return x.BeforeDiscount
+	}
+	return ""
+}
+
+func (x *CostBreakdown) GetDiscount() string {
+	if x != nil {
+		return x.Discount
+	}
+	return ""
+}
+
+func (x *CostBreakdown) GetNet() string {
+	if x != nil {
+		return x.Net
+	}
+	return ""
+}
+
+func (x *CostBreakdown) GetTax() string {
+	if x != nil {
+		return x.Tax
+	}
+	return ""
+}
+
+func (x *CostBreakdown) GetGross() string {
+	if x != nil {
+		return x.Gross
+	}
+	return ""
+}
+
+func (x *CostBreakdown) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ForecastCostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ServiceName   string                 `protobuf:"bytes,2,opt,name=service_name,json=serviceName,proto3" json:"service_name,omitempty"`
+	From          string                 `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	Months        int32                  `protobuf:"varint,4,opt,name=months,proto3" json:"months,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForecastCostRequest) Reset() {
+	*x = ForecastCostRequest{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForecastCostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastCostRequest) ProtoMessage() {}
+
+func (x *ForecastCostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastCostRequest.ProtoReflect.Descriptor instead.
+func (*ForecastCostRequest) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ForecastCostRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ForecastCostRequest) GetServiceName() string {
+	if x != nil {
+		return x.ServiceName
+	}
+	return ""
+}
+
+func (x *ForecastCostRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ForecastCostRequest) GetMonths() int32 {
+	if x != nil {
+		return x.Months
+	}
+	return 0
+}
+
+func (x *ForecastCostRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type MonthlyCost struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Month         string                 `protobuf:"bytes,1,opt,name=month,proto3" json:"month,omitempty"`
+	Committed     string                 `protobuf:"bytes,2,opt,name=committed,proto3" json:"committed,omitempty"`
+	Projected     string                 `protobuf:"bytes,3,opt,name=projected,proto3" json:"projected,omitempty"`
+	Total         string                 `protobuf:"bytes,4,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MonthlyCost) Reset() {
+	*x = MonthlyCost{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MonthlyCost) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MonthlyCost) ProtoMessage() {}
+
+func (x *MonthlyCost) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MonthlyCost.ProtoReflect.Descriptor instead.
+func (*MonthlyCost) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *MonthlyCost) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *MonthlyCost) GetCommitted() string {
+	if x != nil {
+		return x.Committed
+	}
+	return ""
+}
+
+func (x *MonthlyCost) GetProjected() string {
+	if x != nil {
+		return x.Projected
+	}
+	return ""
+}
+
+func (x *MonthlyCost) GetTotal() string {
+	if x != nil {
+		return x.Total
+	}
+	return ""
+}
+
+type ForecastCostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          string                 `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	Total         string                 `protobuf:"bytes,3,opt,name=total,proto3" json:"total,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Months        []*MonthlyCost         `protobuf:"bytes,5,rep,name=months,proto3" json:"months,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForecastCostResponse) Reset() {
+	*x = ForecastCostResponse{}
+	mi := &file_subscription_v1_subscription_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForecastCostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForecastCostResponse) ProtoMessage() {}
+
+func (x *ForecastCostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscription_v1_subscription_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForecastCostResponse.ProtoReflect.Descriptor instead.
+func (*ForecastCostResponse) Descriptor() ([]byte, []int) {
+	return file_subscription_v1_subscription_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ForecastCostResponse) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *ForecastCostResponse) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *ForecastCostResponse) GetTotal() string {
+	if x != nil {
+		return x.Total
+	}
+	return ""
+}
+
+func (x *ForecastCostResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ForecastCostResponse) GetMonths() []*MonthlyCost {
+	if x != nil {
+		return x.Months
+	}
+	return nil
+}
+
+var File_subscription_v1_subscription_proto protoreflect.FileDescriptor
+
+const file_subscription_v1_subscription_proto_rawDesc = "" +
+	"\n" +
+	"\"subscription/v1/subscription.proto\x12\x0fsubscription.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x93\x05\n" +
+	"\fSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\tR\x05price\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x17\n" +
+	"\auser_id\x18\x05 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x06 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\a \x01(\tR\aendDate\x12E\n" +
+	"\x0ebilling_period\x18\b \x01(\x0e2\x1e.subscription.v1.BillingPeriodR\rbillingPeriod\x12$\n" +
+	"\x0etrial_end_date\x18\t \x01(\tR\ftrialEndDate\x12\x19\n" +
+	"\btax_rate\x18\n" +
+	" \x01(\x01R\ataxRate\x12,\n" +
+	"\x12price_includes_tax\x18\v \x01(\bR\x10priceIncludesTax\x12;\n" +
+	"\x06status\x18\f \x01(\x0e2#.subscription.v1.SubscriptionStatusR\x06status\x12=\n" +
+	"\fcancelled_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\vcancelledAt\x125\n" +
+	"\bended_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\aendedAt\x12\x18\n" +
+	"\adeleted\x18\x0f \x01(\bR\adeleted\x12H\n" +
+	"\x0fdeletion_reason\x18\x10 \x01(\x0e2\x1f.subscription.v1.DeletionReasonR\x0edeletionReason\"\x8d\x03\n" +
+	"\x11SubscriptionInput\x12!\n" +
+	"\fservice_name\x18\x01 \x01(\tR\vserviceName\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\tR\x05price\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x05 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x06 \x01(\tR\aendDate\x12E\n" +
+	"\x0ebilling_period\x18\a \x01(\x0e2\x1e.subscription.v1.BillingPeriodR\rbillingPeriod\x12$\n" +
+	"\x0etrial_end_date\x18\b \x01(\tR\ftrialEndDate\x12\x19\n" +
+	"\btax_rate\x18\t \x01(\x01R\ataxRate\x121\n" +
+	"\x12price_includes_tax\x18\n" +
+	" \x01(\bH\x00R\x10priceIncludesTax\x88\x01\x01B\x15\n" +
+	"\x13_price_includes_tax\"{\n" +
+	"\x19CreateSubscriptionRequest\x12F\n" +
+	"\fsubscription\x18\x01 \x01(\v2\".subscription.v1.SubscriptionInputR\fsubscription\x12\x16\n" +
+	"\x06strict\x18\x02 \x01(\bR\x06strict\"(\n" +
+	"\x16GetSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xcf\x01\n" +
+	"\x18ListSubscriptionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12;\n" +
+	"\x06status\x18\x03 \x01(\x0e2#.subscription.v1.SubscriptionStatusR\x06status\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"\x88\x01\n" +
+	"\x19ListSubscriptionsResponse\x12C\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1d.subscription.v1.SubscriptionR\rsubscriptions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\x8b\x01\n" +
+	"\x19UpdateSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12F\n" +
+	"\fsubscription\x18\x02 \x01(\v2\".subscription.v1.SubscriptionInputR\fsubscription\x12\x16\n" +
+	"\x06strict\x18\x03 \x01(\bR\x06strict\"d\n" +
+	"\x19DeleteSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x127\n" +
+	"\x06reason\x18\x02 \x01(\x0e2\x1f.subscription.v1.DeletionReasonR\x06reason\",\n" +
+	"\x1aRestoreSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\\\n" +
+	"\x19CancelSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12/\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x1b.subscription.v1.CancelModeR\x04mode\"/\n" +
+	"\x1dReactivateSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xd9\x01\n" +
+	"\x19CalculateTotalCostRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\tR\x02to\x12,\n" +
+	"\x0finclude_deleted\x18\x05 \x01(\bH\x00R\x0eincludeDeleted\x88\x01\x01\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrencyB\x12\n" +
+	"\x10_include_deleted\"\xaa\x01\n" +
+	"\rCostBreakdown\x12'\n" +
+	"\x0fbefore_discount\x18\x01 \x01(\tR\x0ebeforeDiscount\x12\x1a\n" +
+	"\bdiscount\x18\x02 \x01(\tR\bdiscount\x12\x10\n" +
+	"\x03net\x18\x03 \x01(\tR\x03net\x12\x10\n" +
+	"\x03tax\x18\x04 \x01(\tR\x03tax\x12\x14\n" +
+	"\x05gross\x18\x05 \x01(\tR\x05gross\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\"\x99\x01\n" +
+	"\x13ForecastCostRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fservice_name\x18\x02 \x01(\tR\vserviceName\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\tR\x04from\x12\x16\n" +
+	"\x06months\x18\x04 \x01(\x05R\x06months\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"u\n" +
+	"\vMonthlyCost\x12\x14\n" +
+	"\x05month\x18\x01 \x01(\tR\x05month\x12\x1c\n" +
+	"\tcommitted\x18\x02 \x01(\tR\tcommitted\x12\x1c\n" +
+	"\tprojected\x18\x03 \x01(\tR\tprojected\x12\x14\n" +
+	"\x05total\x18\x04 \x01(\tR\x05total\"\xa2\x01\n" +
+	"\x14ForecastCostResponse\x12\x12\n" +
+	"\x04from\x18\x01 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x02 \x01(\tR\x02to\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\tR\x05total\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x124\n" +
+	"\x06months\x18\x05 \x03(\v2\x1c.subscription.v1.MonthlyCostR\x06months*\x84\x01\n" +
+	"\rBillingPeriod\x12\x1e\n" +
+	"\x1aBILLING_PERIOD_UNSPECIFIED\x10\x00\x12\x1a\n" +
+	"\x16BILLING_PERIOD_MONTHLY\x10\x01\x12\x1c\n" +
+	"\x18BILLING_PERIOD_QUARTERLY\x10\x02\x12\x19\n" +
+	"\x15BILLING_PERIOD_YEARLY\x10\x03*\xdf\x01\n" +
+	"\x12SubscriptionStatus\x12#\n" +
+	"\x1fSUBSCRIPTION_STATUS_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dSUBSCRIPTION_STATUS_SCHEDULED\x10\x01\x12\x1d\n" +
+	"\x19SUBSCRIPTION_STATUS_TRIAL\x10\x02\x12\x1e\n" +
+	"\x1aSUBSCRIPTION_STATUS_ACTIVE\x10\x03\x12\x1f\n" +
+	"\x1bSUBSCRIPTION_STATUS_EXPIRED\x10\x04\x12!\n" +
+	"\x1dSUBSCRIPTION_STATUS_CANCELLED\x10\x05*e\n" +
+	"\n" +
+	"CancelMode\x12\x1b\n" +
+	"\x17CANCEL_MODE_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19CANCEL_MODE_AT_PERIOD_END\x10\x01\x12\x1b\n" +
+	"\x17CANCEL_MODE_IMMEDIATELY\x10\x02*i\n" +
+	"\x0eDeletionReason\x12\x1f\n" +
+	"\x1bDELETION_REASON_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17DELETION_REASON_MISTAKE\x10\x01\x12\x19\n" +
+	"\x15DELETION_REASON_ENDED\x10\x022\xe4\a\n" +
+	"\x13SubscriptionService\x12_\n" +
+	"\x12CreateSubscription\x12*.subscription.v1.CreateSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12Y\n" +
+	"\x0fGetSubscription\x12'.subscription.v1.GetSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12j\n" +
+	"\x11ListSubscriptions\x12).subscription.v1.ListSubscriptionsRequest\x1a*.subscription.v1.ListSubscriptionsResponse\x12_\n" +
+	"\x12UpdateSubscription\x12*.subscription.v1.UpdateSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12X\n" +
+	"\x12DeleteSubscription\x12*.subscription.v1.DeleteSubscriptionRequest\x1a\x16.google.protobuf.Empty\x12a\n" +
+	"\x13RestoreSubscription\x12+.subscription.v1.RestoreSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12_\n" +
+	"\x12CancelSubscription\x12*.subscription.v1.CancelSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12g\n" +
+	"\x16ReactivateSubscription\x12..subscription.v1.ReactivateSubscriptionRequest\x1a\x1d.subscription.v1.Subscription\x12`\n" +
+	"\x12CalculateTotalCost\x12*.subscription.v1.CalculateTotalCostRequest\x1a\x1e.subscription.v1.CostBreakdown\x12[\n" +
+	"\fForecastCost\x12$.subscription.v1.ForecastCostRequest\x1a%.subscription.v1.ForecastCostResponseBVZTgithub.com/Babushkin05/subscription-organizer/pkg/api/subscription/v1;subscriptionv1b\x06proto3"
+
+var (
+	file_subscription_v1_subscription_proto_rawDescOnce sync.Once
+	file_subscription_v1_subscription_proto_rawDescData []byte
+)
+
+func file_subscription_v1_subscription_proto_rawDescGZIP() []byte {
+	file_subscription_v1_subscription_proto_rawDescOnce.Do(func() {
+		file_subscription_v1_subscription_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_subscription_v1_subscription_proto_rawDesc), len(file_subscription_v1_subscription_proto_rawDesc)))
+	})
+	return file_subscription_v1_subscription_proto_rawDescData
+}
+
+var file_subscription_v1_subscription_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_subscription_v1_subscription_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_subscription_v1_subscription_proto_goTypes = []any{
+	(BillingPeriod)(0),                    // 0: subscription.v1.BillingPeriod
+	(SubscriptionStatus)(0),               // 1: subscription.v1.SubscriptionStatus
+	(CancelMode)(0),                       // 2: subscription.v1.CancelMode
+	(DeletionReason)(0),                   // 3: subscription.v1.DeletionReason
+	(*Subscription)(nil),                  // 4: subscription.v1.Subscription
+	(*SubscriptionInput)(nil),             // 5: subscription.v1.SubscriptionInput
+	(*CreateSubscriptionRequest)(nil),     // 6: subscription.v1.CreateSubscriptionRequest
+	(*GetSubscriptionRequest)(nil),        // 7: subscription.v1.GetSubscriptionRequest
+	(*ListSubscriptionsRequest)(nil),      // 8: subscription.v1.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),     // 9: subscription.v1.ListSubscriptionsResponse
+	(*UpdateSubscriptionRequest)(nil),     // 10: subscription.v1.UpdateSubscriptionRequest
+	(*DeleteSubscriptionRequest)(nil),     // 11: subscription.v1.DeleteSubscriptionRequest
+	(*RestoreSubscriptionRequest)(nil),    // 12: subscription.v1.RestoreSubscriptionRequest
+	(*CancelSubscriptionRequest)(nil),     // 13: subscription.v1.CancelSubscriptionRequest
+	(*ReactivateSubscriptionRequest)(nil), // 14: subscription.v1.ReactivateSubscriptionRequest
+	(*CalculateTotalCostRequest)(nil),     // 15: subscription.v1.CalculateTotalCostRequest
+	(*CostBreakdown)(nil),                 // 16: subscription.v1.CostBreakdown
+	(*ForecastCostRequest)(nil),           // 17: subscription.v1.ForecastCostRequest
+	(*MonthlyCost)(nil),                   // 18: subscription.v1.MonthlyCost
+	(*ForecastCostResponse)(nil),          // 19: subscription.v1.ForecastCostResponse
+	(*timestamppb.Timestamp)(nil),         // 20: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),                 // 21: google.protobuf.Empty
+}
+var file_subscription_v1_subscription_proto_depIdxs = []int32{
+	0,  // 0: subscription.v1.Subscription.billing_period:type_name -> subscription.v1.BillingPeriod
+	1,  // 1: subscription.v1.Subscription.status:type_name -> subscription.v1.SubscriptionStatus
+	20, // 2: subscription.v1.Subscription.cancelled_at:type_name -> google.protobuf.Timestamp
+	20, // 3: subscription.v1.Subscription.ended_at:type_name -> google.protobuf.Timestamp
+	3,  // 4: subscription.v1.Subscription.deletion_reason:type_name -> subscription.v1.DeletionReason
+	0,  // 5: subscription.v1.SubscriptionInput.billing_period:type_name -> subscription.v1.BillingPeriod
+	5,  // 6: subscription.v1.CreateSubscriptionRequest.subscription:type_name -> subscription.v1.SubscriptionInput
+	1,  // 7: subscription.v1.ListSubscriptionsRequest.status:type_name -> subscription.v1.SubscriptionStatus
+	4,  // 8: subscription.v1.ListSubscriptionsResponse.subscriptions:type_name -> subscription.v1.Subscription
+	5,  // 9: subscription.v1.UpdateSubscriptionRequest.subscription:type_name -> subscription.v1.SubscriptionInput
+	3,  // 10: subscription.v1.DeleteSubscriptionRequest.reason:type_name -> subscription.v1.DeletionReason
+	2,  // 11: subscription.v1.CancelSubscriptionRequest.mode:type_name -> subscription.v1.CancelMode
+	18, // 12: subscription.v1.ForecastCostResponse.months:type_name -> subscription.v1.MonthlyCost
+	6,  // 13: subscription.v1.SubscriptionService.CreateSubscription:input_type -> subscription.v1.CreateSubscriptionRequest
+	7,  // 14: subscription.v1.SubscriptionService.GetSubscription:input_type -> subscription.v1.GetSubscriptionRequest
+	8,  // 15: subscription.v1.SubscriptionService.ListSubscriptions:input_type -> subscription.v1.ListSubscriptionsRequest
+	10, // 16: subscription.v1.SubscriptionService.UpdateSubscription:input_type -> subscription.v1.UpdateSubscriptionRequest
+	11, // 17: subscription.v1.SubscriptionService.DeleteSubscription:input_type -> subscription.v1.DeleteSubscriptionRequest
+	12, // 18: subscription.v1.SubscriptionService.RestoreSubscription:input_type -> subscription.v1.RestoreSubscriptionRequest
+	13, // 19: subscription.v1.SubscriptionService.CancelSubscription:input_type -> subscription.v1.CancelSubscriptionRequest
+	14, // 20: subscription.v1.SubscriptionService.ReactivateSubscription:input_type -> subscription.v1.ReactivateSubscriptionRequest
+	15, // 21: subscription.v1.SubscriptionService.CalculateTotalCost:input_type -> subscription.v1.CalculateTotalCostRequest
+	17, // 22: subscription.v1.SubscriptionService.ForecastCost:input_type -> subscription.v1.ForecastCostRequest
+	4,  // 23: subscription.v1.SubscriptionService.CreateSubscription:output_type -> subscription.v1.Subscription
+	4,  // 24: subscription.v1.SubscriptionService.GetSubscription:output_type -> subscription.v1.Subscription
+	9,  // 25: subscription.v1.SubscriptionService.ListSubscriptions:output_type -> subscription.v1.ListSubscriptionsResponse
+	4,  // 26: subscription.v1.SubscriptionService.UpdateSubscription:output_type -> subscription.v1.Subscription
+	21, // 27: subscription.v1.SubscriptionService.DeleteSubscription:output_type -> google.protobuf.Empty
+	4,  // 28: subscription.v1.SubscriptionService.RestoreSubscription:output_type -> subscription.v1.Subscription
+	4,  // 29: subscription.v1.SubscriptionService.CancelSubscription:output_type -> subscription.v1.Subscription
+	4,  // 30: subscription.v1.SubscriptionService.ReactivateSubscription:output_type -> subscription.v1.Subscription
+	16, // 31: subscription.v1.SubscriptionService.CalculateTotalCost:output_type -> subscription.v1.CostBreakdown
+	19, // 32: subscription.v1.SubscriptionService.ForecastCost:output_type -> subscription.v1.ForecastCostResponse
+	23, // [23:33] is the sub-list for method output_type
+	13, // [13:23] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
+}
+
+func init() { file_subscription_v1_subscription_proto_init() }
+func file_subscription_v1_subscription_proto_init() {
+	if File_subscription_v1_subscription_proto != nil {
+		return
+	}
+	file_subscription_v1_subscription_proto_msgTypes[1].OneofWrappers = []any{}
+	file_subscription_v1_subscription_proto_msgTypes[11].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_subscription_v1_subscription_proto_rawDesc), len(file_subscription_v1_subscription_proto_rawDesc)),
+			NumEnums:      4,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_subscription_v1_subscription_proto_goTypes,
+		DependencyIndexes: file_subscription_v1_subscription_proto_depIdxs,
+		EnumInfos:         file_subscription_v1_subscription_proto_enumTypes,
+		MessageInfos:      file_subscription_v1_subscription_proto_msgTypes,
+	}.Build()
+	File_subscription_v1_subscription_proto = out.File
+	file_subscription_v1_subscription_proto_goTypes = nil
+	file_subscription_v1_subscription_proto_depIdxs = nil
+}